@@ -16,7 +16,9 @@ import (
 	"github.com/letsssgooo/quizBot/internal/events/engine"
 	"github.com/letsssgooo/quizBot/internal/events/fetcher"
 	"github.com/letsssgooo/quizBot/internal/events/sender"
+	"github.com/letsssgooo/quizBot/internal/integrity"
 	"github.com/letsssgooo/quizBot/internal/lib/slogcustom"
+	"github.com/letsssgooo/quizBot/internal/ratelimit"
 	"github.com/letsssgooo/quizBot/internal/storage/postgres"
 	"golang.org/x/sync/errgroup"
 )
@@ -70,7 +72,14 @@ func main() {
 	slog.Debug("Creating bot entities...")
 
 	httpClient := client.NewHTTPClient(token)
-	botAuth := auth.NewBotAuth()
+	authConfig, err := auth.ConfigFromEnv(os.LookupEnv)
+	if err != nil {
+		slog.Error("Cannot parse lecturer verification settings, using defaults", "error", err)
+
+		authConfig = auth.DefaultConfig()
+	}
+
+	botAuth := auth.NewBotAuth(authConfig)
 	quizEngine := engine.NewEngine()
 	telegramFetcher := fetcher.NewTelegramFetcher(gCtx, httpClient)
 	telegramSender := sender.NewTelegramSender(httpClient)
@@ -88,6 +97,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	limits, err := ratelimit.ConfigFromEnv(os.LookupEnv)
+	if err != nil {
+		slog.Error("Cannot parse rate limits, using defaults", "error", err)
+
+		limits = ratelimit.DefaultConfig()
+	}
+
+	integrityConfig, err := integrity.ConfigFromEnv(os.LookupEnv)
+	if err != nil {
+		slog.Error("Cannot parse integrity thresholds, using defaults", "error", err)
+
+		integrityConfig = integrity.DefaultConfig()
+	}
+
 	telegramBot := bot.NewBot(
 		httpClient,
 		botAuth,
@@ -95,6 +118,8 @@ func main() {
 		telegramSender,
 		quizEngine,
 		botStorage,
+		ratelimit.New(limits, nil),
+		integrityConfig,
 		botUsername,
 	)
 
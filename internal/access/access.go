@@ -0,0 +1,93 @@
+// Package access описывает роли, с которыми владелец квиза открывает его другим преподавателям
+// и ассистентам, и права, которые эти роли дают.
+package access
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrUnknownRole возвращается, если роль не распознана.
+var ErrUnknownRole = errors.New("unknown share role")
+
+// Role — роль пользователя в квизе.
+type Role string
+
+// Роли в квизе. Каждая следующая включает права предыдущей.
+const (
+	RoleNone   Role = ""
+	RoleViewer Role = "viewer" // видит квиз, историю запусков и результаты
+	RoleRunner Role = "runner" // проводит запуски
+	RoleEditor Role = "editor" // проверяет открытые ответы и перепроверяет вопросы
+	RoleOwner  Role = "owner"  // владелец: удаляет квиз и управляет доступом
+)
+
+// Permission — действие над квизом или его запуском.
+type Permission int
+
+// Права на квиз.
+const (
+	PermView   Permission = iota // смотреть квиз, историю и результаты, получать итоги запусков
+	PermRun                      // запускать квиз и управлять запуском: начать, продолжить, отменить
+	PermEdit                     // менять результаты: проверка открытых ответов, /regrade
+	PermManage                   // удалить квиз, выдать и отозвать доступ
+)
+
+// levels — права, которые даёт роль: роль даёт все права до своего уровня включительно.
+var levels = map[Role]Permission{
+	RoleViewer: PermView,
+	RoleRunner: PermRun,
+	RoleEditor: PermEdit,
+	RoleOwner:  PermManage,
+}
+
+// names — названия ролей для пользователей.
+var names = map[Role]string{
+	RoleViewer: "наблюдатель",
+	RoleRunner: "ведущий",
+	RoleEditor: "редактор",
+	RoleOwner:  "владелец",
+}
+
+// ShareableRoles — роли, которые владелец может выдать, в порядке возрастания прав.
+var ShareableRoles = []Role{RoleViewer, RoleRunner, RoleEditor}
+
+// ParseRole распознаёт выдаваемую роль по английскому или русскому названию без учёта регистра.
+func ParseRole(s string) (Role, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	for _, role := range ShareableRoles {
+		if s == string(role) || s == names[role] {
+			return role, nil
+		}
+	}
+
+	return RoleNone, ErrUnknownRole
+}
+
+// Can сообщает, даёт ли роль право perm.
+func (r Role) Can(perm Permission) bool {
+	level, ok := levels[r]
+
+	return ok && perm <= level
+}
+
+// CanInRun сообщает, даёт ли роль право perm над запуском квиза. Тот, кто запустил квиз, ведёт запуск
+// до конца, даже если доступ к квизу у него уже отозвали, но менять результаты может только с ролью,
+// которая это разрешает.
+func (r Role) CanInRun(perm Permission, starter bool) bool {
+	if starter && perm <= PermRun {
+		return true
+	}
+
+	return r.Can(perm)
+}
+
+// Name возвращает название роли для пользователя.
+func (r Role) Name() string {
+	if name, ok := names[r]; ok {
+		return name
+	}
+
+	return string(r)
+}
@@ -0,0 +1,61 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRole(t *testing.T) {
+	tests := map[string]Role{
+		"viewer":      RoleViewer,
+		" Runner ":    RoleRunner,
+		"редактор":    RoleEditor,
+		"Наблюдатель": RoleViewer,
+		"ведущий":     RoleRunner,
+	}
+
+	for input, want := range tests {
+		role, err := ParseRole(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, role, input)
+	}
+
+	for _, input := range []string{"", "owner", "владелец", "admin"} {
+		_, err := ParseRole(input)
+		assert.ErrorIs(t, err, ErrUnknownRole, input)
+	}
+}
+
+func TestRole_Can(t *testing.T) {
+	assert.True(t, RoleViewer.Can(PermView))
+	assert.False(t, RoleViewer.Can(PermRun))
+
+	assert.True(t, RoleRunner.Can(PermView))
+	assert.True(t, RoleRunner.Can(PermRun))
+	assert.False(t, RoleRunner.Can(PermEdit))
+
+	assert.True(t, RoleEditor.Can(PermEdit))
+	assert.False(t, RoleEditor.Can(PermManage))
+
+	assert.True(t, RoleOwner.Can(PermManage))
+
+	assert.False(t, RoleNone.Can(PermView))
+	assert.False(t, Role("unknown").Can(PermView))
+}
+
+func TestRole_CanInRun(t *testing.T) {
+	// ведущий, запустивший квиз, управляет запуском, но не проверяет ответы и не перепроверяет вопросы
+	assert.True(t, RoleRunner.CanInRun(PermRun, true))
+	assert.False(t, RoleRunner.CanInRun(PermEdit, true))
+
+	// доступ отозван во время запуска: запуск можно довести до конца, но не менять результаты
+	assert.True(t, RoleNone.CanInRun(PermRun, true))
+	assert.False(t, RoleNone.CanInRun(PermEdit, true))
+	assert.False(t, RoleNone.CanInRun(PermRun, false))
+
+	assert.True(t, RoleEditor.CanInRun(PermEdit, true))
+	assert.True(t, RoleEditor.CanInRun(PermEdit, false))
+	assert.False(t, RoleViewer.CanInRun(PermRun, false))
+}
@@ -0,0 +1,136 @@
+// Package analytics считает сводные показатели по сохранённым итогам запусков квизов:
+// средний балл, долю дошедших до конца участников, разницу в ответах между двумя запусками,
+// личную статистику студента и журнал курса.
+package analytics
+
+import (
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+// RunSummary — сводка по одному запуску.
+type RunSummary struct {
+	Run            *models.RunModel
+	Participants   int
+	MeanScore      float64
+	CompletionRate float64 // доля участников, ответивших на все вопросы
+	HasResults     bool    // итоги сохранены, то есть запуск завершён
+}
+
+// QuestionDelta — доля правильных ответов на один вопрос в двух запусках.
+// Вопросы сопоставляются по тексту, потому что порядок вопросов в запусках может быть перемешан.
+type QuestionDelta struct {
+	Text  string
+	IdxA  int // номер вопроса в первом запуске или -1, если его там нет
+	IdxB  int // номер вопроса во втором запуске или -1, если его там нет
+	RateA float64
+	RateB float64
+}
+
+// Delta возвращает изменение доли правильных ответов от первого запуска ко второму.
+func (d QuestionDelta) Delta() float64 {
+	return d.RateB - d.RateA
+}
+
+// InBoth сообщает, есть ли вопрос в обоих запусках.
+func (d QuestionDelta) InBoth() bool {
+	return d.IdxA >= 0 && d.IdxB >= 0
+}
+
+// Summarize считает сводку по запуску. results может быть nil, если итоги ещё не сохранены.
+func Summarize(run *models.RunModel, results *models.RunResultsModel) RunSummary {
+	summary := RunSummary{
+		Run:          run,
+		Participants: run.ParticipantsCount,
+	}
+
+	if results == nil || len(results.Questions) == 0 {
+		return summary
+	}
+
+	summary.HasResults = true
+	summary.Participants = len(results.Participants)
+
+	if summary.Participants == 0 {
+		return summary
+	}
+
+	totalScore, completed := 0, 0
+
+	for _, participant := range results.Participants {
+		totalScore += participant.Score
+
+		if participant.AnsweredCount >= len(results.Questions) {
+			completed++
+		}
+	}
+
+	summary.MeanScore = float64(totalScore) / float64(summary.Participants)
+	summary.CompletionRate = float64(completed) / float64(summary.Participants)
+
+	return summary
+}
+
+// CorrectRate возвращает долю участников запуска, правильно ответивших на вопрос.
+// Не ответившие участники считаются ответившими неверно.
+func CorrectRate(question *models.RunQuestionModel, participants int) float64 {
+	if participants == 0 {
+		return 0
+	}
+
+	return float64(question.Correct) / float64(participants)
+}
+
+// Compare сопоставляет вопросы двух запусков. Сначала идут вопросы в порядке первого запуска,
+// затем вопросы, которые есть только во втором. Аннулированные вопросы не сравниваются.
+func Compare(a, b *models.RunResultsModel) []QuestionDelta {
+	// вопросы с одинаковым текстом сопоставляются по порядку
+	indicesB := make(map[string][]int)
+
+	for i, question := range b.Questions {
+		if !question.Voided {
+			indicesB[question.QuestionText] = append(indicesB[question.QuestionText], i)
+		}
+	}
+
+	matchedB := make(map[int]bool)
+	deltas := make([]QuestionDelta, 0, len(a.Questions))
+
+	for _, question := range a.Questions {
+		if question.Voided {
+			continue
+		}
+
+		delta := QuestionDelta{
+			Text:  question.QuestionText,
+			IdxA:  question.QuestionIdx,
+			IdxB:  -1,
+			RateA: CorrectRate(question, len(a.Participants)),
+		}
+
+		if candidates := indicesB[question.QuestionText]; len(candidates) > 0 {
+			matched := b.Questions[candidates[0]]
+			indicesB[question.QuestionText] = candidates[1:]
+			matchedB[candidates[0]] = true
+
+			delta.IdxB = matched.QuestionIdx
+			delta.RateB = CorrectRate(matched, len(b.Participants))
+		}
+
+		deltas = append(deltas, delta)
+	}
+
+	for i, question := range b.Questions {
+		if question.Voided || matchedB[i] {
+			continue
+		}
+
+		deltas = append(deltas, QuestionDelta{
+			Text:  question.QuestionText,
+			IdxA:  -1,
+			IdxB:  question.QuestionIdx,
+			RateB: CorrectRate(question, len(b.Participants)),
+		})
+	}
+
+	return deltas
+}
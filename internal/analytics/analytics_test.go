@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+func TestSummarize(t *testing.T) {
+	run := &models.RunModel{ID: "run", ParticipantsCount: 3}
+
+	// запуск ещё не завершён
+	summary := Summarize(run, nil)
+	assert.False(t, summary.HasResults)
+	assert.Equal(t, 3, summary.Participants)
+
+	results := &models.RunResultsModel{
+		Participants: []*models.RunParticipantModel{
+			{Score: 10, AnsweredCount: 2},
+			{Score: 5, AnsweredCount: 2},
+			{Score: 0, AnsweredCount: 1},
+			{Score: 1, AnsweredCount: 0},
+		},
+		Questions: []*models.RunQuestionModel{
+			{QuestionIdx: 0, QuestionText: "Q1"},
+			{QuestionIdx: 1, QuestionText: "Q2"},
+		},
+	}
+
+	summary = Summarize(run, results)
+	assert.True(t, summary.HasResults)
+	assert.Equal(t, 4, summary.Participants)
+	assert.InDelta(t, 4.0, summary.MeanScore, 1e-9)
+	assert.InDelta(t, 0.5, summary.CompletionRate, 1e-9)
+}
+
+func TestCompare(t *testing.T) {
+	a := &models.RunResultsModel{
+		Participants: make([]*models.RunParticipantModel, 4),
+		Questions: []*models.RunQuestionModel{
+			{QuestionIdx: 0, QuestionText: "Q1", Correct: 4},
+			{QuestionIdx: 1, QuestionText: "Q2", Correct: 1},
+			{QuestionIdx: 2, QuestionText: "Voided", Correct: 0, Voided: true},
+			{QuestionIdx: 3, QuestionText: "Only A", Correct: 2},
+		},
+	}
+
+	// во втором запуске вопросы перемешаны
+	b := &models.RunResultsModel{
+		Participants: make([]*models.RunParticipantModel, 2),
+		Questions: []*models.RunQuestionModel{
+			{QuestionIdx: 0, QuestionText: "Q2", Correct: 2},
+			{QuestionIdx: 1, QuestionText: "Only B", Correct: 1},
+			{QuestionIdx: 2, QuestionText: "Q1", Correct: 1},
+			{QuestionIdx: 3, QuestionText: "Voided", Correct: 2},
+		},
+	}
+
+	deltas := Compare(a, b)
+
+	assert.Equal(t, []QuestionDelta{
+		{Text: "Q1", IdxA: 0, IdxB: 2, RateA: 1, RateB: 0.5},
+		{Text: "Q2", IdxA: 1, IdxB: 0, RateA: 0.25, RateB: 1},
+		{Text: "Only A", IdxA: 3, IdxB: -1, RateA: 0.5},
+		{Text: "Only B", IdxA: -1, IdxB: 1, RateB: 0.5},
+		{Text: "Voided", IdxA: -1, IdxB: 3, RateB: 1},
+	}, deltas)
+
+	assert.InDelta(t, -0.5, deltas[0].Delta(), 1e-9)
+	assert.True(t, deltas[1].InBoth())
+	assert.False(t, deltas[2].InBoth())
+}
@@ -0,0 +1,67 @@
+package analytics
+
+import (
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+// Gradebook — журнал курса: строка на каждого студента, столбец на каждый квиз курса.
+type Gradebook struct {
+	Quizzes []*models.QuizModel
+	Rows    []GradebookRow
+}
+
+// GradebookRow — результаты одного студента по квизам курса.
+type GradebookRow struct {
+	Student  *models.UserModel
+	Scores   []int  // лучший балл по каждому квизу в порядке Gradebook.Quizzes
+	Taken    []bool // проходил ли студент квиз; не пройденный квиз не то же самое, что ноль баллов
+	Attempts int    // всего попыток по квизам курса
+	Passed   int    // сколько квизов курса студент проходил
+	Total    int    // сумма лучших баллов
+}
+
+// BuildGradebook собирает журнал курса из студентов, квизов курса и лучших результатов студентов.
+// Порядок строк и столбцов сохраняется. Результаты по квизам вне курса и студентам вне списка пропускаются.
+func BuildGradebook(
+	students []*models.UserModel,
+	quizzes []*models.QuizModel,
+	results []*models.CourseResultModel,
+) Gradebook {
+	quizIdx := make(map[string]int, len(quizzes))
+	for i, quiz := range quizzes {
+		quizIdx[quiz.ID] = i
+	}
+
+	rows := make([]GradebookRow, len(students))
+	rowIdx := make(map[int64]int, len(students))
+
+	for i, student := range students {
+		rows[i] = GradebookRow{
+			Student: student,
+			Scores:  make([]int, len(quizzes)),
+			Taken:   make([]bool, len(quizzes)),
+		}
+		rowIdx[student.TelegramID] = i
+	}
+
+	for _, result := range results {
+		i, ok := rowIdx[result.TelegramID]
+		if !ok {
+			continue
+		}
+
+		j, ok := quizIdx[result.QuizID]
+		if !ok {
+			continue
+		}
+
+		row := &rows[i]
+		row.Scores[j] = result.BestScore
+		row.Taken[j] = true
+		row.Attempts += result.Attempts
+		row.Passed++
+		row.Total += result.BestScore
+	}
+
+	return Gradebook{Quizzes: quizzes, Rows: rows}
+}
@@ -0,0 +1,43 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+func TestBuildGradebook(t *testing.T) {
+	students := []*models.UserModel{
+		{TelegramID: 1, FullName: "Иванов Иван Иванович"},
+		{TelegramID: 2, FullName: "Петров Пётр Петрович"},
+	}
+	quizzes := []*models.QuizModel{{ID: "q1"}, {ID: "q2"}}
+	results := []*models.CourseResultModel{
+		{TelegramID: 1, QuizID: "q1", BestScore: 8, Attempts: 2},
+		{TelegramID: 1, QuizID: "q2", BestScore: 0, Attempts: 1},
+		{TelegramID: 2, QuizID: "q2", BestScore: 5, Attempts: 1},
+		// результаты студента, который не записан на курс, и квиза вне курса в журнал не попадают
+		{TelegramID: 3, QuizID: "q1", BestScore: 10, Attempts: 1},
+		{TelegramID: 2, QuizID: "q3", BestScore: 10, Attempts: 1},
+	}
+
+	book := BuildGradebook(students, quizzes, results)
+
+	require.Len(t, book.Rows, 2)
+
+	ivanov := book.Rows[0]
+	assert.Equal(t, []int{8, 0}, ivanov.Scores)
+	assert.Equal(t, []bool{true, true}, ivanov.Taken)
+	assert.Equal(t, 3, ivanov.Attempts)
+	assert.Equal(t, 2, ivanov.Passed)
+	assert.Equal(t, 8, ivanov.Total)
+
+	petrov := book.Rows[1]
+	assert.Equal(t, []int{0, 5}, petrov.Scores)
+	assert.Equal(t, []bool{false, true}, petrov.Taken)
+	assert.Equal(t, 1, petrov.Passed)
+	assert.Equal(t, 5, petrov.Total)
+}
@@ -0,0 +1,109 @@
+package analytics
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+// StudentStats — личная статистика студента по завершённым запускам.
+type StudentStats struct {
+	Quizzes        int
+	MeanPercent    float64 // средняя доля набранных баллов от максимума
+	MeanPercentile float64 // средняя доля участников, которых студент опередил
+	Topics         []TopicAccuracy
+	Trend          []MonthStats
+}
+
+// TopicAccuracy — доля правильных ответов студента по теме.
+type TopicAccuracy struct {
+	Topic    string
+	Answered int
+	Correct  int
+	Rate     float64
+}
+
+// MonthStats — результаты студента за один месяц.
+type MonthStats struct {
+	Month       time.Time // первое число месяца
+	Quizzes     int
+	MeanPercent float64
+}
+
+// ScorePercent возвращает долю набранных баллов от максимума. Баллы могут уйти в минус из-за подсказок,
+// поэтому доля ограничена отрезком [0, 1].
+func ScorePercent(run *models.StudentRunModel) float64 {
+	if run.MaxScore <= 0 {
+		return 0
+	}
+
+	return min(max(float64(run.Score)/float64(run.MaxScore), 0), 1)
+}
+
+// Percentile возвращает долю остальных участников запуска, занявших место ниже rank.
+// Единственный участник считается лучшим.
+func Percentile(rank, participants int) float64 {
+	if participants <= 1 {
+		return 1
+	}
+
+	return float64(participants-rank) / float64(participants-1)
+}
+
+// Student считает статистику студента. runs должны быть упорядочены от старых к новым.
+// Запуски без сохранённого максимума баллов учитываются только в количестве квизов.
+// Темы сортируются от самой слабой к самой сильной.
+func Student(runs []*models.StudentRunModel, topics []*models.TopicAccuracyModel) StudentStats {
+	stats := StudentStats{Quizzes: len(runs)}
+
+	scored := 0
+
+	for _, run := range runs {
+		stats.MeanPercentile += Percentile(run.Rank, run.Participants)
+
+		if run.MaxScore <= 0 {
+			continue
+		}
+
+		scored++
+		stats.MeanPercent += ScorePercent(run)
+
+		month := time.Date(run.StartedAt.Year(), run.StartedAt.Month(), 1, 0, 0, 0, 0, run.StartedAt.Location())
+		if len(stats.Trend) == 0 || !stats.Trend[len(stats.Trend)-1].Month.Equal(month) {
+			stats.Trend = append(stats.Trend, MonthStats{Month: month})
+		}
+
+		last := &stats.Trend[len(stats.Trend)-1]
+		last.MeanPercent = (last.MeanPercent*float64(last.Quizzes) + ScorePercent(run)) / float64(last.Quizzes+1)
+		last.Quizzes++
+	}
+
+	if len(runs) > 0 {
+		stats.MeanPercentile /= float64(len(runs))
+	}
+
+	if scored > 0 {
+		stats.MeanPercent /= float64(scored)
+	}
+
+	for _, topic := range topics {
+		if topic.Answered == 0 {
+			continue
+		}
+
+		stats.Topics = append(stats.Topics, TopicAccuracy{
+			Topic:    topic.Topic,
+			Answered: topic.Answered,
+			Correct:  topic.Correct,
+			Rate:     float64(topic.Correct) / float64(topic.Answered),
+		})
+	}
+
+	slices.SortStableFunc(stats.Topics, func(a, b TopicAccuracy) int {
+		return cmp.Or(cmp.Compare(a.Rate, b.Rate), cmp.Compare(a.Topic, b.Topic))
+	})
+
+	return stats
+}
@@ -0,0 +1,65 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+func TestPercentile(t *testing.T) {
+	assert.InDelta(t, 1.0, Percentile(1, 5), 1e-9)
+	assert.InDelta(t, 0.0, Percentile(5, 5), 1e-9)
+	assert.InDelta(t, 0.5, Percentile(3, 5), 1e-9)
+	assert.InDelta(t, 1.0, Percentile(1, 1), 1e-9)
+}
+
+func TestScorePercent(t *testing.T) {
+	assert.InDelta(t, 0.5, ScorePercent(&models.StudentRunModel{Score: 5, MaxScore: 10}), 1e-9)
+	assert.InDelta(t, 0.0, ScorePercent(&models.StudentRunModel{Score: -2, MaxScore: 10}), 1e-9)
+	assert.InDelta(t, 0.0, ScorePercent(&models.StudentRunModel{Score: 3}), 1e-9)
+}
+
+func TestStudent(t *testing.T) {
+	sep := time.Date(2026, time.September, 10, 12, 0, 0, 0, time.UTC)
+	oct := time.Date(2026, time.October, 2, 12, 0, 0, 0, time.UTC)
+
+	runs := []*models.StudentRunModel{
+		{StartedAt: sep, Score: 10, MaxScore: 10, Rank: 1, Participants: 3},
+		{StartedAt: sep.AddDate(0, 0, 7), Score: 5, MaxScore: 10, Rank: 3, Participants: 3},
+		{StartedAt: oct, Score: 2, MaxScore: 8, Rank: 2, Participants: 3},
+		// итоги сохранены до появления max_score
+		{StartedAt: oct.AddDate(0, 0, 1), Score: 4, Rank: 1, Participants: 1},
+	}
+
+	topics := []*models.TopicAccuracyModel{
+		{Topic: "Графы", Answered: 4, Correct: 3},
+		{Topic: "Деревья", Answered: 4, Correct: 1},
+		{Topic: "Пусто", Answered: 0},
+	}
+
+	stats := Student(runs, topics)
+
+	assert.Equal(t, 4, stats.Quizzes)
+	assert.InDelta(t, (1.0+0.5+0.25)/3, stats.MeanPercent, 1e-9)
+	assert.InDelta(t, (1.0+0.0+0.5+1.0)/4, stats.MeanPercentile, 1e-9)
+
+	require.Len(t, stats.Topics, 2)
+	assert.Equal(t, "Деревья", stats.Topics[0].Topic)
+	assert.InDelta(t, 0.25, stats.Topics[0].Rate, 1e-9)
+	assert.Equal(t, "Графы", stats.Topics[1].Topic)
+
+	require.Len(t, stats.Trend, 2)
+	assert.Equal(t, time.September, stats.Trend[0].Month.Month())
+	assert.Equal(t, 2, stats.Trend[0].Quizzes)
+	assert.InDelta(t, 0.75, stats.Trend[0].MeanPercent, 1e-9)
+	assert.Equal(t, 1, stats.Trend[1].Quizzes)
+	assert.InDelta(t, 0.25, stats.Trend[1].MeanPercent, 1e-9)
+
+	empty := Student(nil, nil)
+	assert.Zero(t, empty.Quizzes)
+	assert.Empty(t, empty.Trend)
+}
@@ -11,16 +11,19 @@ import (
 
 // BotAuth реализует Auth
 type BotAuth struct {
-	Roles map[string]struct{}
+	Roles  map[string]struct{}
+	config Config
 }
 
 // NewBotAuth создает BotAuth
-func NewBotAuth() *BotAuth {
+func NewBotAuth(cfg Config) *BotAuth {
 	return &BotAuth{
 		Roles: map[string]struct{}{
 			RoleLecturer: {},
 			RoleStudent:  {},
+			RoleAdmin:    {},
 		},
+		config: cfg,
 	}
 }
 
@@ -62,7 +65,8 @@ func (q *BotAuth) UpdateStudentData(st storage.Storage, telegramID int64, messag
 	return nil
 }
 
-// AddRole добавляет роль у существующего пользотеля
+// AddRole добавляет роль у существующего пользотеля. Роль преподавателя так выдать нельзя:
+// она выдаётся только после подтверждения, см. RequestLecturerRole и VerifyLecturer.
 func (q *BotAuth) AddRole(st storage.Storage, telegramID int64, message string) error {
 	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutAuth)
 	defer cancelFunc()
@@ -76,6 +80,14 @@ func (q *BotAuth) AddRole(st storage.Storage, telegramID int64, message string)
 		return errors.New("invalid role")
 	}
 
+	if role == RoleLecturer {
+		return ErrVerificationRequired
+	}
+
+	if role == RoleAdmin && !q.IsAdmin(telegramID) {
+		return ErrNotAdmin
+	}
+
 	err = st.AddRole(ctx, &models.UserModel{
 		TelegramID: telegramID,
 		Role:       role,
@@ -88,7 +100,14 @@ func (q *BotAuth) AddRole(st storage.Storage, telegramID int64, message string)
 }
 
 // CheckRole возвращает роль у существующего пользователя. Возвращает nil, если роли нет.
+// Роль администратора определяется конфигурацией, а не БД.
 func (q *BotAuth) CheckRole(st storage.Storage, telegramID int64) (*string, error) {
+	if q.IsAdmin(telegramID) {
+		role := RoleAdmin
+
+		return &role, nil
+	}
+
 	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutAuth)
 	defer cancelFunc()
 
@@ -99,5 +118,10 @@ func (q *BotAuth) CheckRole(st storage.Storage, telegramID int64) (*string, erro
 		return nil, err
 	}
 
+	// администратор, убранный из ADMIN_IDS, теряет роль и выбирает её заново
+	if role != nil && *role == RoleAdmin {
+		return nil, nil
+	}
+
 	return role, nil
 }
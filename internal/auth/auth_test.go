@@ -1 +1,249 @@
 package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+func TestParseField(t *testing.T) {
+	value, err := ParseField(FieldName, "  иванов иван-петрович ИВАНОВИЧ ")
+	require.NoError(t, err)
+	assert.Equal(t, "Иванов Иван-петрович Иванович", value)
+
+	_, err = ParseField(FieldName, "Иванов Иван")
+	assert.ErrorIs(t, err, ErrValidation)
+
+	value, err = ParseField(FieldGroup, "ивтб101")
+	require.NoError(t, err)
+	assert.Equal(t, "ИВТБ101", value)
+
+	_, err = ParseField(FieldGroup, "101")
+	assert.ErrorIs(t, err, ErrValidation)
+
+	value, err = ParseField(FieldEmail, "Student@Example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "student@example.com", value)
+
+	_, err = ParseField(FieldEmail, "Студент <student@example.com>")
+	assert.ErrorIs(t, err, ErrValidation)
+
+	value, err = ParseField(FieldStudentID, "21b-0042")
+	require.NoError(t, err)
+	assert.Equal(t, "21B-0042", value)
+
+	_, err = ParseField(FieldStudentID, "12 34")
+	assert.ErrorIs(t, err, ErrValidation)
+
+	value, err = ParseField("university", " МГУ ")
+	require.NoError(t, err)
+	assert.Equal(t, "МГУ", value)
+
+	_, err = ParseField("university", "   ")
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestParseStudentsData(t *testing.T) {
+	data, err := ParseStudentsData([]string{"иванов", "иван", "иванович", "ивтб101"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Иванов Иван Иванович", "ИВТБ101"}, data)
+
+	_, err = ParseStudentsData([]string{"иванов", "иван", "ивтб101"})
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+// fakeStorage хранит роли, подтверждённых преподавателей и коды в памяти.
+type fakeStorage struct {
+	roles    map[int64]string
+	approved map[int64]int64
+	codes    map[string]*models.LecturerCodeModel
+}
+
+func newFakeStorage() *fakeStorage {
+	return &fakeStorage{
+		roles:    make(map[int64]string),
+		approved: make(map[int64]int64),
+		codes:    make(map[string]*models.LecturerCodeModel),
+	}
+}
+
+func (s *fakeStorage) CreateUser(_ context.Context, _ *models.UserModel) error { return nil }
+
+func (s *fakeStorage) UpdateStudentData(_ context.Context, _ *models.UserModel) error { return nil }
+
+func (s *fakeStorage) AddRole(_ context.Context, user *models.UserModel) error {
+	s.roles[user.TelegramID] = user.Role
+
+	return nil
+}
+
+func (s *fakeStorage) CheckRole(_ context.Context, user *models.UserModel) (*string, error) {
+	role, ok := s.roles[user.TelegramID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &role, nil
+}
+
+func (s *fakeStorage) IsLecturerApproved(_ context.Context, telegramID int64) (bool, error) {
+	_, ok := s.approved[telegramID]
+
+	return ok, nil
+}
+
+func (s *fakeStorage) ApproveLecturer(_ context.Context, telegramID int64, approvedBy int64) error {
+	s.approved[telegramID] = approvedBy
+
+	return nil
+}
+
+func (s *fakeStorage) RevokeLecturer(_ context.Context, telegramID int64) error {
+	delete(s.approved, telegramID)
+
+	return nil
+}
+
+func (s *fakeStorage) SaveLecturerCode(_ context.Context, code *models.LecturerCodeModel) error {
+	s.codes[code.Code] = code
+
+	return nil
+}
+
+func (s *fakeStorage) TakeLecturerCode(_ context.Context, code string, now time.Time) (*models.LecturerCodeModel, error) {
+	lecturerCode, ok := s.codes[code]
+	delete(s.codes, code)
+
+	if !ok || !lecturerCode.ExpiresAt.After(now) {
+		return nil, storage.ErrLecturerCodeNotFound
+	}
+
+	return lecturerCode, nil
+}
+
+func TestBotAuth_LecturerVerification(t *testing.T) {
+	const (
+		admin    = 1
+		allowed  = 2
+		stranger = 3
+	)
+
+	cfg := DefaultConfig()
+	cfg.AdminIDs = []int64{admin}
+	cfg.LecturerIDs = []int64{allowed}
+
+	botAuth := NewBotAuth(cfg)
+	st := newFakeStorage()
+
+	// выбрать роль преподавателя напрямую нельзя
+	require.ErrorIs(t, botAuth.AddRole(st, stranger, "Lecturer"), ErrVerificationRequired)
+
+	role, err := botAuth.RequestLecturerRole(st, allowed)
+	require.NoError(t, err)
+	assert.Equal(t, RoleLecturer, role)
+
+	role, err = botAuth.RequestLecturerRole(st, stranger)
+	require.NoError(t, err)
+	assert.Equal(t, RolePendingLecturer, role)
+	assert.Equal(t, RolePendingLecturer, st.roles[stranger])
+
+	_, err = botAuth.IssueLecturerCode(st, stranger)
+	require.ErrorIs(t, err, ErrNotAdmin)
+
+	code, err := botAuth.IssueLecturerCode(st, admin)
+	require.NoError(t, err)
+	assert.Len(t, code.Code, lecturerCodeLen)
+
+	require.ErrorIs(t, botAuth.VerifyLecturer(st, stranger, "WRONG"), ErrInvalidLecturerCode)
+	require.NoError(t, botAuth.VerifyLecturer(st, stranger, " "+strings.ToLower(code.Code)+" "))
+	assert.Equal(t, RoleLecturer, st.roles[stranger])
+	assert.Equal(t, int64(admin), st.approved[stranger])
+
+	// код одноразовый
+	require.ErrorIs(t, botAuth.VerifyLecturer(st, 4, code.Code), ErrInvalidLecturerCode)
+
+	// подтверждение сохраняется, даже если пользователь переключался на роль студента
+	require.NoError(t, botAuth.AddRole(st, stranger, "Student"))
+
+	role, err = botAuth.RequestLecturerRole(st, stranger)
+	require.NoError(t, err)
+	assert.Equal(t, RoleLecturer, role)
+}
+
+func TestBotAuth_AdminRole(t *testing.T) {
+	const (
+		admin   = 1
+		allowed = 2
+		user    = 3
+	)
+
+	cfg := DefaultConfig()
+	cfg.AdminIDs = []int64{admin}
+	cfg.LecturerIDs = []int64{allowed}
+
+	botAuth := NewBotAuth(cfg)
+	st := newFakeStorage()
+
+	role, err := botAuth.CheckRole(st, admin)
+	require.NoError(t, err)
+	require.NotNil(t, role)
+	assert.Equal(t, RoleAdmin, *role)
+
+	require.ErrorIs(t, botAuth.AddRole(st, user, RoleAdmin), ErrNotAdmin)
+
+	// роль администратора в БД без записи в конфигурации не действует
+	st.roles[user] = RoleAdmin
+
+	role, err = botAuth.CheckRole(st, user)
+	require.NoError(t, err)
+	assert.Nil(t, role)
+
+	require.NoError(t, botAuth.PromoteLecturer(st, user, admin))
+	assert.Equal(t, RoleLecturer, st.roles[user])
+	assert.Equal(t, int64(admin), st.approved[user])
+
+	require.NoError(t, botAuth.DemoteLecturer(st, user))
+	assert.Equal(t, RoleStudent, st.roles[user])
+	assert.NotContains(t, st.approved, int64(user))
+
+	requested, err := botAuth.RequestLecturerRole(st, user)
+	require.NoError(t, err)
+	assert.Equal(t, RolePendingLecturer, requested)
+
+	require.ErrorIs(t, botAuth.DemoteLecturer(st, allowed), ErrConfiguredLecturer)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	env := map[string]string{
+		"ADMIN_IDS":         "101",
+		"LECTURER_IDS":      "202, 303 404",
+		"LECTURER_CODE_TTL": "24h",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+
+		return value, ok
+	}
+
+	cfg, err := ConfigFromEnv(lookup)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{101}, cfg.AdminIDs)
+	assert.Equal(t, []int64{202, 303, 404}, cfg.LecturerIDs)
+	assert.Equal(t, 24*time.Hour, cfg.CodeTTL)
+
+	env["LECTURER_IDS"] = "202,@teacher"
+	_, err = ConfigFromEnv(lookup)
+	require.Error(t, err)
+
+	env["LECTURER_IDS"] = ""
+	env["LECTURER_CODE_TTL"] = "0s"
+	_, err = ConfigFromEnv(lookup)
+	require.Error(t, err)
+}
@@ -0,0 +1,270 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+// Config — настройки подтверждения роли преподавателя.
+type Config struct {
+	AdminIDs    []int64       // администраторы бота
+	LecturerIDs []int64       // преподаватели, которым роль выдаётся без кода
+	CodeTTL     time.Duration // срок действия кода подтверждения
+}
+
+// DefaultConfig возвращает настройки по умолчанию: администраторов и заранее разрешённых преподавателей нет.
+func DefaultConfig() Config {
+	return Config{
+		CodeTTL: 72 * time.Hour,
+	}
+}
+
+// ConfigFromEnv возвращает DefaultConfig, переопределённый переменными окружения:
+// ADMIN_IDS и LECTURER_IDS (Telegram ID через запятую или пробел)
+// и LECTURER_CODE_TTL (в формате time.ParseDuration).
+func ConfigFromEnv(lookup func(string) (string, bool)) (Config, error) {
+	cfg := DefaultConfig()
+
+	lists := map[string]*[]int64{
+		"ADMIN_IDS":    &cfg.AdminIDs,
+		"LECTURER_IDS": &cfg.LecturerIDs,
+	}
+
+	for name, value := range lists {
+		if raw, ok := lookup(name); ok {
+			ids, err := parseIDs(raw)
+			if err != nil {
+				return Config{}, fmt.Errorf("invalid %s: %w", name, err)
+			}
+
+			*value = ids
+		}
+	}
+
+	if raw, ok := lookup("LECTURER_CODE_TTL"); ok {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			return Config{}, fmt.Errorf("invalid LECTURER_CODE_TTL: %q", raw)
+		}
+
+		cfg.CodeTTL = parsed
+	}
+
+	return cfg, nil
+}
+
+// parseIDs разбирает список Telegram ID, разделённых запятыми или пробелами.
+func parseIDs(raw string) ([]int64, error) {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+
+	ids := make([]int64, 0, len(fields))
+
+	for _, field := range fields {
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("%q is not a telegram id", field)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// lecturerCodeAlphabet — символы кода подтверждения. Похожие друг на друга 0/O и 1/I исключены,
+// чтобы код было легко переписать вручную. Длина алфавита делит 256, поэтому символы равновероятны.
+const lecturerCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const lecturerCodeLen = 8
+
+// IsAdmin сообщает, является ли пользователь администратором бота
+func (q *BotAuth) IsAdmin(telegramID int64) bool {
+	return slices.Contains(q.config.AdminIDs, telegramID)
+}
+
+// RequestLecturerRole выдаёт роль преподавателя, если пользователь есть в списке разрешённых
+// (в конфигурации или в БД), иначе переводит его в ожидание подтверждения. Возвращает выданную роль.
+func (q *BotAuth) RequestLecturerRole(st storage.Storage, telegramID int64) (string, error) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutAuth)
+	defer cancelFunc()
+
+	current, err := st.CheckRole(ctx, &models.UserModel{TelegramID: telegramID})
+	if err != nil {
+		return "", err
+	}
+
+	// преподаватель, выбравший роль ещё до появления подтверждения, её не теряет
+	if current != nil && *current == RoleLecturer {
+		return RoleLecturer, nil
+	}
+
+	approved, err := q.isLecturerApproved(ctx, st, telegramID)
+	if err != nil {
+		return "", err
+	}
+
+	role := RolePendingLecturer
+	if approved {
+		role = RoleLecturer
+	}
+
+	err = st.AddRole(ctx, &models.UserModel{
+		TelegramID: telegramID,
+		Role:       role,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return role, nil
+}
+
+// VerifyLecturer подтверждает роль преподавателя одноразовым кодом: код тратится, пользователь
+// попадает в список подтверждённых преподавателей в БД и получает роль.
+func (q *BotAuth) VerifyLecturer(st storage.Storage, telegramID int64, code string) error {
+	lecturerStorage, ok := st.(storage.LecturerStorage)
+	if !ok {
+		return ErrVerificationUnavailable
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutAuth)
+	defer cancelFunc()
+
+	lecturerCode, err := lecturerStorage.TakeLecturerCode(ctx, strings.ToUpper(strings.TrimSpace(code)), time.Now())
+	if errors.Is(err, storage.ErrLecturerCodeNotFound) {
+		return ErrInvalidLecturerCode
+	} else if err != nil {
+		return err
+	}
+
+	err = lecturerStorage.ApproveLecturer(ctx, telegramID, lecturerCode.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	return st.AddRole(ctx, &models.UserModel{
+		TelegramID: telegramID,
+		Role:       RoleLecturer,
+	})
+}
+
+// IssueLecturerCode создаёт одноразовый код подтверждения преподавателя сроком на CodeTTL.
+// Возвращает ErrNotAdmin, если issuerID не администратор.
+func (q *BotAuth) IssueLecturerCode(st storage.Storage, issuerID int64) (*models.LecturerCodeModel, error) {
+	if !q.IsAdmin(issuerID) {
+		return nil, ErrNotAdmin
+	}
+
+	lecturerStorage, ok := st.(storage.LecturerStorage)
+	if !ok {
+		return nil, ErrVerificationUnavailable
+	}
+
+	code, err := newLecturerCode()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutAuth)
+	defer cancelFunc()
+
+	lecturerCode := &models.LecturerCodeModel{
+		Code:      code,
+		CreatedBy: issuerID,
+		ExpiresAt: time.Now().Add(q.config.CodeTTL),
+	}
+
+	err = lecturerStorage.SaveLecturerCode(ctx, lecturerCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return lecturerCode, nil
+}
+
+// PromoteLecturer подтверждает пользователю роль преподавателя от имени администратора approvedBy
+// и выдаёт её. Пользователь, ещё не писавший боту, получит роль, выбрав её в /start.
+func (q *BotAuth) PromoteLecturer(st storage.Storage, telegramID int64, approvedBy int64) error {
+	lecturerStorage, ok := st.(storage.LecturerStorage)
+	if !ok {
+		return ErrVerificationUnavailable
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutAuth)
+	defer cancelFunc()
+
+	err := lecturerStorage.ApproveLecturer(ctx, telegramID, approvedBy)
+	if err != nil {
+		return err
+	}
+
+	return st.AddRole(ctx, &models.UserModel{
+		TelegramID: telegramID,
+		Role:       RoleLecturer,
+	})
+}
+
+// DemoteLecturer отзывает подтверждение роли преподавателя и делает пользователя студентом.
+// Преподавателей из LECTURER_IDS отозвать нельзя: при следующем выборе роли они получили бы её снова.
+func (q *BotAuth) DemoteLecturer(st storage.Storage, telegramID int64) error {
+	if slices.Contains(q.config.LecturerIDs, telegramID) {
+		return ErrConfiguredLecturer
+	}
+
+	lecturerStorage, ok := st.(storage.LecturerStorage)
+	if !ok {
+		return ErrVerificationUnavailable
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutAuth)
+	defer cancelFunc()
+
+	err := lecturerStorage.RevokeLecturer(ctx, telegramID)
+	if err != nil {
+		return err
+	}
+
+	return st.AddRole(ctx, &models.UserModel{
+		TelegramID: telegramID,
+		Role:       RoleStudent,
+	})
+}
+
+// isLecturerApproved сообщает, можно ли выдать пользователю роль преподавателя без кода.
+func (q *BotAuth) isLecturerApproved(ctx context.Context, st storage.Storage, telegramID int64) (bool, error) {
+	if slices.Contains(q.config.LecturerIDs, telegramID) {
+		return true, nil
+	}
+
+	lecturerStorage, ok := st.(storage.LecturerStorage)
+	if !ok {
+		return false, nil
+	}
+
+	return lecturerStorage.IsLecturerApproved(ctx, telegramID)
+}
+
+// newLecturerCode генерирует случайный код подтверждения.
+func newLecturerCode() (string, error) {
+	buf := make([]byte, lecturerCodeLen)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	for i, b := range buf {
+		buf[i] = lecturerCodeAlphabet[int(b)%len(lecturerCodeAlphabet)]
+	}
+
+	return string(buf), nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/letsssgooo/quizBot/internal/domain/models"
 	"github.com/letsssgooo/quizBot/internal/storage"
 )
 
@@ -20,16 +21,61 @@ type Auth interface {
 
 	// CheckRole возвращает роль у существующего пользователя. Возвращает nil, если роли нет.
 	CheckRole(st storage.Storage, telegramID int64) (*string, error)
+
+	// RequestLecturerRole выдаёт роль преподавателя, если она пользователю подтверждена,
+	// иначе переводит его в ожидание подтверждения. Возвращает выданную роль.
+	RequestLecturerRole(st storage.Storage, telegramID int64) (string, error)
+
+	// VerifyLecturer подтверждает роль преподавателя одноразовым кодом и выдаёт её.
+	VerifyLecturer(st storage.Storage, telegramID int64, code string) error
+
+	// IssueLecturerCode создаёт одноразовый код подтверждения преподавателя. Доступно только администраторам.
+	IssueLecturerCode(st storage.Storage, issuerID int64) (*models.LecturerCodeModel, error)
+
+	// IsAdmin сообщает, является ли пользователь администратором бота.
+	IsAdmin(telegramID int64) bool
+
+	// PromoteLecturer подтверждает пользователю роль преподавателя без кода и выдаёт её.
+	PromoteLecturer(st storage.Storage, telegramID int64, approvedBy int64) error
+
+	// DemoteLecturer отзывает у пользователя подтверждение роли преподавателя и делает его студентом.
+	DemoteLecturer(st storage.Storage, telegramID int64) error
 }
 
 // Ошибки авторизации
-var ErrValidation = errors.New("validation error")
+var (
+	ErrValidation = errors.New("validation error")
+
+	// ErrVerificationRequired возвращается при попытке выдать роль преподавателя без подтверждения.
+	ErrVerificationRequired = errors.New("lecturer role requires verification")
+	// ErrInvalidLecturerCode возвращается, если кода нет, он уже использован или истёк.
+	ErrInvalidLecturerCode = errors.New("invalid lecturer code")
+	// ErrNotAdmin возвращается, если действие доступно только администраторам.
+	ErrNotAdmin = errors.New("user is not an admin")
+	// ErrVerificationUnavailable возвращается, если хранилище не поддерживает коды подтверждения.
+	ErrVerificationUnavailable = errors.New("lecturer verification is not supported by storage")
+	// ErrConfiguredLecturer возвращается при попытке отозвать роль у преподавателя из LECTURER_IDS.
+	ErrConfiguredLecturer = errors.New("lecturer is allowed by configuration")
+)
 
 // Роли
 const (
-	RoleLecturer = "lecturer"
-	RoleStudent  = "student"
+	RoleLecturer        = "lecturer"
+	RoleStudent         = "student"
+	RolePendingLecturer = "pending_lecturer" // выбрал роль преподавателя, но ещё не подтвердил её
+	RoleAdmin           = "admin"            // администратор бота, назначается только через конфигурацию
+)
+
+// Поля регистрации участника, значения которых проверяются по типу (см. ParseField)
+const (
+	FieldName      = "name"       // ФИО
+	FieldGroup     = "group"      // учебная группа
+	FieldEmail     = "email"      // почта
+	FieldStudentID = "student_id" // номер студенческого билета
 )
 
+// Максимальная длина значения поля регистрации без проверки по типу
+const maxFieldLen = 100
+
 // Таймаут
 const timeoutAuth = 500 * time.Millisecond
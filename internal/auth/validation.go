@@ -2,23 +2,81 @@ package auth
 
 import (
 	"fmt"
+	"net/mail"
 	"regexp"
 	"strings"
 	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	groupRe     = regexp.MustCompile(`(?i)^[а-яё]{4}[0-9]{3}$`)
+	studentIDRe = regexp.MustCompile(`^[0-9A-Za-z-]{3,20}$`)
 )
 
 // ParseStudentsData валидирует сообщение студента и отдает обработанные данные (фио и группа)
 func ParseStudentsData(data []string) ([]string, error) {
-	fullNameParts := data[:len(data)-1]
+	fullName, err := parseFullName(data[:len(data)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := parseGroup(data[len(data)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{fullName, group}, nil
+}
+
+// ParseField валидирует значение поля регистрации в зависимости от типа поля и отдает обработанное значение.
+// Поля неизвестного типа принимаются как непустой текст не длиннее maxFieldLen символов.
+func ParseField(field, value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	switch field {
+	case FieldName:
+		return parseFullName(strings.Fields(value))
+	case FieldGroup:
+		return parseGroup(value)
+	case FieldEmail:
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value {
+			return "", fmt.Errorf("%w, invalid email", ErrValidation)
+		}
+
+		return strings.ToLower(value), nil
+	case FieldStudentID:
+		if !studentIDRe.MatchString(value) {
+			return "", fmt.Errorf("%w, invalid student id", ErrValidation)
+		}
 
+		return strings.ToUpper(value), nil
+	}
+
+	if value == "" {
+		return "", fmt.Errorf("%w, empty value of %s", ErrValidation, field)
+	}
+
+	if utf8.RuneCountInString(value) > maxFieldLen {
+		return "", fmt.Errorf("%w, %s is longer than %d characters", ErrValidation, field, maxFieldLen)
+	}
+
+	return value, nil
+}
+
+// parseFullName проверяет части ФИО и приводит каждую к виду "Иванов"
+func parseFullName(fullNameParts []string) (string, error) {
 	if len(fullNameParts) != 3 {
-		return nil, fmt.Errorf("%w, need 3 parts of fullname", ErrValidation)
+		return "", fmt.Errorf("%w, need 3 parts of fullname", ErrValidation)
 	}
 
+	parts := make([]string, len(fullNameParts))
+
 	for i, word := range fullNameParts {
 		wordRunes := []rune(word)
 		if len(wordRunes) < 2 {
-			return nil, fmt.Errorf("%w, there are too few letters in a part of fullName", ErrValidation)
+			return "", fmt.Errorf("%w, there are too few letters in a part of fullName", ErrValidation)
 		}
 
 		wordRunes[0] = unicode.ToUpper(wordRunes[0])
@@ -30,25 +88,25 @@ func ParseStudentsData(data []string) ([]string, error) {
 			}
 
 			if !unicode.IsLetter(letter) {
-				return nil, fmt.Errorf("%w, only letters and '-' can be in fullName", ErrValidation)
+				return "", fmt.Errorf("%w, only letters and '-' can be in fullName", ErrValidation)
 			}
 
 			wordRunes[j] = unicode.ToLower(wordRunes[j])
 		}
 
-		fullNameParts[i] = string(wordRunes)
+		parts[i] = string(wordRunes)
 	}
 
-	group := data[len(data)-1]
+	return strings.Join(parts, " "), nil
+}
 
-	re := regexp.MustCompile(`(?i)^[а-яё]{4}[0-9]{3}$`)
-	if !re.MatchString(group) {
-		return nil, fmt.Errorf("%w, cannot add group to user, invalid parameter", ErrValidation)
+// parseGroup проверяет учебную группу вида "ИВТБ101"
+func parseGroup(group string) (string, error) {
+	if !groupRe.MatchString(group) {
+		return "", fmt.Errorf("%w, cannot add group to user, invalid parameter", ErrValidation)
 	}
 
-	group = strings.ToUpper(group)
-
-	return []string{strings.Join(fullNameParts, " "), group}, nil
+	return strings.ToUpper(group), nil
 }
 
 // ParseRole валидирует сообщение пользователя и отдает роль в виде строки
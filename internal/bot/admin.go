@@ -0,0 +1,496 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/auth"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+const maxListedUsers = 50 // максимум пользователей в ответе на /users
+
+// usersFilterBanned — фильтр /users для заблокированных пользователей: это не роль, а отдельный список.
+const usersFilterBanned = "banned"
+
+// usersFilters — фильтры /users и роли, которые им соответствуют.
+var usersFilters = map[string]string{
+	"lecturer":        auth.RoleLecturer,
+	"pending":         auth.RolePendingLecturer,
+	"student":         auth.RoleStudent,
+	"admin":           auth.RoleAdmin,
+	"none":            "",
+	usersFilterBanned: usersFilterBanned,
+}
+
+// roleTitles — названия ролей в ответах администратору.
+var roleTitles = map[string]string{
+	auth.RoleLecturer:        "преподаватели",
+	auth.RolePendingLecturer: "ожидают подтверждения",
+	auth.RoleStudent:         "студенты",
+	auth.RoleAdmin:           "администраторы",
+	"":                       "без роли",
+	usersFilterBanned:        "заблокированные",
+}
+
+// loadBans загружает заблокированных пользователей, чтобы не обращаться к БД на каждое обновление.
+func (b *Bot) loadBans(ctx context.Context) {
+	adminStorage, ok := b.storage.(storage.AdminStorage)
+	if !ok {
+		return
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	bans, err := adminStorage.ListBans(storageCtx)
+	if err != nil {
+		slog.Error("failed to load banned users", "error", err)
+
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ban := range bans {
+		b.banned[ban.TelegramID] = struct{}{}
+	}
+}
+
+// isBanned сообщает, пришло ли обновление от заблокированного пользователя.
+func (b *Bot) isBanned(update client.Update) bool {
+	userID, _, ok := updateSource(update)
+	if !ok {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, banned := b.banned[userID]
+
+	return banned
+}
+
+// resolveUser возвращает Telegram ID пользователя, указанного администратором по ID или @username.
+func (b *Bot) resolveUser(ctx context.Context, arg string) (int64, error) {
+	if id, err := strconv.ParseInt(arg, 10, 64); err == nil && id > 0 {
+		return id, nil
+	}
+
+	shareStorage, ok := b.storage.(storage.ShareStorage)
+	if !ok {
+		return 0, storage.ErrUserNotFound
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	return shareStorage.FindUserByUsername(storageCtx, strings.TrimPrefix(arg, "@"))
+}
+
+// adminTarget разбирает пользователя из первого аргумента команды администратора и отвечает сам,
+// если пользователь не указан, не найден или это администратор. ok = false, если команду выполнять не нужно.
+func (b *Bot) adminTarget(
+	ctx context.Context,
+	message *client.Message,
+	args []string,
+	command string,
+) (userID int64, ok bool, err error) {
+	if len(args) == 0 {
+		_, err = b.sender.Message(message.Chat.ID, fmt.Sprintf(msgAdminTargetUsage, command), nil)
+
+		return 0, false, err
+	}
+
+	userID, err = b.resolveUser(ctx, args[0])
+	if errors.Is(err, storage.ErrUserNotFound) {
+		_, err = b.sender.Message(message.Chat.ID, msgAdminUserNotFound, nil)
+
+		return 0, false, err
+	} else if err != nil {
+		return 0, false, err
+	}
+
+	if b.auth.IsAdmin(userID) {
+		_, err = b.sender.Message(message.Chat.ID, msgAdminTargetIsAdmin, nil)
+
+		return 0, false, err
+	}
+
+	return userID, true, nil
+}
+
+// handleUsersCommand показывает пользователей с указанной ролью: /users <роль>.
+func (b *Bot) handleUsersCommand(ctx context.Context, message *client.Message, args []string) error {
+	adminStorage, ok := b.storage.(storage.AdminStorage)
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgAdminUnavailable, nil)
+
+		return err
+	}
+
+	var filter string
+	if len(args) == 1 {
+		filter, ok = usersFilters[strings.ToLower(args[0])]
+	}
+
+	if len(args) != 1 || !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgUsersUsage, nil)
+
+		return err
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	var lines []string
+
+	if filter == usersFilterBanned {
+		bans, err := adminStorage.ListBans(storageCtx)
+		if err != nil {
+			return err
+		}
+
+		for _, ban := range bans {
+			line := "- " + userLabel(ban.TelegramID, ban.Username, ban.FullName)
+			if ban.Reason != "" {
+				line += ": " + ban.Reason
+			}
+
+			lines = append(lines, line)
+		}
+	} else {
+		users, err := adminStorage.ListUsersByRole(storageCtx, filter, maxListedUsers)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range users {
+			lines = append(lines, "- "+userLabel(user.TelegramID, user.Username, user.FullName))
+		}
+	}
+
+	if len(lines) == 0 {
+		_, err := b.sender.Message(message.Chat.ID, msgUsersEmpty, nil)
+
+		return err
+	}
+
+	text := fmt.Sprintf("Пользователи: %s\n%s", roleTitles[filter], strings.Join(lines, "\n"))
+	if len(lines) >= maxListedUsers && filter != usersFilterBanned {
+		text += fmt.Sprintf("\n\nПоказаны последние %d.", maxListedUsers)
+	}
+
+	_, err := b.sender.Message(message.Chat.ID, text, nil)
+
+	return err
+}
+
+// handlePromoteCommand выдаёт пользователю роль преподавателя без кода: /promote <ID или @username>.
+func (b *Bot) handlePromoteCommand(ctx context.Context, message *client.Message, args []string) error {
+	userID, ok, err := b.adminTarget(ctx, message, args, "/promote")
+	if !ok || err != nil {
+		return err
+	}
+
+	err = b.auth.PromoteLecturer(b.storage, userID, message.From.ID)
+	if errors.Is(err, auth.ErrVerificationUnavailable) {
+		_, err = b.sender.Message(message.Chat.ID, msgAdminUnavailable, nil)
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	b.setUserCommandMenu(userID, auth.RoleLecturer)
+
+	_, err = b.sender.Message(userID, msgPromotedNotice, nil)
+	if err != nil {
+		slog.Debug("failed to notify promoted lecturer", "error", err, "user", userID)
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, fmt.Sprintf(msgPromoted, args[0]), nil)
+
+	return err
+}
+
+// handleDemoteCommand отзывает у пользователя роль преподавателя: /demote <ID или @username>.
+func (b *Bot) handleDemoteCommand(ctx context.Context, message *client.Message, args []string) error {
+	userID, ok, err := b.adminTarget(ctx, message, args, "/demote")
+	if !ok || err != nil {
+		return err
+	}
+
+	err = b.auth.DemoteLecturer(b.storage, userID)
+	if errors.Is(err, auth.ErrConfiguredLecturer) {
+		_, err = b.sender.Message(message.Chat.ID, fmt.Sprintf(msgDemoteConfigured, args[0]), nil)
+
+		return err
+	} else if errors.Is(err, auth.ErrVerificationUnavailable) {
+		_, err = b.sender.Message(message.Chat.ID, msgAdminUnavailable, nil)
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	b.setUserCommandMenu(userID, auth.RoleStudent)
+
+	_, err = b.sender.Message(userID, msgDemotedNotice, nil)
+	if err != nil {
+		slog.Debug("failed to notify demoted lecturer", "error", err, "user", userID)
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, fmt.Sprintf(msgDemoted, args[0]), nil)
+
+	return err
+}
+
+// handleBanCommand блокирует пользователя: /ban <ID или @username> [причина].
+// Обновления заблокированного пользователя отбрасываются в HandleUpdate.
+func (b *Bot) handleBanCommand(ctx context.Context, message *client.Message, args []string) error {
+	adminStorage, ok := b.storage.(storage.AdminStorage)
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgAdminUnavailable, nil)
+
+		return err
+	}
+
+	if len(args) == 0 {
+		_, err := b.sender.Message(message.Chat.ID, msgBanUsage, nil)
+
+		return err
+	}
+
+	userID, ok, err := b.adminTarget(ctx, message, args, "/ban")
+	if !ok || err != nil {
+		return err
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	err = adminStorage.BanUser(storageCtx, &models.BanModel{
+		TelegramID: userID,
+		Reason:     strings.Join(args[1:], " "),
+		BannedBy:   message.From.ID,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.banned[userID] = struct{}{}
+	b.mu.Unlock()
+
+	_, err = b.sender.Message(userID, msgBannedNotice, nil)
+	if err != nil {
+		slog.Debug("failed to notify banned user", "error", err, "user", userID)
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, fmt.Sprintf(msgBanned, args[0]), nil)
+
+	return err
+}
+
+// handleUnbanCommand снимает блокировку: /unban <ID или @username>.
+func (b *Bot) handleUnbanCommand(ctx context.Context, message *client.Message, args []string) error {
+	adminStorage, ok := b.storage.(storage.AdminStorage)
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgAdminUnavailable, nil)
+
+		return err
+	}
+
+	userID, ok, err := b.adminTarget(ctx, message, args, "/unban")
+	if !ok || err != nil {
+		return err
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	err = adminStorage.UnbanUser(storageCtx, userID)
+	if errors.Is(err, storage.ErrUserNotBanned) {
+		_, err = b.sender.Message(message.Chat.ID, fmt.Sprintf(msgNotBanned, args[0]), nil)
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	delete(b.banned, userID)
+	b.mu.Unlock()
+
+	_, err = b.sender.Message(userID, msgUnbannedNotice, nil)
+	if err != nil {
+		slog.Debug("failed to notify unbanned user", "error", err, "user", userID)
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, fmt.Sprintf(msgUnbanned, args[0]), nil)
+
+	return err
+}
+
+// handleBotStatsCommand показывает общую статистику бота и активные запуски с их ID для /forcecancel.
+func (b *Bot) handleBotStatsCommand(ctx context.Context, message *client.Message) error {
+	var builder strings.Builder
+
+	builder.WriteString("Статистика бота\n")
+
+	if adminStorage, ok := b.storage.(storage.AdminStorage); ok {
+		storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+		defer cancelFunc()
+
+		stats, err := adminStorage.GetBotStats(storageCtx)
+		if err != nil {
+			return err
+		}
+
+		builder.WriteString(formatBotStats(stats))
+	}
+
+	runs := b.activeRuns()
+
+	builder.WriteString(fmt.Sprintf("\nАктивные запуски: %d\n", len(runs)))
+
+	b.mu.Lock()
+	for _, run := range runs {
+		status := "лобби"
+		if run.Status == engine.RunStatusRunning {
+			status = "идёт"
+		}
+
+		builder.WriteString(fmt.Sprintf(
+			"- %s (%s, участников %d): %s\n",
+			b.runIDToQuiz[run.ID].Title,
+			status,
+			b.engine.GetParticipantCount(run.ID),
+			run.ID,
+		))
+	}
+	b.mu.Unlock()
+
+	_, err := b.sender.Message(message.Chat.ID, strings.TrimSuffix(builder.String(), "\n"), nil)
+
+	return err
+}
+
+// formatBotStats формирует статистику пользователей, квизов и запусков из БД.
+func formatBotStats(stats *models.BotStatsModel) string {
+	var (
+		builder strings.Builder
+		total   int
+	)
+
+	for _, count := range stats.UsersByRole {
+		total += count
+	}
+
+	builder.WriteString(fmt.Sprintf("\nПользователи: %d\n", total))
+
+	roles := []string{auth.RoleLecturer, auth.RolePendingLecturer, auth.RoleStudent, auth.RoleAdmin, ""}
+	for _, role := range roles {
+		builder.WriteString(fmt.Sprintf("- %s: %d\n", roleTitles[role], stats.UsersByRole[role]))
+	}
+
+	builder.WriteString(fmt.Sprintf("Заблокировано: %d\n", stats.BannedUsers))
+	builder.WriteString(fmt.Sprintf("\nКвизов сохранено: %d\n", stats.Quizzes))
+	builder.WriteString(fmt.Sprintf("Запусков: %d, из них завершено: %d\n", stats.Runs, stats.FinishedRuns))
+
+	return builder.String()
+}
+
+// activeRuns возвращает запуски в лобби и идущие сейчас, начиная с самых старых.
+func (b *Bot) activeRuns() []*engine.QuizRun {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var runs []*engine.QuizRun
+
+	for runID := range b.runIDToQuiz {
+		run, err := b.engine.GetRun(runID)
+		if err == nil && (run.Status == engine.RunStatusLobby || run.Status == engine.RunStatusRunning) {
+			runs = append(runs, run)
+		}
+	}
+
+	slices.SortFunc(runs, func(a, b *engine.QuizRun) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+
+	return runs
+}
+
+// handleForceCancelCommand отменяет любой активный запуск: /forcecancel <ID запуска>.
+// Тот, кто проводит запуск, получает сообщение об отмене администратором.
+func (b *Bot) handleForceCancelCommand(ctx context.Context, message *client.Message, args []string) error {
+	if len(args) != 1 {
+		_, err := b.sender.Message(message.Chat.ID, msgForceCancelUsage, nil)
+
+		return err
+	}
+
+	runID := args[0]
+
+	b.mu.Lock()
+	quiz, ok := b.runIDToQuiz[runID]
+	ownerChatID := b.runIDToOwnerChatID[runID]
+	b.mu.Unlock()
+
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgUnknownQuiz, nil)
+
+		return err
+	}
+
+	err := b.cancelRun(ctx, runID, message.Chat.ID)
+	if errors.Is(err, engine.ErrNotCancellable) {
+		_, err = b.sender.Message(message.Chat.ID, msgRunNotCancellable, nil)
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if ownerChatID != 0 && ownerChatID != message.Chat.ID {
+		_, err = b.sender.Message(ownerChatID, fmt.Sprintf(msgRunCancelledByAdmin, quiz.Title), nil)
+		if err != nil {
+			slog.Debug("failed to notify about force cancelled run", "error", err, "run", runID)
+		}
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, fmt.Sprintf(msgRunForceCancelled, quiz.Title), nil)
+
+	return err
+}
+
+// userLabel возвращает имя пользователя вместе с Telegram ID для списков администратора.
+func userLabel(telegramID int64, username, fullName string) string {
+	parts := make([]string, 0, 3)
+
+	if fullName != "" {
+		parts = append(parts, fullName)
+	}
+
+	if username != "" {
+		parts = append(parts, "@"+username)
+	}
+
+	parts = append(parts, fmt.Sprintf("(ID %d)", telegramID))
+
+	return strings.Join(parts, " ")
+}
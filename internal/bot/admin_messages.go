@@ -0,0 +1,58 @@
+package bot
+
+const msgAdminWelcome = `Здравствуйте! Вы администратор бота.
+
+Команды администратора — в /help.`
+
+const msgAdminHelp = `Вы администратор бота: выдаёте коды подтверждения преподавателям, управляете ролями и блокировками,
+смотрите общую статистику и при необходимости отменяете любой запуск.`
+
+const msgAdminsOnly = `Эта команда доступна только администраторам.`
+
+const msgUsersUsage = `Чтобы посмотреть пользователей, отправьте:
+/users <роль>
+
+Роли: lecturer (преподаватели), pending (ожидают подтверждения), student (студенты), admin (администраторы),
+none (без роли), banned (заблокированные).`
+
+const msgUsersEmpty = `Таких пользователей нет.`
+
+const msgAdminTargetUsage = `Укажите пользователя: %s <Telegram ID или @username>`
+
+const msgBanUsage = `Чтобы заблокировать пользователя, отправьте:
+/ban <Telegram ID или @username> [причина]`
+
+const msgAdminUserNotFound = `Пользователь с таким username не писал боту. Укажите его Telegram ID.`
+
+const msgAdminTargetIsAdmin = `Это администратор: его роль задаётся конфигурацией бота.`
+
+const msgPromoted = `Готово: %s теперь преподаватель.`
+
+const msgPromotedNotice = `Администратор подтвердил вам роль преподавателя 👍. Отправьте мне JSON файл с данными по квизу.`
+
+const msgDemoted = `Готово: %s больше не преподаватель.`
+
+const msgDemotedNotice = `Администратор отозвал у вас роль преподавателя.`
+
+const msgDemoteConfigured = `%s разрешён в конфигурации бота (LECTURER_IDS): уберите его оттуда, чтобы отозвать роль.`
+
+const msgBanned = `Готово: %s заблокирован.`
+
+const msgBannedNotice = `Вы заблокированы администратором бота.`
+
+const msgUnbanned = `Готово: %s разблокирован.`
+
+const msgUnbannedNotice = `Администратор бота снял с вас блокировку.`
+
+const msgNotBanned = `%s не заблокирован.`
+
+const msgForceCancelUsage = `Чтобы отменить запуск, отправьте:
+/forcecancel <ID запуска>
+
+ID активных запусков — в /botstats.`
+
+const msgRunForceCancelled = `Запуск квиза %s отменён.`
+
+const msgRunCancelledByAdmin = `Администратор бота отменил запуск квиза %s.`
+
+const msgAdminUnavailable = `Администрирование сейчас недоступно, попробуйте позже.`
@@ -9,11 +9,16 @@ import (
 	"sync"
 	"time"
 
+	"github.com/letsssgooo/quizBot/internal/access"
 	"github.com/letsssgooo/quizBot/internal/auth"
 	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/dialog"
 	"github.com/letsssgooo/quizBot/internal/events/engine"
 	"github.com/letsssgooo/quizBot/internal/events/fetcher"
 	"github.com/letsssgooo/quizBot/internal/events/sender"
+	"github.com/letsssgooo/quizBot/internal/integrity"
+	"github.com/letsssgooo/quizBot/internal/ratelimit"
+	"github.com/letsssgooo/quizBot/internal/router"
 	"github.com/letsssgooo/quizBot/internal/storage"
 )
 
@@ -34,8 +39,43 @@ type Bot struct {
 	userIDToChatID      map[int64]int64
 	runIDToOwnerChatID  map[string]int64
 	userIDToAnswersCnt  map[int64]int
-	hasLecturer         bool
-	mu                  sync.Mutex
+	// runIDToGraderID — запуски с очередью проверки открытых ответов и те, кто их проверяет
+	runIDToGraderID map[string]int64
+	// gradingMessages — отправленные проверяющим сообщения с ответами на проверку
+	gradingMessages map[messageRef]gradedAnswer
+	// gradingComments — комментарии к ещё не выставленным оценкам
+	gradingComments map[gradedAnswer]string
+	// userIDToPractice — активные тренировки студентов
+	userIDToPractice map[int64]*engine.PracticeSession
+	// userIDToReview — активные повторения карточек студентов
+	userIDToReview map[int64]*reviewSession
+	// runIDToQuestion — текущий вопрос запуска (после перемешивания вариантов)
+	runIDToQuestion map[string]*activeQuestion
+	// userIDToSelection — последний вариант, выбранный участником кнопкой
+	userIDToSelection map[int64]answerSelection
+	// pollIDToQuestion — открытые опросы-викторины и вопросы, которые в них отправлены
+	pollIDToQuestion map[string]pollQuestion
+	// runIDToGroupChatID — групповой чат, в котором проводится запуск
+	runIDToGroupChatID map[string]int64
+	// runIDToSavedQuizID — сохранённый квиз, по которому создан запуск
+	runIDToSavedQuizID map[string]string
+	// runIDToRestoredAt — когда выгруженный запуск вернули в память из хранилища
+	runIDToRestoredAt map[string]time.Time
+	// userIDToRestoredAt — когда выгруженные запуски студента вернули в память; пока отметка есть,
+	// /practice и /report не ходят за ними в хранилище
+	userIDToRestoredAt map[int64]time.Time
+	// router — зарегистрированные команды бота
+	router *router.Router
+	// dialogs — многошаговые диалоги пользователей
+	dialogs *dialog.Manager
+	// limiter — защита от флуда, проверяется до обработки обновления
+	limiter *ratelimit.Limiter
+	// integrity — пороги анализа ответов на списывание
+	integrity integrity.Config
+	// banned — заблокированные администратором пользователи, их обновления не обрабатываются
+	banned      map[int64]struct{}
+	hasLecturer bool
+	mu          sync.Mutex
 }
 
 // NewBot создаёт нового бота.
@@ -48,15 +88,19 @@ func NewBot(
 	sender sender.Sender,
 	quizEngine engine.QuizEngine,
 	storage storage.Storage,
+	limiter *ratelimit.Limiter,
+	integrityConfig integrity.Config,
 	botUsername string,
 ) *Bot {
-	return &Bot{
+	b := &Bot{
 		client:              client,
 		auth:                auth,
 		fetcher:             fetcher,
 		sender:              sender,
 		engine:              quizEngine,
 		storage:             storage,
+		limiter:             limiter,
+		integrity:           integrityConfig,
 		botUsername:         botUsername,
 		userIDToRunID:       make(map[int64]string),
 		runIDToLobbyEndChan: make(map[string]chan struct{}),
@@ -64,24 +108,55 @@ func NewBot(
 		userIDToChatID:      make(map[int64]int64),
 		runIDToOwnerChatID:  make(map[string]int64),
 		userIDToAnswersCnt:  make(map[int64]int),
+		runIDToGraderID:     make(map[string]int64),
+		gradingMessages:     make(map[messageRef]gradedAnswer),
+		gradingComments:     make(map[gradedAnswer]string),
+		userIDToPractice:    make(map[int64]*engine.PracticeSession),
+		userIDToReview:      make(map[int64]*reviewSession),
+		runIDToQuestion:     make(map[string]*activeQuestion),
+		userIDToSelection:   make(map[int64]answerSelection),
+		pollIDToQuestion:    make(map[string]pollQuestion),
+		runIDToGroupChatID:  make(map[string]int64),
+		runIDToSavedQuizID:  make(map[string]string),
+		runIDToRestoredAt:   make(map[string]time.Time),
+		userIDToRestoredAt:  make(map[int64]time.Time),
+		banned:              make(map[int64]struct{}),
 	}
+
+	b.router = b.newRouter()
+	b.dialogs = newDialogManager(storage)
+
+	return b
 }
 
 // Run запускает бота (long polling).
 func (b *Bot) Run(ctx context.Context) error {
 	slog.Debug("Bot started!")
 
-	runLoop:
+	b.setCommandMenus()
+
+	if err := b.dialogs.Load(ctx, time.Now()); err != nil {
+		slog.Error("failed to load dialogs", "error", err)
+	}
+
+	b.loadBans(ctx)
+
+	go b.runReviewReminders(ctx)
+	go b.runDialogTimeouts(ctx)
+	go b.runLifecycle(ctx)
+
+runLoop:
 	for { // long polling
 		updates, err := b.fetcher.GetUpdates(ctx, updatesTimeout)
 		if err != nil {
 			return err
 		}
 
+		// ошибка одного обновления не должна останавливать бота
 		for _, update := range updates {
 			err = b.HandleUpdate(ctx, update)
 			if err != nil {
-				return err
+				slog.Error("failed to handle update", "error", err, "update", update.UpdateID)
 			}
 		}
 
@@ -98,11 +173,24 @@ func (b *Bot) Run(ctx context.Context) error {
 }
 
 // HandleUpdate обрабатывает одно обновление.
+// Обновления заблокированных пользователей и обновления сверх лимита отбрасываются без обработки.
 func (b *Bot) HandleUpdate(ctx context.Context, update client.Update) error {
+	if b.isBanned(update) {
+		return nil
+	}
+
+	if !b.allowUpdate(update) {
+		return nil
+	}
+
 	if update.Message != nil {
 		return b.handleMessageUpdate(ctx, update.Message)
 	} else if update.CallbackQuery != nil {
 		return b.handleCallbackUpdate(ctx, update.CallbackQuery)
+	} else if update.PollAnswer != nil {
+		return b.handlePollAnswerUpdate(ctx, update.PollAnswer)
+	} else if update.Poll != nil {
+		return nil // состояние опросов приходит само, ответы берутся из poll_answer
 	}
 
 	return fmt.Errorf("%w, update :%v", errors.New("undefined update type"), update)
@@ -110,14 +198,36 @@ func (b *Bot) HandleUpdate(ctx context.Context, update client.Update) error {
 
 // handleMessageUpdate обрабатывает одно сообщение.
 func (b *Bot) handleMessageUpdate(ctx context.Context, message *client.Message) error {
+	if isGroupChat(message.Chat) {
+		return b.handleGroupMessage(ctx, message)
+	}
+
 	ID := message.From.ID
 
 	b.mu.Lock()
-	runID, ok := b.userIDToRunID[ID]
+	practice, isPractising := b.userIDToPractice[ID]
+	reviewing, isReviewing := b.userIDToReview[ID]
 	b.mu.Unlock()
 
-	if ok {
-		return b.handleAnswerUpdate(message.Chat.ID, message.From.ID, message.Text, runID)
+	isCommand := strings.HasPrefix(message.Text, "/")
+
+	if graded, ok := b.gradingReplyTarget(message); ok && message.Text != "" && !isCommand {
+		return b.handleGradingComment(message, graded)
+	}
+
+	if isPractising && !isCommand {
+		return b.handlePracticeAnswer(ctx, message.Chat.ID, practice, message.Text)
+	}
+
+	if isReviewing && !isCommand {
+		return b.handleReviewAnswer(ctx, message, reviewing)
+	}
+
+	if !isCommand {
+		handled, err := b.handleDialogMessage(ctx, message)
+		if handled || err != nil {
+			return err
+		}
 	}
 
 	if message.Document != nil {
@@ -135,35 +245,8 @@ func (b *Bot) handleMessageUpdate(ctx context.Context, message *client.Message)
 		}
 	}
 
-	text := strings.Fields(message.Text)
-
-	if text[0][:1] == "/" {
-		// TODO: добавить все команды бота
-		switch text[0] {
-		case "/start":
-			return b.handleStartCommand(ctx, message, text)
-		case "/help":
-			return b.handleHelpCommand(message)
-		default:
-			_, err := b.sender.Message(message.Chat.ID, msgUnknownCommand, nil)
-
-			return err
-		}
-	} else if len(text) == 4 {
-		err := b.auth.UpdateStudentData(b.storage, message.From.ID, text)
-		if errors.Is(err, auth.ErrValidation) {
-			slog.Debug("incorrect student data: ", "error", err)
-
-			_, err = b.sender.Message(message.Chat.ID, msgStudentsDataMistake, nil)
-
-			return err
-		} else if err != nil {
-			return err
-		}
-
-		_, err = b.sender.Message(message.Chat.ID, msgStudentsSuccessfullVerification, nil)
-
-		return err
+	if isCommand {
+		return b.handleCommand(ctx, message)
 	}
 
 	_, err := b.sender.Message(message.Chat.ID, msgUnknownText, nil)
@@ -188,6 +271,21 @@ func (b *Bot) handleStartCommand(
 		slog.Debug("User in database now", "username", message.From.Username)
 	}
 
+	b.saveUsername(ctx, message.From)
+
+	// администратор назначается конфигурацией и роль не выбирает
+	if len(text) == 1 && b.auth.IsAdmin(message.From.ID) {
+		if err = b.auth.AddRole(b.storage, message.From.ID, auth.RoleAdmin); err != nil {
+			return err
+		}
+
+		b.setUserCommandMenu(message.From.ID, auth.RoleAdmin)
+
+		_, err = b.sender.Message(message.Chat.ID, msgAdminWelcome, nil)
+
+		return err
+	}
+
 	if len(text) == 1 {
 		keyboard := client.InlineKeyboardMarkup{
 			InlineKeyboard: [][]client.InlineKeyboardButton{
@@ -206,14 +304,35 @@ func (b *Bot) handleStartCommand(
 		return err
 	}
 
+	// преподаватель перешёл по ссылке с кодом подтверждения роли
+	if code, ok := strings.CutPrefix(text[1], "lecturer_"); ok {
+		return b.verifyLecturer(ctx, message, code)
+	}
+
+	// студент перешёл по ссылке для записи на курс
+	if code, ok := strings.CutPrefix(text[1], "course_"); ok {
+		return b.handleCourseJoin(ctx, message, code)
+	}
+
+	// преподаватель перешёл по приглашению к чужому квизу
+	if code, ok := strings.CutPrefix(text[1], "invite_"); ok {
+		return b.handleInviteAccept(ctx, message, code)
+	}
+
 	// студент присоединен к квизу по ссылке
 	runID := strings.Split(text[1], "_")[1]
 
-	return b.handleStudentsJoin(ctx, message, runID)
+	return b.handleStudentsJoin(ctx, message, runID, nil)
 }
 
-// handleStudentsJoin присоединяет студента к квизу.
-func (b *Bot) handleStudentsJoin(ctx context.Context, message *client.Message, runID string) error {
+// handleStudentsJoin присоединяет студента к квизу. Если квиз требует полей регистрации, которых
+// у студента ещё нет, сначала спрашивает их. entered — значения полей, уже введённые в диалоге.
+func (b *Bot) handleStudentsJoin(
+	ctx context.Context,
+	message *client.Message,
+	runID string,
+	entered map[string]string,
+) error {
 	b.mu.Lock()
 
 	run, err := b.engine.GetRun(runID)
@@ -231,17 +350,47 @@ func (b *Bot) handleStudentsJoin(ctx context.Context, message *client.Message, r
 		return err
 	}
 
+	allowed, err := b.canJoinRun(ctx, runID, message.From.ID)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		_, err = b.sender.Message(message.Chat.ID, msgCourseOnly, nil)
+
+		return err
+	}
+
+	regData, missing, err := b.runFields(ctx, message.From.ID, runID, entered)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		err = b.askRunField(ctx, message.Chat.ID, message.From.ID, runID, missing[0], entered)
+		if errors.Is(err, dialog.ErrInvalidTransition) {
+			_, err = b.sender.Message(message.Chat.ID, msgDialogBusy, nil)
+		}
+
+		return err
+	}
+
 	participant := &engine.Participant{
 		TelegramID: message.From.ID,
 		Username:   message.From.Username,
 		FirstName:  message.From.FirstName,
 		LastName:   message.From.LastName,
+		RegData:    regData,
 	}
 
 	err = b.engine.JoinRun(ctx, runID, participant)
 	if errors.Is(err, engine.ErrLobbyFull) {
 		_, err = b.client.SendMessage(message.Chat.ID, msgMaxParticipantNumber, nil)
 
+		return err
+	} else if errors.Is(err, engine.ErrRunCancelled) {
+		_, err = b.client.SendMessage(message.Chat.ID, msgClosedLobby, nil)
+
 		return err
 	} else if err != nil {
 		return err
@@ -250,39 +399,58 @@ func (b *Bot) handleStudentsJoin(ctx context.Context, message *client.Message, r
 	b.mu.Lock()
 	b.userIDToRunID[message.From.ID] = runID
 	b.userIDToChatID[message.From.ID] = message.Chat.ID
+	delete(b.userIDToAnswersCnt, message.From.ID)
+	// тренировка и повторение перехватывают текстовые ответы, поэтому живой запуск их закрывает
+	delete(b.userIDToPractice, message.From.ID)
+	delete(b.userIDToReview, message.From.ID)
 	b.mu.Unlock()
 
+	b.startAnswering(ctx, message.From.ID, runID)
+
 	_, err = b.client.SendMessage(message.Chat.ID, msgQuizJoin, nil)
 
 	return err
 }
 
 // handleHelpCommand обрабатывает /help команду.
+// Список команд формируется из реестра роутера.
 func (b *Bot) handleHelpCommand(message *client.Message) error {
-	userRole, err := b.auth.CheckRole(b.storage, message.From.ID)
-	if err != nil {
+	if isGroupChat(message.Chat) {
+		msg := msgGroupHelp + "\n\nКоманды:\n" + b.router.Help("", router.ChatGroup)
+		_, err := b.sender.Message(message.Chat.ID, msg, nil)
+
 		return err
 	}
 
-	if userRole != nil {
-		if *userRole == auth.RoleLecturer {
-			_, err := b.sender.Message(message.Chat.ID, msgLecturersHelp, nil)
-
-			return err
-		}
-
-		_, err := b.sender.Message(message.Chat.ID, msgStudentsHelp, nil)
-
+	role, err := b.roleOf(context.Background(), message.From.ID)
+	if err != nil {
 		return err
 	}
 
-	_, err = b.sender.Message(message.Chat.ID, msgHelp, nil)
+	var msg string
+
+	switch role {
+	case auth.RoleLecturer:
+		msg = msgLecturersHelp
+	case auth.RoleStudent:
+		msg = msgStudentsHelp
+	case auth.RolePendingLecturer:
+		msg = msgLecturerPending
+	case auth.RoleAdmin:
+		msg = msgAdminHelp
+	default:
+		msg = msgHelp
+	}
+
+	msg += "\n\nКоманды:\n" + b.router.Help(role, router.ChatPrivate)
+	_, err = b.sender.Message(message.Chat.ID, msg, nil)
 
 	return err
 }
 
 // handleAnswerUpdate обрабатывает ответ студента на вопрос.
 func (b *Bot) handleAnswerUpdate(
+	ctx context.Context,
 	chatID, fromID int64,
 	text string,
 	runID string,
@@ -291,16 +459,45 @@ func (b *Bot) handleAnswerUpdate(
 
 	currentQuestion := b.engine.GetCurrentQuestion(runID)
 	hasAnswered := currentQuestion < b.userIDToAnswersCnt[fromID]
+	quiz := b.runIDToQuiz[runID]
 	b.mu.Unlock()
 
+	if currentQuestion < 0 {
+		_, err := b.sender.Message(chatID, msgNoActiveQuestion, nil)
+
+		return err
+	}
+
 	if hasAnswered {
 		_, err := b.sender.Message(chatID, msgRepeatedAnswer, nil)
 
 		return err
 	}
 
-	err := b.engine.SubmitAnswerByLetter(runID, fromID, text)
-	if err != nil {
+	var err error
+	if quiz.Questions[currentQuestion].IsOpen() {
+		err = b.engine.SubmitTextAnswer(ctx, runID, fromID, text)
+	} else {
+		err = b.engine.SubmitAnswerByLetter(ctx, runID, fromID, text)
+	}
+
+	if errors.Is(err, engine.ErrEmptyTextAnswer) {
+		_, err = b.sender.Message(chatID, msgEmptyTextAnswer, nil)
+
+		return err
+	} else if errors.Is(err, engine.ErrStaleQuestion) {
+		_, err = b.sender.Message(chatID, msgNoActiveQuestion, nil)
+
+		return err
+	} else if errors.Is(err, engine.ErrAlreadyAnswered) {
+		_, err = b.sender.Message(chatID, msgRepeatedAnswer, nil)
+
+		return err
+	} else if errors.Is(err, engine.ErrConvertLetterToIndex) || errors.Is(err, engine.ErrInvalidAnswerIndex) {
+		_, err = b.sender.Message(chatID, msgInvalidLetter, nil)
+
+		return err
+	} else if err != nil {
 		return err
 	}
 
@@ -336,6 +533,27 @@ func (b *Bot) handleDocumentUpdate(ctx context.Context, message *client.Message)
 	quiz.OwnerID = message.From.ID
 	quiz.CreatedAt = time.Now()
 
+	savedQuizID := b.saveQuiz(ctx, quiz, data)
+
+	return b.openLobby(ctx, message.Chat, message.From.ID, quiz, savedQuizID)
+}
+
+// openLobby создаёт запуск квиза и отправляет в чат сообщение лобби.
+// savedQuizID — ID сохранённого квиза, к которому привязывается запуск, или пустая строка.
+func (b *Bot) openLobby(
+	ctx context.Context,
+	chat *client.Chat,
+	ownerID int64,
+	quiz *engine.Quiz,
+	savedQuizID string,
+) error {
+	isGroup := isGroupChat(chat)
+
+	// в группе бот видит только команды и ответы на свои сообщения, поэтому буквы сообщением не принимаются
+	if isGroup && (quiz.Settings.AnswerMode == "" || quiz.Settings.AnswerMode == engine.AnswerModeText) {
+		quiz.Settings.AnswerMode = engine.AnswerModeButtons
+	}
+
 	activeQuizRun, err := b.engine.StartRun(ctx, quiz)
 	if err != nil {
 		return err
@@ -343,26 +561,41 @@ func (b *Bot) handleDocumentUpdate(ctx context.Context, message *client.Message)
 
 	b.mu.Lock()
 	b.runIDToQuiz[activeQuizRun.ID] = quiz
-	b.runIDToOwnerChatID[activeQuizRun.ID] = message.Chat.ID
+	b.runIDToOwnerChatID[activeQuizRun.ID] = chat.ID
+
+	if isGroup {
+		b.runIDToGroupChatID[activeQuizRun.ID] = chat.ID
+		// результаты и проверка ответов приходят преподавателю в личный чат
+		b.runIDToOwnerChatID[activeQuizRun.ID] = ownerID
+	}
+
+	if savedQuizID != "" {
+		b.runIDToSavedQuizID[activeQuizRun.ID] = savedQuizID
+	}
 	b.mu.Unlock()
 
+	b.saveRun(ctx, activeQuizRun.ID)
+
 	callbackData := fmt.Sprintf("start_quiz %s", activeQuizRun.ID)
 	keyboard := client.InlineKeyboardMarkup{
 		InlineKeyboard: [][]client.InlineKeyboardButton{
 			{
 				{Text: "Начать квиз", CallbackData: callbackData},
+				cancelRunButton("Отменить", activeQuizRun.ID),
 			},
 		},
 	}
 
-	text := fmt.Sprintf(`Квиз создан.
-Ссылка для студентов: %s
-Количество участников: %d`, fmt.Sprintf("https://t.me/%s?start=join_%s", b.botUsername, activeQuizRun.ID), 0)
+	if isGroup {
+		keyboard = groupLobbyKeyboard(activeQuizRun.ID)
+	}
+
+	text := b.lobbyText(activeQuizRun.ID, 0)
 	opts := &client.SendOptions{
 		ReplyMarkup: &keyboard,
 	}
 
-	botMessage, err := b.client.SendMessage(message.Chat.ID, text, opts)
+	botMessage, err := b.client.SendMessage(chat.ID, text, opts)
 	if err != nil {
 		return err
 	}
@@ -389,6 +622,7 @@ func (b *Bot) handleEditLecturerMessage(
 
 	prevCnt := 0
 	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
 
 	for {
 		select {
@@ -400,39 +634,133 @@ func (b *Bot) handleEditLecturerMessage(
 
 			prevCnt = cnt
 
-			text := fmt.Sprintf(`Квиз создан.
-Ссылка для студентов: %s
-Количество участников: %d`, fmt.Sprintf("https://t.me/%s?start=join_%s", b.botUsername, runID), cnt)
-			_ = b.client.EditMessage(botMessage.Chat.ID, botMessage.MessageID, text, opts)
+			_ = b.client.EditMessage(botMessage.Chat.ID, botMessage.MessageID, b.lobbyText(runID, cnt), opts)
 		case <-lobbyEndChan:
 			return nil
 		case <-ctx.Done():
+			return nil
 		}
 	}
 }
 
+// lobbyText формирует сообщение лобби с количеством участников.
+func (b *Bot) lobbyText(runID string, cnt int) string {
+	if _, ok := b.groupChatID(runID); ok {
+		return fmt.Sprintf(`Квиз создан.
+Чтобы участвовать, нажмите "Участвовать".
+Количество участников: %d`, cnt)
+	}
+
+	return fmt.Sprintf(`Квиз создан.
+Ссылка для студентов: %s
+Количество участников: %d`, fmt.Sprintf("https://t.me/%s?start=join_%s", b.botUsername, runID), cnt)
+}
+
 // handleCallbackUpdate обрабатывает callback запрос.
 func (b *Bot) handleCallbackUpdate(ctx context.Context, callback *client.CallbackQuery) error {
 	if callback.Data == "Student" || callback.Data == "Lecturer" {
-		err := b.auth.AddRole(b.storage, callback.From.ID, callback.Data)
+		role := auth.RoleStudent
+
+		var err error
+
+		// роль преподавателя выдаётся только после подтверждения, до него пользователь ждёт кода
+		if callback.Data == "Lecturer" {
+			role, err = b.auth.RequestLecturerRole(b.storage, callback.From.ID)
+		} else {
+			err = b.auth.AddRole(b.storage, callback.From.ID, callback.Data)
+		}
+
 		if err != nil {
 			return err
 		}
 
-		return b.handleIdentificationCallbackUpdate(callback)
+		b.setUserCommandMenu(callback.From.ID, role)
+
+		return b.handleIdentificationCallbackUpdate(ctx, callback, role)
+	}
+
+	if strings.HasPrefix(callback.Data, "ans ") {
+		return b.handleAnswerCallbackUpdate(ctx, callback)
+	}
+
+	if strings.HasPrefix(callback.Data, "grade ") {
+		return b.handleGradeCallbackUpdate(ctx, callback)
+	}
+
+	if strings.HasPrefix(callback.Data, "hint ") {
+		return b.handleHintCallbackUpdate(ctx, callback)
+	}
+
+	if strings.HasPrefix(callback.Data, "continue ") {
+		return b.handleContinueCallbackUpdate(ctx, callback)
+	}
+
+	if strings.HasPrefix(callback.Data, "report ") {
+		return b.handleReportCallbackUpdate(callback)
+	}
+
+	if strings.HasPrefix(callback.Data, "practice ") {
+		return b.handlePracticeCallbackUpdate(callback)
+	}
+
+	if strings.HasPrefix(callback.Data, "join ") {
+		return b.handleGroupJoinCallbackUpdate(ctx, callback)
+	}
+
+	if strings.HasPrefix(callback.Data, "myquiz ") {
+		return b.handleMyQuizCallbackUpdate(ctx, callback)
+	}
+
+	if strings.HasPrefix(callback.Data, "history ") {
+		return b.handleHistoryCallbackUpdate(ctx, callback)
+	}
+
+	if strings.HasPrefix(callback.Data, "cancel_run ") {
+		return b.handleCancelRunCallbackUpdate(ctx, callback)
+	}
+
+	if strings.HasPrefix(callback.Data, "stats ") {
+		return b.handleStatsCallbackUpdate(ctx, callback)
+	}
+
+	if strings.HasPrefix(callback.Data, "share ") {
+		return b.handleShareCallbackUpdate(ctx, callback)
+	}
+
+	if strings.HasPrefix(callback.Data, "course ") {
+		return b.handleCourseCallbackUpdate(ctx, callback)
 	}
 
 	return b.handleQuizStartCallbackUpdate(ctx, callback)
 }
 
 // handleIdentificationCallbackUpdate обрабатывает CallbackUpdate на основе роли.
-func (b *Bot) handleIdentificationCallbackUpdate(callback *client.CallbackQuery) error {
+func (b *Bot) handleIdentificationCallbackUpdate(
+	ctx context.Context,
+	callback *client.CallbackQuery,
+	role string,
+) error {
 	switch callback.Data {
 	case "Student":
-		_, err := b.sender.Message(callback.Message.Chat.ID, msgStudentsData, nil)
+		err := b.dialogs.Transition(ctx, callback.From.ID, dialog.StateAwaitingRegistration, nil, time.Now())
+		if errors.Is(err, dialog.ErrInvalidTransition) {
+			_, err = b.sender.Message(callback.Message.Chat.ID, msgDialogBusy, nil)
+
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		_, err = b.sender.Message(callback.Message.Chat.ID, msgStudentsData, nil)
 
 		return err
 	case "Lecturer":
+		if role == auth.RolePendingLecturer {
+			_, err := b.sender.Message(callback.Message.Chat.ID, msgLecturerPending, nil)
+
+			return err
+		}
+
 		// TODO: запись данных преподавателя в БД (если надо, но скорее всего не понадобится)
 		_, err := b.sender.Message(
 			callback.Message.Chat.ID,
@@ -451,6 +779,18 @@ func (b *Bot) handleQuizStartCallbackUpdate(
 	ctx context.Context,
 	callback *client.CallbackQuery,
 ) error {
+	// в группе кнопку видят все участники, но запустить квиз может только тот, кому это разрешено
+	if isGroupChat(callback.Message.Chat) {
+		allowed, err := b.canAccessRun(ctx, strings.TrimPrefix(callback.Data, "start_quiz "), callback.From.ID, access.PermRun)
+		if err != nil {
+			return err
+		}
+
+		if !allowed {
+			return b.client.AnswerCallback(callback.ID, msgNotQuizOwner)
+		}
+	}
+
 	err := b.client.AnswerCallback(callback.ID, msgQuizRunning)
 	if err != nil {
 		return err
@@ -463,27 +803,61 @@ func (b *Bot) handleQuizStartCallbackUpdate(
 		return err
 	}
 
+	b.saveRun(ctx, runID)
+
 	b.mu.Lock()
 	close(
 		b.runIDToLobbyEndChan[runID],
 	) // квиз запустился => больше нет лобби => больше не запускаем студентов
+	delete(b.runIDToLobbyEndChan, runID)
 	participantsCnt := b.engine.GetParticipantCount(runID)
 	b.mu.Unlock()
 
 	msg := fmt.Sprintf(`Квиз запущен. Количество участников: %d`, participantsCnt)
 
-	_, err = b.client.SendMessage(callback.Message.Chat.ID, msg, nil)
+	keyboard := client.InlineKeyboardMarkup{
+		InlineKeyboard: [][]client.InlineKeyboardButton{
+			{cancelRunButton("Остановить квиз", runID)},
+		},
+	}
+
+	opts := &client.SendOptions{ReplyMarkup: &keyboard}
+
+	startedMessage, err := b.client.SendMessage(callback.Message.Chat.ID, msg, opts)
 	if err != nil {
 		return nil
 	}
 
+	b.mu.Lock()
+	ownerChatID := b.runIDToOwnerChatID[runID]
+	b.mu.Unlock()
+
+	// в группе сообщение о запуске видят все, поэтому панель отправляется преподавателю в личный чат
+	dashboard := startedMessage
+	if ownerChatID != callback.Message.Chat.ID {
+		dashboard, err = b.client.SendMessage(ownerChatID, msg, opts)
+	}
+
+	if err == nil {
+		go b.runDashboard(ctx, runID, dashboard, opts)
+	} else {
+		slog.Error("failed to send dashboard", "error", err, "run", runID)
+	}
+
 	go func() {
 		for event := range events {
+			// события, отправленные движком до отмены, уже не нужны
+			if b.isRunCancelled(runID) {
+				continue
+			}
+
 			switch event.Type {
 			case engine.EventTypeFinished:
 				_ = b.handleFinishedEvent(runID)
 			case engine.EventTypeQuestion:
 				_ = b.handleQuestionEvent(ctx, runID, event)
+			case engine.EventTypeSectionFinished:
+				_ = b.handleSectionFinishedEvent(runID, event)
 			}
 		}
 	}()
@@ -493,32 +867,20 @@ func (b *Bot) handleQuizStartCallbackUpdate(
 
 // handleQuestionEvent отправляет каждому студенту вопрос со счетчиком времени.
 func (b *Bot) handleQuestionEvent(ctx context.Context, runID string, event engine.QuizEvent) error {
-	var builder strings.Builder
-
-	text := fmt.Sprintf("Вопрос %d", event.QuestionIdx+1) + "\n\n"
-	builder.WriteString(text)
-	builder.WriteString(event.Question.Text + "\n\n")
-
 	quiz := b.runIDToQuiz[runID]
 
 	shuffle := quiz.Settings.ShuffleAnswers
 	if event.Question.Shuffle != nil {
 		shuffle = *event.Question.Shuffle
 	}
-	
-	if shuffle {
+
+	if shuffle && !event.Question.IsOpen() {
 		err := b.engine.ShuffleAnswers(&event)
 		if err != nil {
 			return err
 		}
 	}
 
-	for i, option := range event.Question.Options {
-		letter := engine.IndexToLetter(i)
-		text = fmt.Sprintf("%s. %s", letter, option) + "\n"
-		builder.WriteString(text)
-	}
-
 	b.mu.Lock()
 
 	var questionTime int
@@ -528,12 +890,22 @@ func (b *Bot) handleQuestionEvent(ctx context.Context, runID string, event engin
 		questionTime = quiz.Settings.TimePerQuestion
 	}
 
+	b.runIDToQuestion[runID] = &activeQuestion{
+		event:    event,
+		deadline: time.Now().Add(time.Duration(questionTime) * time.Second),
+	}
 	b.mu.Unlock()
 
-	text = "\n" + fmt.Sprintf("Время: %d секунд", questionTime) + "\n\n"
-	builder.WriteString(text)
-	builder.WriteString("Отправьте букву ответа (A, B, C, ...)")
-	msg := builder.String()
+	if groupChatID, ok := b.groupChatID(runID); ok {
+		return b.sendGroupQuestion(ctx, runID, groupChatID, event, questionTime)
+	}
+
+	if quiz.Settings.AnswerMode == engine.AnswerModePoll && !event.Question.IsOpen() {
+		return b.sendQuestionPolls(ctx, runID, b.participantChatIDs(runID), event, questionTime)
+	}
+
+	msg := formatQuestion(event, questionTime, quiz.Settings.AnswerMode, "")
+	opts := questionOptions(runID, event, quiz.Settings.AnswerMode)
 
 	userIDToBotMessage := make(map[int64]*client.Message)
 
@@ -544,7 +916,7 @@ func (b *Bot) handleQuestionEvent(ctx context.Context, runID string, event engin
 			chatID := b.userIDToChatID[userID]
 			b.mu.Unlock()
 
-			botMessage, err := b.client.SendMessage(chatID, msg, nil)
+			botMessage, err := b.client.SendMessage(chatID, msg, opts)
 			if err != nil {
 				return err
 			}
@@ -578,11 +950,13 @@ func (b *Bot) handleEditUserMessage(
 		questionTime = b.runIDToQuiz[runID].Settings.TimePerQuestion
 	}
 
+	answerMode := b.runIDToQuiz[runID].Settings.AnswerMode
 	b.mu.Unlock()
 
 	lim := questionTime
+	opts := questionOptions(runID, event, answerMode)
 
-	Loop:
+Loop:
 	for range lim {
 		select {
 		case <-ctx.Done():
@@ -590,37 +964,29 @@ func (b *Bot) handleEditUserMessage(
 		case <-ticker.C:
 		}
 
-
 		questionTime--
 
-		var str strings.Builder
-
-		text := fmt.Sprintf("Вопрос %d", event.QuestionIdx+1) + "\n\n"
-		str.WriteString(text)
-		str.WriteString(event.Question.Text + "\n\n")
-
-		for i, option := range event.Question.Options {
-			letter := engine.IndexToLetter(i)
-			text = fmt.Sprintf("%s. %s", letter, option) + "\n"
-			str.WriteString(text)
-		}
-
-		text = "\n" + fmt.Sprintf("Время: %d секунд", questionTime) + "\n\n"
-		str.WriteString(text)
-		str.WriteString("Отправьте букву ответа (A, B, C, ...)")
-		msg := str.String()
-
 		b.mu.Lock()
 
 		for userID, runIDForUser := range b.userIDToRunID {
-			if runIDForUser == runID {
-				_ = b.client.EditMessage(
-					b.userIDToChatID[userID],
-					userIDToBotMessage[userID].MessageID,
-					msg,
-					nil,
-				)
+			botMessage, ok := userIDToBotMessage[userID]
+			if runIDForUser != runID || !ok {
+				continue
 			}
+
+			// после ответа или по истечении времени кнопки убираются
+			selected := b.selectionFor(userID, runID, event.QuestionIdx)
+			userOpts := opts
+			if selected != "" || questionTime == 0 {
+				userOpts = nil
+			}
+
+			_ = b.client.EditMessage(
+				b.userIDToChatID[userID],
+				botMessage.MessageID,
+				formatQuestion(event, questionTime, answerMode, selected),
+				userOpts,
+			)
 		}
 
 		b.mu.Unlock()
@@ -629,13 +995,56 @@ func (b *Bot) handleEditUserMessage(
 	return nil
 }
 
-// handleFinishedEvent отправляет студентам и преподавателю результаты квиза.
+// handleFinishedEvent обрабатывает окончание вопросов квиза.
+// Если есть непроверенные открытые ответы, запускает очередь проверки у преподавателя,
+// иначе сразу отправляет результаты.
 func (b *Bot) handleFinishedEvent(runID string) error {
+	b.finishAnswering(context.Background(), runID)
+	// чаты участников для уведомлений берутся до освобождения
+	defer b.releaseParticipants(runID)
+
+	res, err := b.engine.GetResults(runID)
+	if err != nil {
+		return err
+	}
+
+	if res.Pending == 0 {
+		return b.sendResults(runID)
+	}
+
+	b.saveRun(context.Background(), runID)
+
+	chatIDs := b.participantChatIDs(runID)
+	if groupChatID, ok := b.groupChatID(runID); ok {
+		chatIDs = []int64{groupChatID}
+	}
+
+	for _, chatID := range chatIDs {
+		_, err = b.client.SendMessage(chatID, msgResultsPendingGrading, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.startGrading(runID)
+}
+
+// sendResults отправляет студентам и преподавателю итоговые результаты квиза.
+func (b *Bot) sendResults(runID string) error {
 	res, err := b.engine.GetResults(runID)
 	if err != nil {
 		return err
 	}
 
+	run, err := b.engine.GetRun(runID)
+	if err != nil {
+		return err
+	}
+
+	b.saveRun(context.Background(), runID)
+	b.saveRunResults(runID)
+	b.addReviewCards(runID)
+
 	var str strings.Builder
 	str.WriteString("Топ-10:\n")
 
@@ -646,42 +1055,46 @@ func (b *Bot) handleFinishedEvent(runID string) error {
 		str.WriteString(text)
 	}
 
-	idx := 0
 	text := str.String()
 
-	for userID, runIDForUser := range b.userIDToRunID {
-		if runIDForUser == runID {
-			endText := "Квиз %s окончен!\n\nВаш результат: %d баллов (место %d)\n\n%s"
-			msg := fmt.Sprintf(
-				endText,
-				res.QuizTitle,
-				res.Leaderboard[idx].Score,
-				res.Leaderboard[idx].Rank,
-				text,
-			)
-
-			b.mu.Lock()
-			chatID := b.userIDToChatID[userID]
-			b.mu.Unlock()
-
-			_, err = b.client.SendMessage(chatID, msg, nil)
-			if err != nil {
-				return err
-			}
-
-			idx++
+	groupChatID, isGroup := b.groupChatID(runID)
+	if isGroup {
+		_, err = b.client.SendMessage(groupChatID, fmt.Sprintf("Квиз %s окончен!\n\n%s", res.QuizTitle, text), nil)
+		if err != nil {
+			return err
 		}
 	}
 
-	msg := fmt.Sprintf(`Квиз %s окончен. Результаты квиза:`, res.QuizTitle)
+	for _, entry := range res.Leaderboard {
+		userID := entry.Participant.TelegramID
+		// после проверки открытых ответов участники уже освобождены, но в квиз входят
+		// из личного чата, ID которого совпадает с ID пользователя
+		chatID := userID
+
+		endText := "Квиз %s окончен!\n\nВаш результат: %d баллов (место %d)\n\n%s"
+		msg := fmt.Sprintf(
+			endText,
+			res.QuizTitle,
+			entry.Score,
+			entry.Rank,
+			text,
+		)
+		msg += formatHintsUsed(entry.HintsUsed)
+		msg += formatGradingComments(run.Answers[userID])
 
-	b.mu.Lock()
-	ownerChatID := b.runIDToOwnerChatID[runID]
-	b.mu.Unlock()
+		_, err = b.client.SendMessage(chatID, msg, nil)
+		if err == nil {
+			err = b.sendParticipantReport(chatID, runID, userID)
+		}
 
-	_, err = b.sender.Message(ownerChatID, msg, nil)
-	if err != nil {
-		return err
+		// участник группового квиза мог ни разу не писать боту, тогда личный результат не доставить
+		if err != nil && isGroup {
+			slog.Debug("failed to send personal results", "error", err, "user", userID)
+
+			continue
+		} else if err != nil {
+			return err
+		}
 	}
 
 	csvData, err := b.engine.ExportCSV(runID)
@@ -691,5 +1104,88 @@ func (b *Bot) handleFinishedEvent(runID string) error {
 
 	fileName := fmt.Sprintf(`Результаты квиза "%s"`, res.QuizTitle)
 
-	return b.sender.Document(ownerChatID, fileName, csvData)
+	// итоги получают тот, кто провёл квиз, владелец и все, кому квиз открыт;
+	// ошибка важна только для первого: остальные могли ни разу не писать боту
+	for i, recipient := range b.resultRecipients(context.Background(), runID) {
+		msg := fmt.Sprintf("Квиз %s окончен. ID запуска: %s\n\n", res.QuizTitle, runID)
+		if recipient.role.Can(access.PermEdit) {
+			msg += fmt.Sprintf(`Если ключ какого-то вопроса оказался неверным, пересчитайте результаты командой
+/regrade %s <номер вопроса> <буквы правильных вариантов через запятую | any | void>
+
+`, runID)
+		}
+
+		msg += "Результаты квиза:"
+
+		_, err = b.sender.Message(recipient.chatID, msg, nil)
+		if err == nil {
+			err = b.sender.Document(recipient.chatID, fileName, csvData)
+		}
+
+		if err != nil && i == 0 {
+			return err
+		} else if err != nil {
+			slog.Debug("failed to send run results", "error", err, "user", recipient.chatID)
+
+			continue
+		}
+
+		b.sendIntegrityReport(recipient.chatID, run, res.QuizTitle)
+	}
+
+	return nil
+}
+
+// participantChatIDs возвращает чаты всех участников запуска.
+func (b *Bot) participantChatIDs(runID string) []int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var chatIDs []int64
+
+	for userID, runIDForUser := range b.userIDToRunID {
+		if runIDForUser == runID {
+			chatIDs = append(chatIDs, b.userIDToChatID[userID])
+		}
+	}
+
+	return chatIDs
+}
+
+// formatQuestion формирует текст сообщения с вопросом и оставшимся временем.
+// Если участник уже выбрал вариант кнопкой, вместо подсказки о способе ответа выводится его выбор.
+func formatQuestion(event engine.QuizEvent, timeLeft int, answerMode string, selected string) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Вопрос %d", event.QuestionIdx+1) + "\n\n")
+	builder.WriteString(event.Question.Text + "\n\n")
+
+	for i, option := range event.Question.Options {
+		letter := engine.IndexToLetter(i)
+		builder.WriteString(fmt.Sprintf("%s. %s", letter, option) + "\n")
+	}
+
+	builder.WriteString("\n" + fmt.Sprintf("Время: %d секунд", timeLeft) + "\n\n")
+
+	switch {
+	case selected != "":
+		builder.WriteString("Ваш ответ: " + selected)
+	case event.Question.IsOpen():
+		builder.WriteString("Отправьте ответ текстом одним сообщением")
+	case answerMode == engine.AnswerModeButtons:
+		builder.WriteString("Выберите вариант кнопкой ниже")
+	default:
+		builder.WriteString("Отправьте букву ответа (A, B, C, ...)")
+	}
+
+	return builder.String()
+}
+
+// participantName возвращает имя участника для отображения.
+func participantName(participant *engine.Participant) string {
+	if participant.Username != "" {
+		return "@" + participant.Username
+	}
+
+	return strings.TrimSpace(participant.FirstName + " " + participant.LastName)
 }
@@ -0,0 +1,144 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+)
+
+// activeQuestion — текущий вопрос запуска в том виде, в котором его видят участники.
+type activeQuestion struct {
+	event    engine.QuizEvent
+	deadline time.Time
+}
+
+// answerSelection — вариант, выбранный участником кнопкой.
+type answerSelection struct {
+	runID       string
+	questionIdx int
+	text        string
+}
+
+// questionOptions возвращает клавиатуру для сообщения с вопросом: варианты ответа в режиме кнопок
+// и кнопку подсказки, если у вопроса есть подсказки. Если кнопок нет, возвращает nil.
+func questionOptions(runID string, event engine.QuizEvent, answerMode string) *client.SendOptions {
+	var rows [][]client.InlineKeyboardButton
+
+	if answerMode == engine.AnswerModeButtons && !event.Question.IsOpen() {
+		for i := range event.Question.Options {
+			rows = append(rows, []client.InlineKeyboardButton{
+				{
+					Text:         formatOption(event.Question, i),
+					CallbackData: fmt.Sprintf("ans %s %d %d", runID, event.QuestionIdx, i),
+				},
+			})
+		}
+	}
+
+	if len(event.Question.Hints) > 0 {
+		rows = append(rows, []client.InlineKeyboardButton{
+			{
+				Text:         "💡 Подсказка",
+				CallbackData: fmt.Sprintf("hint %s %d", runID, event.QuestionIdx),
+			},
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return &client.SendOptions{
+		ReplyMarkup: &client.InlineKeyboardMarkup{InlineKeyboard: rows},
+	}
+}
+
+// handleAnswerCallbackUpdate принимает ответ, выбранный кнопкой.
+// Формат данных: "ans <runID> <questionIdx> <answerIdx>".
+func (b *Bot) handleAnswerCallbackUpdate(ctx context.Context, callback *client.CallbackQuery) error {
+	data := strings.Fields(callback.Data)
+	if len(data) != 4 {
+		return fmt.Errorf("invalid answer callback data: %q", callback.Data)
+	}
+
+	runID := data[1]
+
+	questionIdx, err := strconv.Atoi(data[2])
+	if err != nil {
+		return fmt.Errorf("invalid question in answer callback: %w", err)
+	}
+
+	answerIdx, err := strconv.Atoi(data[3])
+	if err != nil {
+		return fmt.Errorf("invalid option in answer callback: %w", err)
+	}
+
+	userID := callback.From.ID
+
+	b.mu.Lock()
+	userRunID := b.userIDToRunID[userID]
+	current, hasQuestion := b.runIDToQuestion[runID]
+	quiz, hasQuiz := b.runIDToQuiz[runID]
+	b.mu.Unlock()
+
+	if userRunID != runID || !hasQuiz {
+		return b.client.AnswerCallback(callback.ID, msgNotParticipant)
+	}
+
+	err = b.engine.SubmitAnswer(ctx, runID, userID, questionIdx, answerIdx)
+
+	switch {
+	case errors.Is(err, engine.ErrStaleQuestion):
+		return b.client.AnswerCallback(callback.ID, msgStaleAnswer)
+	case errors.Is(err, engine.ErrAlreadyAnswered):
+		return b.client.AnswerCallback(callback.ID, msgRepeatedAnswer)
+	case err != nil:
+		return b.client.AnswerCallback(callback.ID, msgAnswerRejected)
+	}
+
+	b.mu.Lock()
+	b.userIDToAnswersCnt[userID]++
+	b.mu.Unlock()
+
+	if !hasQuestion || current.event.QuestionIdx != questionIdx {
+		return b.client.AnswerCallback(callback.ID, msgAnswerAcceptance)
+	}
+
+	selected := formatOption(current.event.Question, answerIdx)
+
+	// сообщение с вопросом в группе общее, поэтому выбор показывается только нажавшему
+	if isGroupChat(callback.Message.Chat) {
+		return b.client.AnswerCallback(callback.ID, truncateRunes(msgAnswerAcceptance+" "+selected, maxCallbackTextLen))
+	}
+
+	b.mu.Lock()
+	b.userIDToSelection[userID] = answerSelection{runID: runID, questionIdx: questionIdx, text: selected}
+	b.mu.Unlock()
+
+	err = b.client.AnswerCallback(callback.ID, msgAnswerAcceptance)
+	if err != nil {
+		return err
+	}
+
+	timeLeft := max(0, int(time.Until(current.deadline).Seconds()))
+	msg := formatQuestion(current.event, timeLeft, quiz.Settings.AnswerMode, selected)
+
+	return b.client.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID, msg, nil)
+}
+
+// selectionFor возвращает выбранный участником вариант для вопроса или пустую строку.
+// Вызывается под мьютексом.
+func (b *Bot) selectionFor(userID int64, runID string, questionIdx int) string {
+	selection, ok := b.userIDToSelection[userID]
+	if !ok || selection.runID != runID || selection.questionIdx != questionIdx {
+		return ""
+	}
+
+	return selection.text
+}
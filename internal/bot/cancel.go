@@ -0,0 +1,167 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/letsssgooo/quizBot/internal/access"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+)
+
+// cancelRunButton возвращает кнопку отмены запуска для преподавателя.
+func cancelRunButton(text string, runID string) client.InlineKeyboardButton {
+	return client.InlineKeyboardButton{Text: text, CallbackData: fmt.Sprintf("cancel_run %s", runID)}
+}
+
+// handleCancelRunCallbackUpdate отменяет запуск по кнопке в лобби, в сообщении о запуске или в ответе на /cancel.
+// Формат данных: "cancel_run <runID>".
+func (b *Bot) handleCancelRunCallbackUpdate(ctx context.Context, callback *client.CallbackQuery) error {
+	runID := strings.TrimPrefix(callback.Data, "cancel_run ")
+
+	b.mu.Lock()
+	quiz, ok := b.runIDToQuiz[runID]
+	b.mu.Unlock()
+
+	if !ok {
+		return b.client.AnswerCallback(callback.ID, msgUnknownQuiz)
+	}
+
+	allowed, err := b.canAccessRun(ctx, runID, callback.From.ID, access.PermRun)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return b.client.AnswerCallback(callback.ID, msgNotQuizOwner)
+	}
+
+	err = b.cancelRun(ctx, runID, callback.Message.Chat.ID)
+	if errors.Is(err, engine.ErrNotCancellable) {
+		return b.client.AnswerCallback(callback.ID, msgRunNotCancellable)
+	} else if err != nil {
+		return err
+	}
+
+	if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	return b.client.EditMessage(
+		callback.Message.Chat.ID,
+		callback.Message.MessageID,
+		fmt.Sprintf(msgRunCancelled, quiz.Title),
+		nil,
+	)
+}
+
+// cancelRun отменяет запуск: останавливает квиз в движке, закрывает лобби, выводит участников
+// из режима ответов и сообщает им об отмене. В чат noticeChatID сообщение об отмене не отправляется:
+// там его показывает отредактированное сообщение с кнопкой.
+func (b *Bot) cancelRun(ctx context.Context, runID string, noticeChatID int64) error {
+	if err := b.engine.CancelRun(runID); err != nil {
+		return err
+	}
+
+	b.finishAnswering(ctx, runID)
+	b.saveRun(ctx, runID)
+
+	b.mu.Lock()
+	quiz := b.runIDToQuiz[runID]
+
+	// если квиз уже запущен, лобби закрыто при запуске
+	if lobbyEndChan, ok := b.runIDToLobbyEndChan[runID]; ok {
+		close(lobbyEndChan)
+		delete(b.runIDToLobbyEndChan, runID)
+	}
+
+	var chatIDs []int64
+
+	for userID, runIDForUser := range b.userIDToRunID {
+		if runIDForUser != runID {
+			continue
+		}
+
+		chatIDs = append(chatIDs, b.userIDToChatID[userID])
+	}
+
+	delete(b.runIDToQuestion, runID)
+	b.mu.Unlock()
+
+	b.releaseParticipants(runID)
+
+	// в группе участникам достаточно одного сообщения в самой группе
+	if groupChatID, ok := b.groupChatID(runID); ok {
+		chatIDs = []int64{groupChatID}
+	}
+
+	for _, chatID := range chatIDs {
+		if chatID == noticeChatID {
+			continue
+		}
+
+		_, err := b.client.SendMessage(chatID, fmt.Sprintf(msgRunCancelled, quiz.Title), nil)
+		if err != nil {
+			slog.Error("failed to notify about cancelled run", "error", err, "chat", chatID, "run", runID)
+		}
+	}
+
+	return nil
+}
+
+// isRunCancelled сообщает, отменён ли запуск.
+func (b *Bot) isRunCancelled(runID string) bool {
+	run, err := b.engine.GetRun(runID)
+
+	return err == nil && run.Status == engine.RunStatusCancelled
+}
+
+// ownedActiveRuns возвращает запуски квизов преподавателя и запуски, которые он провёл сам,
+// если их ещё можно отменить. В группе учитывается только запуск этой группы.
+func (b *Bot) ownedActiveRuns(ownerID int64, chat *client.Chat) []*engine.QuizRun {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var runs []*engine.QuizRun
+
+	for runID, quiz := range b.runIDToQuiz {
+		// ID личного чата совпадает с ID пользователя
+		if quiz.OwnerID != ownerID && b.runIDToOwnerChatID[runID] != ownerID {
+			continue
+		}
+
+		if isGroupChat(chat) && b.runIDToGroupChatID[runID] != chat.ID {
+			continue
+		}
+
+		run, err := b.engine.GetRun(runID)
+		if err == nil && (run.Status == engine.RunStatusLobby || run.Status == engine.RunStatusRunning) {
+			runs = append(runs, run)
+		}
+	}
+
+	return runs
+}
+
+// sendCancelRunChoice предлагает преподавателю отменить один из его активных запусков.
+func (b *Bot) sendCancelRunChoice(chatID int64, runs []*engine.QuizRun) error {
+	keyboard := client.InlineKeyboardMarkup{}
+
+	for _, run := range runs {
+		b.mu.Lock()
+		title := b.runIDToQuiz[run.ID].Title
+		b.mu.Unlock()
+
+		text := fmt.Sprintf("Отменить %s (%s)", title, run.StartedAt.Format("02.01 15:04"))
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []client.InlineKeyboardButton{
+			cancelRunButton(text, run.ID),
+		})
+	}
+
+	_, err := b.sender.Message(chatID, msgChooseRunToCancel, &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
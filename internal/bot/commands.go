@@ -0,0 +1,317 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/letsssgooo/quizBot/internal/auth"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/router"
+)
+
+// newRouter регистрирует команды бота и middleware для их обработки.
+func (b *Bot) newRouter() *router.Router {
+	r := router.New(b.botUsername)
+
+	r.Use(
+		router.Recover(slog.Default()),
+		router.Logging(slog.Default()),
+		router.RateLimit(b.limiter.AllowCommand),
+		router.RequireRole(b.roleOf),
+	)
+
+	commands := []router.Command{
+		{
+			Name:        "/start",
+			Description: "Начать работу и выбрать роль",
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleStartCommand(ctx, req.Message, req.Fields)
+			},
+		},
+		{
+			Name:        "/help",
+			Description: "Справка и список команд",
+			ChatTypes:   []string{router.ChatPrivate, router.ChatGroup},
+			Handler: func(_ context.Context, req *router.Request) error {
+				return b.handleHelpCommand(req.Message)
+			},
+		},
+		{
+			Name:        "/cancel",
+			Description: "Отменить текущее действие или свой запуск квиза",
+			ChatTypes:   []string{router.ChatPrivate, router.ChatGroup},
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleCancelCommand(ctx, req.Message)
+			},
+		},
+		{
+			Name:        "/verify",
+			Description: "Подтвердить роль преподавателя кодом: /verify <код>",
+			Role:        auth.RolePendingLecturer,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleVerifyCommand(ctx, req.Message, req.Args())
+			},
+		},
+		{
+			Name:        "/report",
+			Description: "Подробный отчёт по пройденному квизу",
+			Role:        auth.RoleStudent,
+			Handler: func(_ context.Context, req *router.Request) error {
+				return b.handleReportCommand(req.Message, req.Fields)
+			},
+		},
+		{
+			Name:        "/practice",
+			Description: "Пройти квиз ещё раз в режиме тренировки",
+			Role:        auth.RoleStudent,
+			Handler: func(_ context.Context, req *router.Request) error {
+				return b.handlePracticeCommand(req.Message, req.Fields)
+			},
+		},
+		{
+			Name:        "/review",
+			Description: "Повторить вопросы, в которых вы ошиблись",
+			Role:        auth.RoleStudent,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleReviewCommand(ctx, req.Message)
+			},
+		},
+		{
+			Name:        "/stats",
+			Description: "Статистика по пройденным квизам; преподавателю — статистика студента: /stats <имя>",
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleStatsCommand(ctx, req.Message, req.Args())
+			},
+		},
+		{
+			Name:        "/myquizzes",
+			Description: "Сохранённые квизы: скачать, запустить снова, история запусков",
+			Role:        auth.RoleLecturer,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleMyQuizzesCommand(ctx, req.Message)
+			},
+		},
+		{
+			Name:        "/history",
+			Description: "История запусков квиза и их сравнение: /history [название квиза]",
+			Role:        auth.RoleLecturer,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleHistoryCommand(ctx, req.Message, req.Args())
+			},
+		},
+		{
+			Name:        "/regrade",
+			Description: "Перепроверить вопрос с неверным ключом: /regrade <ID запуска> <номер вопроса> <ключ>",
+			Role:        auth.RoleLecturer,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleRegradeCommand(ctx, req.Message, req.Fields)
+			},
+		},
+		{
+			Name:        "/share",
+			Description: "Открыть свой квиз другому преподавателю: /share @username <роль> <название квиза>",
+			Role:        auth.RoleLecturer,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleShareCommand(ctx, req.Message, req.Args())
+			},
+		},
+		{
+			Name:        "/newcourse",
+			Description: "Создать курс: /newcourse <название курса>",
+			Role:        auth.RoleLecturer,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleNewCourseCommand(ctx, req.Message, req.Args())
+			},
+		},
+		{
+			Name:        "/courses",
+			Description: "Курсы: преподавателю — его курсы, журнал и студенты; студенту — курсы, на которые он записан",
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleCoursesCommand(ctx, req.Message)
+			},
+		},
+		{
+			Name:        "/publish",
+			Description: "Опубликовать свой квиз в курсе: /publish <название квиза>",
+			Role:        auth.RoleLecturer,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handlePublishCommand(ctx, req.Message, req.Args())
+			},
+		},
+		{
+			Name:        "/enrollgroup",
+			Description: "Записать на курс учебную группу: /enrollgroup <группа> <название курса>",
+			Role:        auth.RoleLecturer,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleEnrollGroupCommand(ctx, req.Message, req.Args())
+			},
+		},
+		{
+			Name:        "/forgotten",
+			Description: "Вопросы ваших квизов, в которых студенты ошибаются чаще всего",
+			Role:        auth.RoleLecturer,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleForgottenCommand(ctx, req.Message)
+			},
+		},
+		{
+			Name:        "/lecturercode",
+			Description: "Выдать одноразовый код подтверждения преподавателя",
+			Role:        auth.RoleAdmin,
+			Handler: func(_ context.Context, req *router.Request) error {
+				return b.handleLecturerCodeCommand(req.Message)
+			},
+		},
+		{
+			Name:        "/users",
+			Description: "Пользователи с ролью: /users <lecturer|pending|student|admin|none|banned>",
+			Role:        auth.RoleAdmin,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleUsersCommand(ctx, req.Message, req.Args())
+			},
+		},
+		{
+			Name:        "/promote",
+			Description: "Сделать пользователя преподавателем: /promote <ID или @username>",
+			Role:        auth.RoleAdmin,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handlePromoteCommand(ctx, req.Message, req.Args())
+			},
+		},
+		{
+			Name:        "/demote",
+			Description: "Отозвать роль преподавателя: /demote <ID или @username>",
+			Role:        auth.RoleAdmin,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleDemoteCommand(ctx, req.Message, req.Args())
+			},
+		},
+		{
+			Name:        "/ban",
+			Description: "Заблокировать пользователя: /ban <ID или @username> [причина]",
+			Role:        auth.RoleAdmin,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleBanCommand(ctx, req.Message, req.Args())
+			},
+		},
+		{
+			Name:        "/unban",
+			Description: "Снять блокировку: /unban <ID или @username>",
+			Role:        auth.RoleAdmin,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleUnbanCommand(ctx, req.Message, req.Args())
+			},
+		},
+		{
+			Name:        "/botstats",
+			Description: "Статистика бота и активные запуски",
+			Role:        auth.RoleAdmin,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleBotStatsCommand(ctx, req.Message)
+			},
+		},
+		{
+			Name:        "/forcecancel",
+			Description: "Отменить любой активный запуск: /forcecancel <ID запуска>",
+			Role:        auth.RoleAdmin,
+			Handler: func(ctx context.Context, req *router.Request) error {
+				return b.handleForceCancelCommand(ctx, req.Message, req.Args())
+			},
+		},
+	}
+
+	for _, cmd := range commands {
+		if err := r.Register(cmd); err != nil {
+			panic(err) // набор команд статический, ошибка здесь — ошибка программиста
+		}
+	}
+
+	return r
+}
+
+// roleOf возвращает роль пользователя или пустую строку, если роль не выбрана.
+func (b *Bot) roleOf(_ context.Context, userID int64) (string, error) {
+	role, err := b.auth.CheckRole(b.storage, userID)
+	if err != nil || role == nil {
+		return "", err
+	}
+
+	return *role, nil
+}
+
+// handleCommand передаёт команду в роутер и объясняет пользователю, почему она не выполнена.
+// В группах на неизвестные и недоступные там команды бот не отвечает, чтобы не мешать переписке.
+func (b *Bot) handleCommand(ctx context.Context, message *client.Message) error {
+	err := b.router.Handle(ctx, message)
+	isGroup := isGroupChat(message.Chat)
+
+	var msg string
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, router.ErrNotCommand), errors.Is(err, router.ErrUnknownCommand):
+		msg = msgUnknownCommand
+	case errors.Is(err, router.ErrChatNotAllowed):
+		msg = msgPrivateChatOnly
+	case errors.Is(err, router.ErrForbidden):
+		msg = msgStudentsOnly
+
+		fields, _ := b.router.Parse(message.Text)
+		if cmd, ok := b.router.Lookup(fields[0]); ok {
+			switch cmd.Role {
+			case auth.RoleLecturer:
+				msg = msgLecturersOnly
+			case auth.RolePendingLecturer:
+				msg = msgVerifyNotPending
+			case auth.RoleAdmin:
+				msg = msgAdminsOnly
+			}
+		}
+	case errors.Is(err, router.ErrRateLimited):
+		msg = msgTooManyCommands
+	case errors.Is(err, router.ErrPanic):
+		msg = msgCommandFailed
+	default:
+		return err
+	}
+
+	if isGroup && !errors.Is(err, router.ErrRateLimited) && !errors.Is(err, router.ErrPanic) {
+		return nil
+	}
+
+	_, sendErr := b.sender.Message(message.Chat.ID, msg, nil)
+
+	return sendErr
+}
+
+// setCommandMenus задаёт меню команд для пользователей без роли и для групп.
+// Меню для ролей задаётся отдельно каждому пользователю при выборе роли.
+func (b *Bot) setCommandMenus() {
+	menus := []struct {
+		chatType string
+		scope    string
+	}{
+		{chatType: router.ChatPrivate, scope: "all_private_chats"},
+		{chatType: router.ChatGroup, scope: "all_group_chats"},
+	}
+
+	for _, menu := range menus {
+		err := b.client.SetMyCommands(b.router.BotCommands("", menu.chatType), &client.BotCommandScope{Type: menu.scope})
+		if err != nil {
+			slog.Error("failed to set command menu", "error", err, "scope", menu.scope)
+		}
+	}
+}
+
+// setUserCommandMenu задаёт пользователю меню команд его роли в личном чате.
+func (b *Bot) setUserCommandMenu(userID int64, role string) {
+	// в личном чате ID чата совпадает с ID пользователя
+	scope := &client.BotCommandScope{Type: "chat", ChatID: userID}
+
+	err := b.client.SetMyCommands(b.router.BotCommands(role, router.ChatPrivate), scope)
+	if err != nil {
+		slog.Error("failed to set user command menu", "error", err, "user", userID)
+	}
+}
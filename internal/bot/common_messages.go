@@ -24,6 +24,16 @@ const msgNoRights = `Квиз может отправлять только пр
 
 Если вы еще не выбрали свою роль, сделайте это, выполнив команду /start.`
 
+const msgLecturersOnly = `Эта команда доступна только преподавателям.`
+
+const msgStudentsOnly = `Эта команда доступна только студентам. Если вы еще не выбрали свою роль, сделайте это, выполнив команду /start.`
+
+const msgPrivateChatOnly = `Эта команда работает только в личных сообщениях со мной.`
+
+const msgTooManyCommands = `Слишком много команд подряд 🙁. Подождите немного и попробуйте снова.`
+
+const msgCommandFailed = `Не получилось выполнить команду 😔. Попробуйте ещё раз позже.`
+
 const (
 	msgUnknownCommand = `Не понимаю Вас 🤔.
 Отправьте /help, чтобы узнать возможные команды.`
@@ -31,3 +41,24 @@ const (
 	msgUnknownText = `Не понимаю Вас 🤔.
 Используйте команды для взаимодействия со мной или нажмите на панельки под моим ответом, если такое имеется.`
 )
+
+const msgGroupHelp = `Я провожу квизы прямо в этом чате 🤗.
+
+- Преподаватель отправляет сюда JSON-файл с вопросами и нажимает "Начать квиз", когда все готовы.
+- Студенты нажимают "Участвовать" в сообщении лобби и отвечают кнопками или в опросах под вопросами. На открытые вопросы отвечайте ответом (reply) на сообщение с вопросом.
+
+Таблица лидеров публикуется здесь, а личный результат и отчёт я пришлю в личные сообщения, если вы мне уже писали.`
+
+const msgDialogCancelled = `Действие отменено 👌.`
+
+const msgNothingToCancel = `Сейчас нечего отменять.`
+
+const msgCancelInRun = `Вы участвуете в квизе, и он ещё идёт. Выйти из него нельзя: если не хотите продолжать, просто не отвечайте на оставшиеся вопросы.`
+
+const msgDialogExpired = `Вы слишком долго не отвечали, поэтому я отменил текущее действие. Начните его заново.`
+
+const msgDialogBusy = `Сначала завершите текущее действие или отмените его командой /cancel.`
+
+const msgStatsUnavailable = `Статистика недоступна: бот работает без базы данных.`
+
+const msgMuted = `Вы отправляете сообщения слишком часто. Несколько минут бот не будет на них отвечать.`
@@ -0,0 +1,636 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/letsssgooo/quizBot/internal/analytics"
+	"github.com/letsssgooo/quizBot/internal/auth"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+// courseInviteLink возвращает ссылку для записи на курс.
+func (b *Bot) courseInviteLink(course *models.CourseModel) string {
+	return fmt.Sprintf("https://t.me/%s?start=course_%s", b.botUsername, course.InviteCode)
+}
+
+// canJoinRun сообщает, может ли пользователь войти в запуск. Запуски квиза, опубликованного в курсах,
+// открыты только студентам этих курсов. Остальные запуски открыты всем.
+func (b *Bot) canJoinRun(ctx context.Context, runID string, userID int64) (bool, error) {
+	b.mu.Lock()
+	savedQuizID, saved := b.runIDToSavedQuizID[runID]
+	b.mu.Unlock()
+
+	courseStorage, ok := b.storage.(storage.CourseStorage)
+	if !ok || !saved {
+		return true, nil
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	published, enrolled, err := courseStorage.QuizCourseAccess(ctx, savedQuizID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return !published || enrolled, nil
+}
+
+// handleNewCourseCommand создаёт курс: /newcourse <название курса>.
+func (b *Bot) handleNewCourseCommand(ctx context.Context, message *client.Message, args []string) error {
+	courseStorage, ok := b.storage.(storage.CourseStorage)
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgCoursesUnavailable, nil)
+
+		return err
+	}
+
+	title := strings.TrimSpace(strings.Join(args, " "))
+	if title == "" {
+		_, err := b.sender.Message(message.Chat.ID, msgNewCourseUsage, nil)
+
+		return err
+	}
+
+	course := &models.CourseModel{
+		OwnerID:    message.From.ID,
+		Title:      title,
+		InviteCode: strings.ReplaceAll(uuid.NewString(), "-", ""),
+		CreatedAt:  time.Now(),
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	err := courseStorage.CreateCourse(storageCtx, course)
+	if errors.Is(err, storage.ErrCourseAlreadyExists) {
+		_, err = b.sender.Message(message.Chat.ID, msgCourseExists, nil)
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	_, err = b.sender.Message(
+		message.Chat.ID,
+		fmt.Sprintf(msgCourseCreated, course.Title, b.courseInviteLink(course), course.Title),
+		nil,
+	)
+
+	return err
+}
+
+// handleCoursesCommand обрабатывает /courses: преподавателю показывает его курсы,
+// студенту — курсы, на которые он записан, с опубликованными в них квизами.
+func (b *Bot) handleCoursesCommand(ctx context.Context, message *client.Message) error {
+	courseStorage, ok := b.storage.(storage.CourseStorage)
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgCoursesUnavailable, nil)
+
+		return err
+	}
+
+	role, err := b.roleOf(ctx, message.From.ID)
+	if err != nil {
+		return err
+	}
+
+	switch role {
+	case auth.RoleLecturer:
+		return b.sendOwnedCourses(ctx, courseStorage, message.Chat.ID, message.From.ID)
+	case auth.RoleStudent:
+		return b.sendEnrolledCourses(ctx, courseStorage, message.Chat.ID, message.From.ID)
+	default:
+		_, err = b.sender.Message(message.Chat.ID, msgCoursesNoRole, nil)
+
+		return err
+	}
+}
+
+// sendOwnedCourses показывает курсы преподавателя кнопками.
+func (b *Bot) sendOwnedCourses(
+	ctx context.Context,
+	courseStorage storage.CourseStorage,
+	chatID, ownerID int64,
+) error {
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	courses, err := courseStorage.ListCourses(storageCtx, ownerID)
+	if err != nil {
+		return err
+	}
+
+	if len(courses) == 0 {
+		_, err = b.sender.Message(chatID, msgNoCourses, nil)
+
+		return err
+	}
+
+	keyboard := client.InlineKeyboardMarkup{}
+
+	for _, course := range courses {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []client.InlineKeyboardButton{
+			{
+				Text: fmt.Sprintf(
+					"%s (студентов: %d, квизов: %d)",
+					course.Title,
+					course.StudentsCount,
+					course.QuizzesCount,
+				),
+				CallbackData: fmt.Sprintf("course open %d", course.ID),
+			},
+		})
+	}
+
+	_, err = b.sender.Message(chatID, msgChooseCourse, &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
+
+// sendEnrolledCourses показывает студенту его курсы и опубликованные в них квизы.
+func (b *Bot) sendEnrolledCourses(
+	ctx context.Context,
+	courseStorage storage.CourseStorage,
+	chatID, userID int64,
+) error {
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	courses, err := courseStorage.ListEnrolledCourses(storageCtx, userID)
+	if err != nil {
+		return err
+	}
+
+	if len(courses) == 0 {
+		_, err = b.sender.Message(chatID, msgNoEnrolledCourses, nil)
+
+		return err
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString("Ваши курсы 🎓")
+
+	for _, course := range courses {
+		quizzes, err := courseStorage.ListCourseQuizzes(storageCtx, course.ID)
+		if err != nil {
+			return err
+		}
+
+		builder.WriteString(fmt.Sprintf("\n\n%s", course.Title))
+
+		if len(quizzes) == 0 {
+			builder.WriteString("\nКвизов пока нет")
+		}
+
+		for _, quiz := range quizzes {
+			builder.WriteString(fmt.Sprintf("\n- %s%s", quiz.Title, deletedMark(quiz)))
+		}
+	}
+
+	_, err = b.sender.Message(chatID, builder.String(), nil)
+
+	return err
+}
+
+// handleCourseCallbackUpdate обрабатывает кнопки курса.
+// Формат данных: "course <действие> <courseID>" и "course pub <courseID> <quizID>".
+func (b *Bot) handleCourseCallbackUpdate(ctx context.Context, callback *client.CallbackQuery) error {
+	fields := strings.Fields(callback.Data)
+	if len(fields) < 3 {
+		return b.client.AnswerCallback(callback.ID, "")
+	}
+
+	courseStorage, ok := b.storage.(storage.CourseStorage)
+	if !ok {
+		return b.client.AnswerCallback(callback.ID, msgCoursesUnavailable)
+	}
+
+	courseID, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return b.client.AnswerCallback(callback.ID, "")
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	course, err := courseStorage.GetCourse(storageCtx, courseID)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		return b.client.AnswerCallback(callback.ID, msgCourseNotFound)
+	} else if err != nil {
+		return err
+	}
+
+	if course.OwnerID != callback.From.ID {
+		return b.client.AnswerCallback(callback.ID, msgNotCourseOwner)
+	}
+
+	switch fields[1] {
+	case "open":
+		return b.handleCourseOpen(storageCtx, courseStorage, callback, course)
+	case "students":
+		return b.handleCourseStudents(storageCtx, courseStorage, callback, course)
+	case "book":
+		return b.handleCourseGradebook(storageCtx, courseStorage, callback, course)
+	case "pub":
+		if len(fields) != 4 {
+			return b.client.AnswerCallback(callback.ID, "")
+		}
+
+		return b.handleCoursePublish(storageCtx, courseStorage, callback, course, fields[3])
+	default:
+		return b.client.AnswerCallback(callback.ID, "")
+	}
+}
+
+// handleCourseOpen показывает карточку курса: ссылку для записи, квизы и кнопки журнала и списка студентов.
+func (b *Bot) handleCourseOpen(
+	ctx context.Context,
+	courseStorage storage.CourseStorage,
+	callback *client.CallbackQuery,
+	course *models.CourseModel,
+) error {
+	quizzes, err := courseStorage.ListCourseQuizzes(ctx, course.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Курс %s\nСсылка для записи: %s\n\nКвизы:", course.Title, b.courseInviteLink(course)))
+
+	if len(quizzes) == 0 {
+		builder.WriteString(" пока нет, опубликуйте квиз командой /publish <название квиза>")
+	}
+
+	for _, quiz := range quizzes {
+		builder.WriteString(fmt.Sprintf("\n- %s (запусков: %d)%s", quiz.Title, quiz.RunsCount, deletedMark(quiz)))
+	}
+
+	keyboard := client.InlineKeyboardMarkup{
+		InlineKeyboard: [][]client.InlineKeyboardButton{
+			{
+				{Text: "Журнал", CallbackData: fmt.Sprintf("course book %d", course.ID)},
+				{Text: "Студенты", CallbackData: fmt.Sprintf("course students %d", course.ID)},
+			},
+		},
+	}
+
+	_, err = b.sender.Message(callback.Message.Chat.ID, builder.String(), &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
+
+// handleCourseStudents показывает студентов курса.
+func (b *Bot) handleCourseStudents(
+	ctx context.Context,
+	courseStorage storage.CourseStorage,
+	callback *client.CallbackQuery,
+	course *models.CourseModel,
+) error {
+	students, err := courseStorage.ListCourseStudents(ctx, course.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	if len(students) == 0 {
+		_, err = b.sender.Message(callback.Message.Chat.ID, msgNoCourseStudents, nil)
+
+		return err
+	}
+
+	lines := make([]string, 0, len(students))
+
+	for _, student := range students {
+		line := "- " + userLabel(student.TelegramID, student.Username, student.FullName)
+		if student.Group != "" {
+			line += ", " + student.Group
+		}
+
+		lines = append(lines, line)
+	}
+
+	text := fmt.Sprintf("Студенты курса %s (%d):\n%s", course.Title, len(students), strings.Join(lines, "\n"))
+	_, err = b.sender.Message(callback.Message.Chat.ID, text, nil)
+
+	return err
+}
+
+// handleCourseGradebook отправляет журнал курса CSV файлом: лучший балл каждого студента по каждому квизу курса.
+func (b *Bot) handleCourseGradebook(
+	ctx context.Context,
+	courseStorage storage.CourseStorage,
+	callback *client.CallbackQuery,
+	course *models.CourseModel,
+) error {
+	students, err := courseStorage.ListCourseStudents(ctx, course.ID)
+	if err != nil {
+		return err
+	}
+
+	quizzes, err := courseStorage.ListCourseQuizzes(ctx, course.ID)
+	if err != nil {
+		return err
+	}
+
+	results, err := courseStorage.ListCourseResults(ctx, course.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(students) == 0 {
+		return b.client.AnswerCallback(callback.ID, msgNoCourseStudents)
+	}
+
+	if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	data, err := gradebookCSV(analytics.BuildGradebook(students, quizzes, results))
+	if err != nil {
+		return err
+	}
+
+	return b.sender.Document(callback.Message.Chat.ID, fmt.Sprintf("Журнал %s.csv", course.Title), data)
+}
+
+// deletedMark возвращает пометку для удалённого квиза курса: его результаты остаются в журнале.
+func deletedMark(quiz *models.QuizModel) string {
+	if quiz.Deleted {
+		return " — удалён"
+	}
+
+	return ""
+}
+
+// gradebookCSV формирует CSV журнала курса. Не пройденный квиз остаётся пустой ячейкой.
+func gradebookCSV(book analytics.Gradebook) ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := []string{"TelegramID", "Username", "FullName", "Group"}
+	for _, quiz := range book.Quizzes {
+		header = append(header, quiz.Title)
+	}
+
+	header = append(header, "Passed", "Attempts", "Total")
+
+	w := csv.NewWriter(&buf)
+	_ = w.Write(header)
+
+	for _, row := range book.Rows {
+		record := []string{
+			strconv.FormatInt(row.Student.TelegramID, 10),
+			row.Student.Username,
+			row.Student.FullName,
+			row.Student.Group,
+		}
+
+		for i, score := range row.Scores {
+			cell := ""
+			if row.Taken[i] {
+				cell = strconv.Itoa(score)
+			}
+
+			record = append(record, cell)
+		}
+
+		record = append(record, strconv.Itoa(row.Passed), strconv.Itoa(row.Attempts), strconv.Itoa(row.Total))
+		_ = w.Write(record)
+	}
+
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to flush buffer: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// handlePublishCommand предлагает выбрать курс, в котором опубликовать свой квиз: /publish <название квиза>.
+func (b *Bot) handlePublishCommand(ctx context.Context, message *client.Message, args []string) error {
+	courseStorage, ok := b.storage.(storage.CourseStorage)
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgCoursesUnavailable, nil)
+
+		return err
+	}
+
+	if len(args) == 0 {
+		_, err := b.sender.Message(message.Chat.ID, msgPublishUsage, nil)
+
+		return err
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	// публикация ограничивает вход в запуски, поэтому опубликовать можно только свой квиз
+	quizzes, err := b.storage.(storage.QuizStorage).ListQuizzes(storageCtx, message.From.ID, maxListedQuizzes)
+	if err != nil {
+		return err
+	}
+
+	quiz := findSavedQuizByTitle(quizzes, strings.Join(args, " "))
+	if quiz == nil {
+		_, err = b.sender.Message(message.Chat.ID, msgHistoryQuizNotFound, nil)
+
+		return err
+	}
+
+	courses, err := courseStorage.ListCourses(storageCtx, message.From.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(courses) == 0 {
+		_, err = b.sender.Message(message.Chat.ID, msgNoCourses, nil)
+
+		return err
+	}
+
+	keyboard := client.InlineKeyboardMarkup{}
+
+	for _, course := range courses {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []client.InlineKeyboardButton{
+			{Text: course.Title, CallbackData: fmt.Sprintf("course pub %d %s", course.ID, quiz.ID)},
+		})
+	}
+
+	_, err = b.sender.Message(
+		message.Chat.ID,
+		fmt.Sprintf(msgChoosePublishCourse, quiz.Title),
+		&client.SendOptions{ReplyMarkup: &keyboard},
+	)
+
+	return err
+}
+
+// handleCoursePublish публикует квиз в курсе по кнопке из /publish.
+func (b *Bot) handleCoursePublish(
+	ctx context.Context,
+	courseStorage storage.CourseStorage,
+	callback *client.CallbackQuery,
+	course *models.CourseModel,
+	quizID string,
+) error {
+	quiz, err := b.storage.(storage.QuizStorage).GetQuiz(ctx, quizID)
+	if errors.Is(err, storage.ErrQuizNotFound) {
+		return b.client.AnswerCallback(callback.ID, msgSavedQuizNotFound)
+	} else if err != nil {
+		return err
+	}
+
+	if quiz.OwnerID != callback.From.ID {
+		return b.client.AnswerCallback(callback.ID, msgNotQuizOwner)
+	}
+
+	published, err := courseStorage.PublishQuiz(ctx, course.ID, quiz.ID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf(msgQuizPublished, quiz.Title, course.Title)
+	if !published {
+		text = fmt.Sprintf(msgQuizAlreadyPublished, quiz.Title, course.Title)
+	}
+
+	return b.client.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID, text, nil)
+}
+
+// handleEnrollGroupCommand записывает на курс всех студентов учебной группы: /enrollgroup <группа> <название курса>.
+func (b *Bot) handleEnrollGroupCommand(ctx context.Context, message *client.Message, args []string) error {
+	courseStorage, ok := b.storage.(storage.CourseStorage)
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgCoursesUnavailable, nil)
+
+		return err
+	}
+
+	if len(args) < 2 {
+		_, err := b.sender.Message(message.Chat.ID, msgEnrollGroupUsage, nil)
+
+		return err
+	}
+
+	group, err := auth.ParseField(auth.FieldGroup, args[0])
+	if err != nil {
+		_, err = b.sender.Message(message.Chat.ID, msgEnrollGroupUsage, nil)
+
+		return err
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	courses, err := courseStorage.ListCourses(storageCtx, message.From.ID)
+	if err != nil {
+		return err
+	}
+
+	course := findCourseByTitle(courses, strings.Join(args[1:], " "))
+	if course == nil {
+		_, err = b.sender.Message(message.Chat.ID, msgCourseNotFound, nil)
+
+		return err
+	}
+
+	enrolled, err := courseStorage.EnrollGroup(storageCtx, course.ID, group, time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, fmt.Sprintf(msgGroupEnrolled, course.Title, group, enrolled), nil)
+
+	return err
+}
+
+// handleCourseJoin записывает студента на курс по ссылке /start course_<code>.
+func (b *Bot) handleCourseJoin(ctx context.Context, message *client.Message, code string) error {
+	courseStorage, ok := b.storage.(storage.CourseStorage)
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgCoursesUnavailable, nil)
+
+		return err
+	}
+
+	role, err := b.roleOf(ctx, message.From.ID)
+	if err != nil {
+		return err
+	}
+
+	if role != auth.RoleStudent {
+		_, err = b.sender.Message(message.Chat.ID, msgCourseNotStudent, nil)
+
+		return err
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	course, err := courseStorage.GetCourseByInvite(storageCtx, code)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		_, err = b.sender.Message(message.Chat.ID, msgCourseInviteInvalid, nil)
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	enrolled, err := courseStorage.Enroll(storageCtx, course.ID, message.From.ID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf(msgCourseEnrolled, course.Title)
+	if !enrolled {
+		text = fmt.Sprintf(msgCourseAlreadyEnrolled, course.Title)
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, text, nil)
+
+	return err
+}
+
+// findCourseByTitle ищет курс по названию без учёта регистра: сначала точное совпадение, затем по подстроке.
+func findCourseByTitle(courses []*models.CourseModel, title string) *models.CourseModel {
+	title = strings.ToLower(strings.TrimSpace(title))
+
+	for _, course := range courses {
+		if strings.ToLower(course.Title) == title {
+			return course
+		}
+	}
+
+	for _, course := range courses {
+		if strings.Contains(strings.ToLower(course.Title), title) {
+			return course
+		}
+	}
+
+	return nil
+}
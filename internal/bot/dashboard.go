@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+)
+
+const (
+	dashboardUpdatePeriod   = 3 * time.Second // как часто обновляется панель преподавателя
+	dashboardTopSize        = 5
+	maxDashboardQuestionLen = 200 // длина текста вопроса на панели
+)
+
+// runDashboard обновляет сообщение message в чате преподавателя, пока идёт квиз: текущий вопрос,
+// оставшееся время, число ответивших, распределение ответов по вариантам и топ участников.
+// После окончания квиза кнопка остановки убирается, после отмены сообщение не трогается:
+// его уже заменил обработчик отмены.
+func (b *Bot) runDashboard(ctx context.Context, runID string, message *client.Message, opts *client.SendOptions) {
+	ticker := time.NewTicker(dashboardUpdatePeriod)
+	defer ticker.Stop()
+
+	b.mu.Lock()
+	title := b.runIDToQuiz[runID].Title
+	b.mu.Unlock()
+
+	prevText := message.Text
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		snapshot, err := b.engine.GetLiveSnapshot(runID, dashboardTopSize)
+		if errors.Is(err, engine.ErrNotRunning) {
+			if !b.isRunCancelled(runID) {
+				_ = b.client.EditMessage(message.Chat.ID, message.MessageID, fmt.Sprintf(msgDashboardFinished, title), nil)
+			}
+
+			return
+		} else if err != nil {
+			slog.Debug("failed to get live snapshot", "error", err, "run", runID)
+
+			return
+		}
+
+		text := formatDashboard(title, snapshot)
+		if text == prevText {
+			continue
+		}
+
+		prevText = text
+
+		_ = b.client.EditMessage(message.Chat.ID, message.MessageID, text, opts)
+	}
+}
+
+// formatDashboard формирует текст панели преподавателя.
+func formatDashboard(title string, snapshot *engine.LiveSnapshot) string {
+	var str strings.Builder
+
+	str.WriteString(fmt.Sprintf("Квиз %s идёт. Участников: %d\n\n", title, snapshot.Participants))
+
+	if snapshot.QuestionIdx < 0 {
+		str.WriteString("Первый вопрос сейчас появится.")
+
+		return str.String()
+	}
+
+	str.WriteString(fmt.Sprintf(
+		"Вопрос %d из %d, осталось %d сек.\n%s\n\n",
+		snapshot.QuestionIdx+1,
+		snapshot.QuestionsCount,
+		int(snapshot.TimeLeft.Round(time.Second)/time.Second),
+		truncateRunes(snapshot.Question.Text, maxDashboardQuestionLen),
+	))
+
+	str.WriteString(fmt.Sprintf("Ответили: %d из %d\n", snapshot.Answered, snapshot.Participants))
+
+	for i, cnt := range snapshot.Distribution {
+		share := 0.0
+		if snapshot.Answered > 0 {
+			share = float64(cnt) / float64(snapshot.Answered) * 100
+		}
+
+		mark := ""
+		if snapshot.Question.IsCorrectOption(i) {
+			mark = " ✅"
+		}
+
+		str.WriteString(fmt.Sprintf("%s%s: %d (%.0f%%)\n", engine.IndexToLetter(i), mark, cnt, share))
+	}
+
+	if len(snapshot.Top) > 0 {
+		str.WriteString(fmt.Sprintf("\nТоп-%d:\n", dashboardTopSize))
+
+		for _, entry := range snapshot.Top {
+			str.WriteString(fmt.Sprintf("%d. %s - %d баллов\n", entry.Rank, participantName(entry.Participant), entry.Score))
+		}
+	}
+
+	return str.String()
+}
@@ -0,0 +1,230 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/auth"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/dialog"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+const dialogCheckPeriod = time.Minute // как часто ищутся истёкшие диалоги
+
+// newDialogManager создаёт менеджер диалогов. Если хранилище не умеет сохранять диалоги,
+// они живут только в памяти.
+func newDialogManager(st storage.Storage) *dialog.Manager {
+	dialogStorage, _ := st.(storage.DialogStorage)
+
+	return dialog.NewManager(dialogStorage)
+}
+
+// handleDialogMessage обрабатывает сообщение, которое не является командой, в зависимости от диалога пользователя.
+// Возвращает false, если пользователь не находится в диалоге и сообщение нужно обработать как обычно.
+func (b *Bot) handleDialogMessage(ctx context.Context, message *client.Message) (bool, error) {
+	current, expired, err := b.dialogs.Current(ctx, message.From.ID, time.Now())
+	if err != nil {
+		slog.Error("failed to get dialog", "error", err, "user", message.From.ID)
+	}
+
+	// сообщения принимают только регистрация и заполнение полей квиза, о других истёкших диалогах здесь не сообщается
+	if expired && (current.State == dialog.StateAwaitingRegistration || current.State == dialog.StateAwaitingRunFields) {
+		_, err = b.sender.Message(message.Chat.ID, msgDialogExpired, nil)
+
+		return true, err
+	}
+
+	switch current.State {
+	case dialog.StateAwaitingAnswer:
+		runID := current.Data[dialog.KeyRunID]
+
+		// после перезапуска бота запуска уже нет в памяти
+		if _, err = b.engine.GetRun(runID); err != nil {
+			b.resetDialog(ctx, message.From.ID)
+
+			return false, nil
+		}
+
+		return true, b.handleAnswerUpdate(ctx, message.Chat.ID, message.From.ID, message.Text, runID)
+	case dialog.StateAwaitingRegistration:
+		return true, b.handleRegistrationMessage(ctx, message)
+	case dialog.StateAwaitingRunFields:
+		return true, b.handleRunFieldMessage(ctx, message, current)
+	default:
+		return false, nil
+	}
+}
+
+// handleRegistrationMessage сохраняет ФИО и группу студента.
+// При ошибке в данных диалог продолжается, пока студент не пришлёт корректные данные или /cancel.
+func (b *Bot) handleRegistrationMessage(ctx context.Context, message *client.Message) error {
+	text := strings.Fields(message.Text)
+	if len(text) == 0 {
+		_, err := b.sender.Message(message.Chat.ID, msgStudentsDataMistake, nil)
+
+		return err
+	}
+
+	err := b.auth.UpdateStudentData(b.storage, message.From.ID, text)
+	if errors.Is(err, auth.ErrValidation) {
+		slog.Debug("incorrect student data: ", "error", err)
+
+		_, err = b.sender.Message(message.Chat.ID, msgStudentsDataMistake, nil)
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	b.resetDialog(ctx, message.From.ID)
+
+	_, err = b.sender.Message(message.Chat.ID, msgStudentsSuccessfullVerification, nil)
+
+	return err
+}
+
+// startAnswering переводит участника в режим ответов: его сообщения считаются ответами в запуске runID.
+func (b *Bot) startAnswering(ctx context.Context, userID int64, runID string) {
+	data := map[string]string{dialog.KeyRunID: runID}
+
+	// регистрация, начатая до входа в квиз, прерывается
+	b.resetDialogState(ctx, userID, dialog.StateAwaitingRegistration)
+
+	err := b.dialogs.Transition(ctx, userID, dialog.StateAwaitingAnswer, data, time.Now())
+	if err != nil {
+		slog.Error("failed to start answering", "error", err, "user", userID, "run", runID)
+	}
+}
+
+// finishAnswering выводит участников запуска из режима ответов.
+func (b *Bot) finishAnswering(ctx context.Context, runID string) {
+	run, err := b.engine.GetRun(runID)
+	if err != nil {
+		return
+	}
+
+	for userID := range run.Participants {
+		current, _, err := b.dialogs.Current(ctx, userID, time.Now())
+		if err != nil {
+			slog.Error("failed to get dialog", "error", err, "user", userID)
+
+			continue
+		}
+
+		if current.State == dialog.StateAwaitingAnswer && current.Data[dialog.KeyRunID] == runID {
+			b.resetDialog(ctx, userID)
+		}
+	}
+}
+
+// handleCancelCommand обрабатывает /cancel: прерывает текущий диалог, тренировку или повторение.
+// Режим ответов в идущем квизе не прерывается: участник остаётся в запуске.
+// Если прерывать нечего, автору активных запусков предлагается отменить один из них.
+// В группе команда отменяет только запуск этой группы.
+func (b *Bot) handleCancelCommand(ctx context.Context, message *client.Message) error {
+	userID := message.From.ID
+
+	if isGroupChat(message.Chat) {
+		runs := b.ownedActiveRuns(userID, message.Chat)
+		if len(runs) == 0 {
+			return nil
+		}
+
+		return b.sendCancelRunChoice(message.Chat.ID, runs)
+	}
+
+	current, _, err := b.dialogs.Current(ctx, userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	_, isPractising := b.userIDToPractice[userID]
+	_, isReviewing := b.userIDToReview[userID]
+	delete(b.userIDToPractice, userID)
+	delete(b.userIDToReview, userID)
+	b.mu.Unlock()
+
+	inRun := current.State == dialog.StateAwaitingAnswer && b.inActiveRun(userID)
+	if inRun && !isPractising && !isReviewing {
+		_, err = b.sender.Message(message.Chat.ID, msgCancelInRun, nil)
+
+		return err
+	}
+
+	if current.State == dialog.StateIdle && !isPractising && !isReviewing {
+		if runs := b.ownedActiveRuns(userID, message.Chat); len(runs) > 0 {
+			return b.sendCancelRunChoice(message.Chat.ID, runs)
+		}
+
+		_, err = b.sender.Message(message.Chat.ID, msgNothingToCancel, nil)
+
+		return err
+	}
+
+	if !inRun {
+		b.resetDialog(ctx, userID)
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, msgDialogCancelled, nil)
+
+	return err
+}
+
+// runDialogTimeouts периодически сбрасывает истёкшие диалоги и сообщает об этом пользователям.
+func (b *Bot) runDialogTimeouts(ctx context.Context) {
+	ticker := time.NewTicker(dialogCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			expired, err := b.dialogs.Expire(ctx, now)
+			if err != nil {
+				slog.Error("failed to expire dialogs", "error", err)
+			}
+
+			for _, current := range expired {
+				if !notifyOnTimeout(current.State) {
+					continue
+				}
+
+				// в личном чате ID чата совпадает с ID пользователя
+				_, err = b.client.SendMessage(current.UserID, msgDialogExpired, nil)
+				if err != nil {
+					slog.Error("failed to send dialog timeout", "error", err, "user", current.UserID)
+				}
+			}
+		}
+	}
+}
+
+// resetDialog завершает диалог пользователя. Ошибка хранилища только логируется:
+// в памяти диалог уже сброшен.
+func (b *Bot) resetDialog(ctx context.Context, userID int64) {
+	if err := b.dialogs.Reset(ctx, userID); err != nil {
+		slog.Error("failed to reset dialog", "error", err, "user", userID)
+	}
+}
+
+// resetDialogState завершает диалог пользователя, если он находится в состоянии state.
+func (b *Bot) resetDialogState(ctx context.Context, userID int64, state dialog.State) {
+	if err := b.dialogs.ResetState(ctx, userID, state); err != nil {
+		slog.Error("failed to reset dialog", "error", err, "user", userID)
+	}
+}
+
+// notifyOnTimeout сообщает, нужно ли предупреждать пользователя об истечении диалога в состоянии state.
+// О завершении режима ответов не сообщается: к этому времени запуск давно закончен.
+// О карточке квиза и выборе запуска для сравнения тоже: бот не ждёт от пользователя сообщения.
+func notifyOnTimeout(state dialog.State) bool {
+	return state == dialog.StateAwaitingRegistration ||
+		state == dialog.StateAwaitingRunFields ||
+		state == dialog.StateConfirmingDelete
+}
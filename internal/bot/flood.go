@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"log/slog"
+
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/ratelimit"
+)
+
+// allowUpdate пропускает обновление через лимитер, пока до него не дошла обработка: отброшенное обновление
+// не стоит ни запроса в БД, ни ответа. Заглушённый пользователь один раз получает предупреждение.
+func (b *Bot) allowUpdate(update client.Update) bool {
+	userID, chatID, ok := updateSource(update)
+	if !ok {
+		return true
+	}
+
+	decision := b.limiter.Allow(userID, chatID)
+	if decision == ratelimit.Allow {
+		return true
+	}
+
+	slog.Debug("update dropped by rate limiter", "user", userID, "chat", chatID, "update", update.UpdateID)
+
+	if decision != ratelimit.Warn {
+		return false
+	}
+
+	var err error
+
+	// на кнопку можно ответить уведомлением, не отправляя сообщений в чат
+	if update.CallbackQuery != nil {
+		err = b.client.AnswerCallback(update.CallbackQuery.ID, msgMuted)
+	} else {
+		// в личном чате ID чата совпадает с ID пользователя, в группу предупреждение не отправляется
+		_, err = b.client.SendMessage(userID, msgMuted, nil)
+	}
+
+	if err != nil {
+		slog.Debug("failed to warn muted user", "error", err, "user", userID)
+	}
+
+	return false
+}
+
+// updateSource возвращает автора обновления и чат, лимит которого оно расходует.
+// Для нажатий кнопок и ответов на опросы чат не учитывается: они не порождают сообщений в чате.
+func updateSource(update client.Update) (userID, chatID int64, ok bool) {
+	switch {
+	case update.Message != nil && update.Message.From != nil:
+		if update.Message.Chat != nil {
+			chatID = update.Message.Chat.ID
+		}
+
+		return update.Message.From.ID, chatID, true
+	case update.CallbackQuery != nil && update.CallbackQuery.From != nil:
+		return update.CallbackQuery.From.ID, 0, true
+	case update.PollAnswer != nil && update.PollAnswer.User != nil:
+		return update.PollAnswer.User.ID, 0, true
+	default:
+		return 0, 0, false
+	}
+}
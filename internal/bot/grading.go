@@ -0,0 +1,270 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/letsssgooo/quizBot/internal/access"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+)
+
+// gradedAnswer — открытый ответ участника, ожидающий оценки.
+type gradedAnswer struct {
+	runID         string
+	participantID int64
+	questionIdx   int
+}
+
+// messageRef — сообщение в чате.
+type messageRef struct {
+	chatID    int64
+	messageID int
+}
+
+// startGrading открывает очередь проверки открытых ответов запуска у проверяющего.
+func (b *Bot) startGrading(runID string) error {
+	graderID := b.graderID(runID)
+
+	b.mu.Lock()
+	b.runIDToGraderID[runID] = graderID
+	b.mu.Unlock()
+
+	return b.sendNextPendingAnswer(runID)
+}
+
+// finishGrading закрывает очередь проверки запуска и забывает комментарии к его ответам.
+func (b *Bot) finishGrading(runID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.runIDToGraderID, runID)
+
+	for ref, answer := range b.gradingMessages {
+		if answer.runID == runID {
+			delete(b.gradingMessages, ref)
+		}
+	}
+
+	for answer := range b.gradingComments {
+		if answer.runID == runID {
+			delete(b.gradingComments, answer)
+		}
+	}
+}
+
+// graderID возвращает того, кто проверяет открытые ответы запуска: того, кто провёл квиз,
+// если его роль разрешает проверку, иначе владельца квиза. ID личного чата совпадает с ID пользователя.
+func (b *Bot) graderID(runID string) int64 {
+	b.mu.Lock()
+	starterID := b.runIDToOwnerChatID[runID]
+	ownerID := b.runIDToQuiz[runID].OwnerID
+	b.mu.Unlock()
+
+	allowed, err := b.canAccessRun(context.Background(), runID, starterID, access.PermEdit)
+	if err != nil {
+		slog.Error("failed to check grading access", "error", err, "run", runID)
+	}
+
+	if allowed {
+		return starterID
+	}
+
+	return ownerID
+}
+
+// sendNextPendingAnswer отправляет преподавателю следующий непроверенный ответ
+// с кнопками для выставления баллов.
+func (b *Bot) sendNextPendingAnswer(runID string) error {
+	pending, err := b.engine.GetPendingAnswers(runID)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	run, err := b.engine.GetRun(runID)
+	if err != nil {
+		return err
+	}
+
+	answer := pending[0]
+	text := fmt.Sprintf(
+		msgGradingAnswer,
+		len(pending),
+		answer.QuestionIdx+1,
+		answer.Question.Text,
+		participantName(run.Participants[answer.ParticipantID]),
+		answer.Text,
+		answer.MaxPoints,
+	)
+
+	// ответ определяется по сообщению с кнопкой через gradingMessages: ID запуска и участника
+	// в callback_data не помещаются в лимит Telegram в 64 байта
+	gradeButton := func(title string, points int) client.InlineKeyboardButton {
+		return client.InlineKeyboardButton{
+			Text:         title,
+			CallbackData: fmt.Sprintf("grade %d", points),
+		}
+	}
+
+	buttons := []client.InlineKeyboardButton{gradeButton("0", 0)}
+	if answer.MaxPoints >= 2 {
+		buttons = append(buttons, gradeButton(fmt.Sprintf("½ (%d)", answer.MaxPoints/2), answer.MaxPoints/2))
+	}
+
+	buttons = append(buttons, gradeButton(fmt.Sprintf("Полный балл (%d)", answer.MaxPoints), answer.MaxPoints))
+
+	opts := &client.SendOptions{
+		ReplyMarkup: &client.InlineKeyboardMarkup{
+			InlineKeyboard: [][]client.InlineKeyboardButton{buttons},
+		},
+	}
+
+	b.mu.Lock()
+	graderID := b.runIDToGraderID[runID]
+	b.mu.Unlock()
+
+	sent, err := b.client.SendMessage(graderID, text, opts)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.gradingMessages[messageRef{chatID: graderID, messageID: sent.MessageID}] = gradedAnswer{
+		runID:         runID,
+		participantID: answer.ParticipantID,
+		questionIdx:   answer.QuestionIdx,
+	}
+	b.mu.Unlock()
+
+	return nil
+}
+
+// gradingReplyTarget возвращает ответ, к сообщению о проверке которого относится ответ преподавателя.
+func (b *Bot) gradingReplyTarget(message *client.Message) (gradedAnswer, bool) {
+	if message.ReplyToMessage == nil {
+		return gradedAnswer{}, false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	answer, ok := b.gradingMessages[messageRef{chatID: message.Chat.ID, messageID: message.ReplyToMessage.MessageID}]
+
+	return answer, ok
+}
+
+// handleGradingComment запоминает комментарий преподавателя к оценке ответа,
+// на сообщение о проверке которого он ответил.
+func (b *Bot) handleGradingComment(message *client.Message, answer gradedAnswer) error {
+	b.mu.Lock()
+	b.gradingComments[answer] = strings.TrimSpace(message.Text)
+	b.mu.Unlock()
+
+	_, err := b.sender.Message(message.Chat.ID, msgGradingCommentSaved, nil)
+
+	return err
+}
+
+// handleGradeCallbackUpdate выставляет баллы за открытый ответ.
+// Формат данных: "grade <points>", ответ находится по сообщению с кнопкой.
+func (b *Bot) handleGradeCallbackUpdate(ctx context.Context, callback *client.CallbackQuery) error {
+	data := strings.Fields(callback.Data)
+	if len(data) != 2 || callback.Message == nil {
+		return fmt.Errorf("invalid grade callback data: %q", callback.Data)
+	}
+
+	points, err := strconv.Atoi(data[1])
+	if err != nil {
+		return fmt.Errorf("invalid points in grade callback: %w", err)
+	}
+
+	ref := messageRef{chatID: callback.Message.Chat.ID, messageID: callback.Message.MessageID}
+
+	b.mu.Lock()
+	graded, ok := b.gradingMessages[ref]
+	b.mu.Unlock()
+
+	// ответ уже оценили или проверку запуска закрыли
+	if !ok {
+		return b.client.AnswerCallback(callback.ID, msgAlreadyGraded)
+	}
+
+	runID := graded.runID
+
+	allowed, err := b.canAccessRun(ctx, runID, callback.From.ID, access.PermEdit)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return b.client.AnswerCallback(callback.ID, msgNotQuizOwner)
+	}
+
+	b.mu.Lock()
+	comment := b.gradingComments[graded]
+	b.mu.Unlock()
+
+	remaining, err := b.engine.GradeAnswer(runID, graded.participantID, graded.questionIdx, points, comment)
+	if errors.Is(err, engine.ErrNoPendingAnswer) {
+		return b.client.AnswerCallback(callback.ID, msgAlreadyGraded)
+	} else if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	delete(b.gradingComments, graded)
+	delete(b.gradingMessages, ref)
+	b.mu.Unlock()
+
+	err = b.client.AnswerCallback(callback.ID, msgGradeAccepted)
+	if err != nil {
+		return err
+	}
+
+	gradedText := callback.Message.Text + fmt.Sprintf("\n\nОценка: %d", points)
+	if comment != "" {
+		gradedText += "\nКомментарий: " + comment
+	}
+
+	_ = b.client.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID, gradedText, nil)
+
+	if remaining > 0 {
+		return b.sendNextPendingAnswer(runID)
+	}
+
+	b.finishGrading(runID)
+
+	_, err = b.sender.Message(callback.Message.Chat.ID, msgGradingFinished, nil)
+	if err != nil {
+		return err
+	}
+
+	return b.sendResults(runID)
+}
+
+// formatGradingComments формирует блок с комментариями преподавателя к открытым ответам.
+func formatGradingComments(answers []engine.Answer) string {
+	var str strings.Builder
+
+	for _, answer := range answers {
+		if answer.Comment == "" {
+			continue
+		}
+
+		str.WriteString(fmt.Sprintf("Вопрос %d: %s\n", answer.QuestionIdx+1, answer.Comment))
+	}
+
+	if str.Len() == 0 {
+		return ""
+	}
+
+	return "\n\nКомментарии преподавателя:\n" + str.String()
+}
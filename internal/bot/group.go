@@ -0,0 +1,235 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/auth"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/dialog"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+const maxCallbackTextLen = 200 // лимит Telegram на текст уведомления callback query
+
+// isGroupChat сообщает, является ли чат группой или супергруппой.
+func isGroupChat(chat *client.Chat) bool {
+	return chat != nil && (chat.Type == "group" || chat.Type == "supergroup")
+}
+
+// groupChatID возвращает групповой чат, в котором проводится запуск.
+func (b *Bot) groupChatID(runID string) (int64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	chatID, ok := b.runIDToGroupChatID[runID]
+
+	return chatID, ok
+}
+
+// handleGroupMessage обрабатывает сообщение из группового чата.
+// Бот реагирует только на квизы от преподавателя, ответы участников и команды,
+// остальная переписка группы игнорируется.
+func (b *Bot) handleGroupMessage(ctx context.Context, message *client.Message) error {
+	if message.From == nil {
+		return nil
+	}
+
+	if message.Document != nil {
+		userRole, err := b.auth.CheckRole(b.storage, message.From.ID)
+		if err != nil {
+			return err
+		}
+
+		if userRole == nil || *userRole != auth.RoleLecturer {
+			return nil
+		}
+
+		return b.handleDocumentUpdate(ctx, message)
+	}
+
+	if strings.TrimSpace(message.Text) == "" {
+		return nil
+	}
+
+	if !strings.HasPrefix(message.Text, "/") {
+		b.mu.Lock()
+		runID, ok := b.userIDToRunID[message.From.ID]
+		groupChatID := b.runIDToGroupChatID[runID]
+		b.mu.Unlock()
+
+		if !ok || groupChatID != message.Chat.ID {
+			return nil
+		}
+
+		return b.handleAnswerUpdate(ctx, message.Chat.ID, message.From.ID, message.Text, runID)
+	}
+
+	return b.handleCommand(ctx, message)
+}
+
+// groupLobbyKeyboard возвращает клавиатуру лобби группового квиза.
+func groupLobbyKeyboard(runID string) client.InlineKeyboardMarkup {
+	return client.InlineKeyboardMarkup{
+		InlineKeyboard: [][]client.InlineKeyboardButton{
+			{
+				{Text: "Участвовать", CallbackData: fmt.Sprintf("join %s", runID)},
+			},
+			{
+				{Text: "Начать квиз", CallbackData: fmt.Sprintf("start_quiz %s", runID)},
+				cancelRunButton("Отменить", runID),
+			},
+		},
+	}
+}
+
+// handleGroupJoinCallbackUpdate добавляет участника группы в квиз по кнопке в лобби.
+// Писать боту в личные сообщения для этого не нужно.
+// Формат данных: "join <runID>".
+func (b *Bot) handleGroupJoinCallbackUpdate(ctx context.Context, callback *client.CallbackQuery) error {
+	runID := strings.TrimPrefix(callback.Data, "join ")
+
+	run, err := b.engine.GetRun(runID)
+	if err != nil || run.Status != engine.RunStatusLobby {
+		return b.client.AnswerCallback(callback.ID, msgClosedLobby)
+	}
+
+	err = b.auth.CreateUser(b.storage, callback.From.ID)
+	if err != nil && !errors.Is(err, storage.ErrUserAlreadyExists) {
+		return err
+	}
+
+	allowed, err := b.canJoinRun(ctx, runID, callback.From.ID)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return b.client.AnswerCallback(callback.ID, msgCourseOnly)
+	}
+
+	regData, missing, err := b.runFields(ctx, callback.From.ID, runID, nil)
+	if err != nil {
+		return err
+	}
+
+	// поля регистрации спрашиваются в личном чате, чтобы не засорять группу и не раскрывать данные
+	if len(missing) > 0 {
+		err = b.askRunField(ctx, callback.From.ID, callback.From.ID, runID, missing[0], nil)
+		if errors.Is(err, dialog.ErrInvalidTransition) {
+			return b.client.AnswerCallback(callback.ID, msgDialogBusy)
+		} else if err != nil {
+			slog.Debug("failed to ask registration field", "error", err, "user", callback.From.ID)
+
+			b.resetDialogState(ctx, callback.From.ID, dialog.StateAwaitingRunFields)
+
+			return b.client.AnswerCallback(callback.ID, msgWriteToBotFirst)
+		}
+
+		return b.client.AnswerCallback(callback.ID, msgFillFieldsInPrivate)
+	}
+
+	participant := &engine.Participant{
+		TelegramID: callback.From.ID,
+		Username:   callback.From.Username,
+		FirstName:  callback.From.FirstName,
+		LastName:   callback.From.LastName,
+		RegData:    regData,
+	}
+
+	err = b.engine.JoinRun(ctx, runID, participant)
+
+	switch {
+	case errors.Is(err, engine.ErrLobbyFull):
+		return b.client.AnswerCallback(callback.ID, msgMaxParticipantNumber)
+	case errors.Is(err, engine.ErrRepeatedJoin):
+		return b.client.AnswerCallback(callback.ID, msgAlreadyJoined)
+	case errors.Is(err, engine.ErrRunCancelled):
+		return b.client.AnswerCallback(callback.ID, msgClosedLobby)
+	case err != nil:
+		return err
+	}
+
+	b.mu.Lock()
+	b.userIDToRunID[callback.From.ID] = runID
+	// личные результаты и отчёт отправляются в личный чат, ID которого совпадает с ID пользователя
+	b.userIDToChatID[callback.From.ID] = callback.From.ID
+	delete(b.userIDToAnswersCnt, callback.From.ID)
+	b.mu.Unlock()
+
+	b.startAnswering(ctx, callback.From.ID, runID)
+
+	return b.client.AnswerCallback(callback.ID, msgQuizJoin)
+}
+
+// sendGroupQuestion публикует вопрос в групповом чате один раз для всех участников.
+// Ответы принимаются опросом-викториной или кнопками под сообщением.
+func (b *Bot) sendGroupQuestion(
+	ctx context.Context,
+	runID string,
+	chatID int64,
+	event engine.QuizEvent,
+	questionTime int,
+) error {
+	b.mu.Lock()
+	answerMode := b.runIDToQuiz[runID].Settings.AnswerMode
+	b.mu.Unlock()
+
+	if answerMode == engine.AnswerModePoll && !event.Question.IsOpen() {
+		return b.sendQuestionPolls(ctx, runID, []int64{chatID}, event, questionTime)
+	}
+
+	opts := questionOptions(runID, event, engine.AnswerModeButtons)
+
+	botMessage, err := b.client.SendMessage(chatID, formatGroupQuestion(event, questionTime, false), opts)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		timer := time.NewTimer(time.Duration(questionTime) * time.Second)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		// по истечении времени кнопки убираются
+		_ = b.client.EditMessage(chatID, botMessage.MessageID, formatGroupQuestion(event, questionTime, true), nil)
+	}()
+
+	return nil
+}
+
+// formatGroupQuestion формирует вопрос для группового чата.
+// Счётчик времени в группе не обновляется, чтобы не упираться в лимиты Telegram на редактирование.
+func formatGroupQuestion(event engine.QuizEvent, questionTime int, closed bool) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Вопрос %d", event.QuestionIdx+1) + "\n\n")
+	builder.WriteString(event.Question.Text + "\n\n")
+
+	for i, option := range event.Question.Options {
+		builder.WriteString(fmt.Sprintf("%s. %s", engine.IndexToLetter(i), option) + "\n")
+	}
+
+	switch {
+	case closed:
+		builder.WriteString("\nВремя вышло")
+	case event.Question.IsOpen():
+		builder.WriteString("\n" + fmt.Sprintf("Время: %d секунд", questionTime) + "\n\n")
+		builder.WriteString("Ответьте на это сообщение текстом")
+	default:
+		builder.WriteString("\n" + fmt.Sprintf("Время: %d секунд", questionTime) + "\n\n")
+		builder.WriteString("Выберите вариант кнопкой ниже")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+)
+
+// handleHintCallbackUpdate отправляет студенту следующую подсказку к текущему вопросу.
+// Формат данных: "hint <runID> <questionIdx>".
+func (b *Bot) handleHintCallbackUpdate(ctx context.Context, callback *client.CallbackQuery) error {
+	data := strings.Fields(callback.Data)
+	if len(data) != 3 {
+		return fmt.Errorf("invalid hint callback data: %q", callback.Data)
+	}
+
+	runID := data[1]
+
+	questionIdx, err := strconv.Atoi(data[2])
+	if err != nil {
+		return fmt.Errorf("invalid question in hint callback: %w", err)
+	}
+
+	hint, err := b.engine.UseHint(ctx, runID, callback.From.ID, questionIdx)
+
+	switch {
+	case errors.Is(err, engine.ErrNoMoreHints):
+		return b.client.AnswerCallback(callback.ID, msgNoMoreHints)
+	case errors.Is(err, engine.ErrAlreadyAnswered):
+		return b.client.AnswerCallback(callback.ID, msgHintAfterAnswer)
+	case err != nil:
+		return b.client.AnswerCallback(callback.ID, msgHintUnavailable)
+	}
+
+	msg := fmt.Sprintf(msgHint, questionIdx+1, hint.Text, hint.Cost)
+
+	// в группе подсказка показывается только нажавшему кнопку
+	if isGroupChat(callback.Message.Chat) {
+		return b.client.AnswerCallback(callback.ID, truncateRunes(msg, maxCallbackTextLen))
+	}
+
+	err = b.client.AnswerCallback(callback.ID, msgHintSent)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.client.SendMessage(callback.Message.Chat.ID, msg, nil)
+
+	return err
+}
+
+// formatHintsUsed формирует блок с использованными подсказками для личного результата.
+func formatHintsUsed(hintsUsed map[int]int) string {
+	if len(hintsUsed) == 0 {
+		return ""
+	}
+
+	var str strings.Builder
+
+	str.WriteString("\n\nИспользованные подсказки:\n")
+
+	for _, questionIdx := range slices.Sorted(maps.Keys(hintsUsed)) {
+		str.WriteString(fmt.Sprintf("Вопрос %d: %d\n", questionIdx+1, hintsUsed[questionIdx]))
+	}
+
+	return str.String()
+}
@@ -0,0 +1,580 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/access"
+	"github.com/letsssgooo/quizBot/internal/analytics"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/dialog"
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+const (
+	maxHistoryRuns         = 20 // максимум запусков в истории квиза
+	maxHistoryTop          = 10 // размер таблицы лидеров в истории
+	maxComparedQuestionLen = 80 // длина текста вопроса в сравнении запусков
+)
+
+// saveRunResults сохраняет итоги завершённого запуска для истории и сравнения.
+// Ошибки только логируются: участники и преподаватель уже получили результаты.
+func (b *Bot) saveRunResults(runID string) {
+	historyStorage, ok := b.storage.(storage.HistoryStorage)
+	if !ok {
+		return
+	}
+
+	b.mu.Lock()
+	_, ok = b.runIDToSavedQuizID[runID]
+	b.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	results, err := b.runResultsModel(runID)
+	if err != nil {
+		slog.Error("failed to collect run results", "error", err, "run", runID)
+
+		return
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutQuizStorage)
+	defer cancelFunc()
+
+	if err = historyStorage.SaveRunResults(ctx, results); err != nil {
+		slog.Error("failed to save run results", "error", err, "run", runID)
+	}
+}
+
+// runResultsModel собирает итоги запуска из движка.
+func (b *Bot) runResultsModel(runID string) (*models.RunResultsModel, error) {
+	res, err := b.engine.GetResults(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := b.engine.GetRun(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := b.engine.GetQuestionStats(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	csvData, err := b.engine.ExportCSV(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &models.RunResultsModel{RunID: runID, CSV: csvData}
+
+	for _, entry := range res.Leaderboard {
+		userID := entry.Participant.TelegramID
+
+		report, err := b.engine.GetParticipantReport(runID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		results.Participants = append(results.Participants, &models.RunParticipantModel{
+			TelegramID:    userID,
+			Name:          participantName(entry.Participant),
+			Score:         entry.Score,
+			Rank:          entry.Rank,
+			CorrectCount:  entry.CorrectCount,
+			AnsweredCount: len(run.Answers[userID]),
+			MaxScore:      report.MaxScore,
+		})
+
+		// ответы на аннулированные вопросы не учитываются в статистике студента
+		for _, item := range report.Items {
+			if stats[item.QuestionIdx].Voided {
+				continue
+			}
+
+			results.Answers = append(results.Answers, &models.RunAnswerModel{
+				TelegramID:  userID,
+				QuestionIdx: item.QuestionIdx,
+				Topics:      item.Question.Topics(),
+				Answered:    item.Answer != nil,
+				Correct:     item.Answer != nil && item.Answer.IsCorrect,
+			})
+		}
+	}
+
+	for _, stat := range stats {
+		results.Questions = append(results.Questions, &models.RunQuestionModel{
+			QuestionIdx:  stat.QuestionIdx,
+			QuestionText: stat.Text,
+			Answered:     stat.Answered,
+			Correct:      stat.Correct,
+			Voided:       stat.Voided,
+		})
+	}
+
+	return results, nil
+}
+
+// handleHistoryCommand обрабатывает /history и /history <название квиза>.
+// Без аргумента или при нескольких совпадениях предлагает выбрать квиз кнопкой.
+func (b *Bot) handleHistoryCommand(ctx context.Context, message *client.Message, args []string) error {
+	if _, ok := b.storage.(storage.QuizStorage); !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgMyQuizzesUnavailable, nil)
+
+		return err
+	}
+
+	quizzes, err := b.accessibleQuizzes(ctx, message.From.ID)
+	if err != nil {
+		return err
+	}
+
+	if query := strings.ToLower(strings.Join(args, " ")); query != "" {
+		var found []*models.QuizModel
+
+		for _, quiz := range quizzes {
+			if strings.Contains(strings.ToLower(quiz.Title), query) {
+				found = append(found, quiz)
+			}
+		}
+
+		quizzes = found
+	}
+
+	switch {
+	case len(quizzes) == 0 && len(args) > 0:
+		_, err = b.sender.Message(message.Chat.ID, msgHistoryQuizNotFound, nil)
+
+		return err
+	case len(quizzes) == 0:
+		_, err = b.sender.Message(message.Chat.ID, msgNoSavedQuizzes, nil)
+
+		return err
+	case len(quizzes) == 1:
+		return b.sendQuizHistory(ctx, message.Chat.ID, quizzes[0])
+	}
+
+	keyboard := client.InlineKeyboardMarkup{}
+
+	for _, quiz := range quizzes {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []client.InlineKeyboardButton{
+			{
+				Text:         fmt.Sprintf("%s (%s)", quiz.Title, quiz.CreatedAt.Format("02.01.2006")),
+				CallbackData: fmt.Sprintf("history quiz %s", quiz.ID),
+			},
+		})
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, msgChooseHistoryQuiz, &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
+
+// sendQuizHistory отправляет список запусков квиза со сводкой по каждому.
+func (b *Bot) sendQuizHistory(ctx context.Context, chatID int64, quiz *models.QuizModel) error {
+	quizStorage := b.storage.(storage.QuizStorage)
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	runs, err := quizStorage.ListRuns(storageCtx, quiz.ID, maxHistoryRuns)
+	if err != nil {
+		return err
+	}
+
+	if len(runs) == 0 {
+		_, err = b.sender.Message(chatID, msgNoSavedQuizRuns, nil)
+
+		return err
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Запуски квиза %s:\n\n", quiz.Title))
+
+	keyboard := client.InlineKeyboardMarkup{}
+
+	for i, run := range runs {
+		summary, err := b.runSummary(ctx, run)
+		if err != nil {
+			return err
+		}
+
+		builder.WriteString(fmt.Sprintf("%d. %s\n\n", i+1, formatRunSummary(summary)))
+
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []client.InlineKeyboardButton{
+			{
+				Text:         fmt.Sprintf("%d. %s", i+1, run.StartedAt.Format("02.01.2006 15:04")),
+				CallbackData: fmt.Sprintf("history run %s", run.ID),
+			},
+		})
+	}
+
+	builder.WriteString("Выберите запуск, чтобы посмотреть таблицу лидеров, скачать CSV или сравнить его с другим запуском.")
+
+	_, err = b.sender.Message(chatID, builder.String(), &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
+
+// runSummary считает сводку по сохранённому запуску.
+func (b *Bot) runSummary(ctx context.Context, run *models.RunModel) (analytics.RunSummary, error) {
+	results, err := b.runResults(ctx, run.ID)
+	if err != nil {
+		return analytics.RunSummary{}, err
+	}
+
+	return analytics.Summarize(run, results), nil
+}
+
+// runResults возвращает сохранённые итоги запуска или nil, если хранилище их не хранит.
+func (b *Bot) runResults(ctx context.Context, runID string) (*models.RunResultsModel, error) {
+	historyStorage, ok := b.storage.(storage.HistoryStorage)
+	if !ok {
+		return nil, nil
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	return historyStorage.GetRunResults(ctx, runID)
+}
+
+// handleHistoryCallbackUpdate обрабатывает кнопки истории запусков.
+// Формат данных: "history quiz <quizID>" или "history <действие> <runID>".
+func (b *Bot) handleHistoryCallbackUpdate(ctx context.Context, callback *client.CallbackQuery) error {
+	fields := strings.Fields(callback.Data)
+	if len(fields) != 3 {
+		return b.client.AnswerCallback(callback.ID, "")
+	}
+
+	action, id := fields[1], fields[2]
+
+	if action == "quiz" {
+		return b.handleHistoryQuizCallback(ctx, callback, id)
+	}
+
+	historyStorage, ok := b.storage.(storage.HistoryStorage)
+	if !ok {
+		return b.client.AnswerCallback(callback.ID, msgMyQuizzesUnavailable)
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	run, err := historyStorage.GetRun(storageCtx, id)
+	if errors.Is(err, storage.ErrRunNotFound) {
+		return b.client.AnswerCallback(callback.ID, msgSavedRunNotFound)
+	} else if err != nil {
+		return err
+	}
+
+	role, err := b.quizRole(ctx, run.QuizID, run.OwnerID, callback.From.ID)
+	if err != nil {
+		return err
+	}
+
+	if !role.Can(access.PermView) {
+		return b.client.AnswerCallback(callback.ID, msgNotQuizOwner)
+	}
+
+	results, err := b.runResults(ctx, run.ID)
+	if err != nil {
+		return err
+	}
+
+	// итоги сохраняются только у завершённых запусков
+	if action != "run" && (results == nil || len(results.Questions) == 0) {
+		return b.client.AnswerCallback(callback.ID, msgRunResultsNotReady)
+	}
+
+	if action != "cmp" && action != "with" {
+		if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+			return err
+		}
+	}
+
+	chatID := callback.Message.Chat.ID
+
+	switch action {
+	case "run":
+		return b.sendRunCard(chatID, analytics.Summarize(run, results))
+	case "top":
+		_, err = b.sender.Message(chatID, formatHistoryLeaderboard(run, results), nil)
+
+		return err
+	case "csv":
+		fileName := fmt.Sprintf(`Результаты запуска %s.csv`, run.StartedAt.Format("02.01.2006 15:04"))
+
+		return b.sender.Document(chatID, fileName, results.CSV)
+	case "cmp":
+		return b.handleCompareStart(ctx, callback, run)
+	case "with":
+		return b.handleCompareWith(ctx, callback, run, results)
+	default:
+		return nil
+	}
+}
+
+// handleHistoryQuizCallback показывает историю выбранного квиза.
+func (b *Bot) handleHistoryQuizCallback(ctx context.Context, callback *client.CallbackQuery, quizID string) error {
+	quizStorage, ok := b.storage.(storage.QuizStorage)
+	if !ok {
+		return b.client.AnswerCallback(callback.ID, msgMyQuizzesUnavailable)
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	quiz, err := quizStorage.GetQuiz(storageCtx, quizID)
+	if errors.Is(err, storage.ErrQuizNotFound) {
+		return b.client.AnswerCallback(callback.ID, msgSavedQuizNotFound)
+	} else if err != nil {
+		return err
+	}
+
+	role, err := b.quizRole(ctx, quiz.ID, quiz.OwnerID, callback.From.ID)
+	if err != nil {
+		return err
+	}
+
+	if !role.Can(access.PermView) {
+		return b.client.AnswerCallback(callback.ID, msgNotQuizOwner)
+	}
+
+	if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	return b.sendQuizHistory(ctx, callback.Message.Chat.ID, quiz)
+}
+
+// sendRunCard отправляет сводку по запуску с кнопками действий.
+func (b *Bot) sendRunCard(chatID int64, summary analytics.RunSummary) error {
+	text := formatRunSummary(summary)
+
+	if !summary.HasResults {
+		_, err := b.sender.Message(chatID, text, nil)
+
+		return err
+	}
+
+	runID := summary.Run.ID
+	keyboard := client.InlineKeyboardMarkup{
+		InlineKeyboard: [][]client.InlineKeyboardButton{
+			{
+				{Text: "Таблица лидеров", CallbackData: fmt.Sprintf("history top %s", runID)},
+				{Text: "Скачать CSV", CallbackData: fmt.Sprintf("history csv %s", runID)},
+			},
+			{
+				{Text: "Сравнить с другим запуском", CallbackData: fmt.Sprintf("history cmp %s", runID)},
+			},
+		},
+	}
+
+	_, err := b.sender.Message(chatID, text, &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
+
+// handleCompareStart запоминает первый запуск для сравнения и предлагает выбрать второй.
+func (b *Bot) handleCompareStart(ctx context.Context, callback *client.CallbackQuery, run *models.RunModel) error {
+	quizStorage := b.storage.(storage.QuizStorage)
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	runs, err := quizStorage.ListRuns(storageCtx, run.QuizID, maxHistoryRuns)
+	if err != nil {
+		return err
+	}
+
+	keyboard := client.InlineKeyboardMarkup{}
+
+	for _, other := range runs {
+		if other.ID == run.ID || other.Status != string(engine.RunStatusFinished) {
+			continue
+		}
+
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []client.InlineKeyboardButton{
+			{
+				Text: fmt.Sprintf(
+					"%s, участников: %d",
+					other.StartedAt.Format("02.01.2006 15:04"),
+					other.ParticipantsCount,
+				),
+				CallbackData: fmt.Sprintf("history with %s", other.ID),
+			},
+		})
+	}
+
+	if len(keyboard.InlineKeyboard) == 0 {
+		return b.client.AnswerCallback(callback.ID, msgNothingToCompare)
+	}
+
+	data := map[string]string{dialog.KeyRunID: run.ID, dialog.KeyQuizID: run.QuizID}
+
+	err = b.dialogs.Transition(ctx, callback.From.ID, dialog.StateComparingRuns, data, time.Now())
+	if errors.Is(err, dialog.ErrInvalidTransition) {
+		return b.client.AnswerCallback(callback.ID, msgDialogBusy)
+	} else if err != nil {
+		return err
+	}
+
+	if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	_, err = b.sender.Message(callback.Message.Chat.ID, msgChooseSecondRun, &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
+
+// handleCompareWith сравнивает запуск, выбранный первым, с запуском second.
+func (b *Bot) handleCompareWith(
+	ctx context.Context,
+	callback *client.CallbackQuery,
+	second *models.RunModel,
+	secondResults *models.RunResultsModel,
+) error {
+	current, _, err := b.dialogs.Current(ctx, callback.From.ID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if current.State != dialog.StateComparingRuns || current.Data[dialog.KeyQuizID] != second.QuizID {
+		return b.client.AnswerCallback(callback.ID, msgCompareExpired)
+	}
+
+	historyStorage := b.storage.(storage.HistoryStorage)
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	first, err := historyStorage.GetRun(storageCtx, current.Data[dialog.KeyRunID])
+	if err != nil {
+		return err
+	}
+
+	firstResults, err := b.runResults(ctx, first.ID)
+	if err != nil {
+		return err
+	}
+
+	if firstResults == nil || len(firstResults.Questions) == 0 {
+		return b.client.AnswerCallback(callback.ID, msgRunResultsNotReady)
+	}
+
+	b.resetDialog(ctx, callback.From.ID)
+
+	if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	blocks := formatRunComparison(
+		analytics.Summarize(first, firstResults),
+		analytics.Summarize(second, secondResults),
+		analytics.Compare(firstResults, secondResults),
+	)
+
+	for _, chunk := range splitMessage(blocks) {
+		if _, err = b.sender.Message(callback.Message.Chat.ID, chunk, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatRunSummary формирует строку со сводкой по запуску.
+func formatRunSummary(summary analytics.RunSummary) string {
+	run := summary.Run
+
+	status, ok := runStatusNames[run.Status]
+	if !ok {
+		status = run.Status
+	}
+
+	text := fmt.Sprintf("%s — %s\nУчастников: %d", run.StartedAt.Format("02.01.2006 15:04"), status, summary.Participants)
+
+	if summary.HasResults {
+		text += fmt.Sprintf(
+			", средний балл: %.1f, дошли до конца: %.0f%%",
+			summary.MeanScore,
+			summary.CompletionRate*100,
+		)
+	}
+
+	return text
+}
+
+// formatHistoryLeaderboard формирует таблицу лидеров сохранённого запуска.
+func formatHistoryLeaderboard(run *models.RunModel, results *models.RunResultsModel) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Запуск %s, топ-%d:\n", run.StartedAt.Format("02.01.2006 15:04"), maxHistoryTop))
+
+	for i, participant := range results.Participants {
+		if i == maxHistoryTop {
+			break
+		}
+
+		builder.WriteString(fmt.Sprintf("%d. %s - %d баллов\n", participant.Rank, participant.Name, participant.Score))
+	}
+
+	builder.WriteString(fmt.Sprintf("\nВсего участников: %d", len(results.Participants)))
+
+	return builder.String()
+}
+
+// formatRunComparison формирует блоки сравнения двух запусков: сводки и разницу по вопросам.
+func formatRunComparison(first, second analytics.RunSummary, deltas []analytics.QuestionDelta) []string {
+	blocks := []string{fmt.Sprintf(
+		"Сравнение запусков\n\nA: %s\n\nB: %s\n\nДоля правильных ответов, A → B:\n",
+		formatRunSummary(first),
+		formatRunSummary(second),
+	)}
+
+	var onlyA, onlyB []string
+
+	for _, delta := range deltas {
+		text := truncateRunes(delta.Text, maxComparedQuestionLen)
+
+		switch {
+		case delta.InBoth():
+			blocks = append(blocks, fmt.Sprintf(
+				"• %s: %.0f%% → %.0f%% (%+.0f п.п.)\n",
+				text,
+				delta.RateA*100,
+				delta.RateB*100,
+				delta.Delta()*100,
+			))
+		case delta.IdxA >= 0:
+			onlyA = append(onlyA, fmt.Sprintf("• %s: %.0f%%\n", text, delta.RateA*100))
+		default:
+			onlyB = append(onlyB, fmt.Sprintf("• %s: %.0f%%\n", text, delta.RateB*100))
+		}
+	}
+
+	if len(onlyA) > 0 {
+		blocks = append(blocks, "\nТолько в A:\n")
+		blocks = append(blocks, onlyA...)
+	}
+
+	if len(onlyB) > 0 {
+		blocks = append(blocks, "\nТолько в B:\n")
+		blocks = append(blocks, onlyB...)
+	}
+
+	return blocks
+}
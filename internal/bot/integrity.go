@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+	"github.com/letsssgooo/quizBot/internal/integrity"
+)
+
+// sendIntegrityReport проверяет ответы завершённого запуска на списывание и отправляет преподавателю
+// отчёт отдельным файлом. Баллы при этом не меняются. Ошибки только логируются:
+// результаты квиза преподаватель к этому моменту уже получил.
+func (b *Bot) sendIntegrityReport(ownerChatID int64, run *engine.QuizRun, quizTitle string) {
+	questions, err := b.engine.GetRunQuestions(run.ID)
+	if err != nil {
+		slog.Error("failed to get run questions for integrity report", "error", err, "run", run.ID)
+
+		return
+	}
+
+	report := integrity.Analyze(run, questions, b.integrity)
+
+	if report.Empty() {
+		_, err = b.sender.Message(ownerChatID, fmt.Sprintf(msgIntegrityClean, quizTitle), nil)
+		if err != nil {
+			slog.Error("failed to send integrity report", "error", err, "run", run.ID)
+		}
+
+		return
+	}
+
+	_, err = b.sender.Message(ownerChatID, fmt.Sprintf(msgIntegrityReport, quizTitle), nil)
+	if err == nil {
+		fileName := fmt.Sprintf(`Проверка на списывание "%s".txt`, quizTitle)
+		err = b.sender.Document(ownerChatID, fileName, []byte(formatIntegrityReport(report, quizTitle, run.ID)))
+	}
+
+	if err != nil {
+		slog.Error("failed to send integrity report", "error", err, "run", run.ID)
+	}
+}
+
+// formatIntegrityReport формирует текст отчёта о подозрительных совпадениях.
+func formatIntegrityReport(report *integrity.Report, quizTitle string, runID string) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Проверка на списывание: квиз %s, запуск %s\n", quizTitle, runID))
+	builder.WriteString("Совпадения не доказывают списывание и не влияют на баллы.\n")
+
+	if len(report.Pairs) > 0 {
+		builder.WriteString("\nПохожие ответы\n")
+
+		for _, pair := range report.Pairs {
+			builder.WriteString(fmt.Sprintf(
+				"- %s и %s: одинаково на %d из %d общих вопросов, из них неверно %d, почти одновременно %d\n",
+				participantLabel(pair.First),
+				participantLabel(pair.Second),
+				pair.Same,
+				pair.Common,
+				pair.SameWrong,
+				pair.Synced,
+			))
+		}
+	}
+
+	if len(report.Fast) > 0 {
+		builder.WriteString("\nСлишком быстрые ответы\n")
+
+		for _, fast := range report.Fast {
+			builder.WriteString(fmt.Sprintf("- %s:\n", participantLabel(fast.Participant)))
+
+			for _, answer := range fast.Answers {
+				builder.WriteString(fmt.Sprintf(
+					"  вопрос %d — %.1f с при минимуме на чтение %.1f с\n",
+					answer.QuestionIdx+1,
+					answer.ResponseTime.Seconds(),
+					answer.MinTime.Seconds(),
+				))
+			}
+		}
+	}
+
+	if len(report.JoinBursts) > 0 {
+		builder.WriteString("\nОдновременный вход\n")
+
+		for _, burst := range report.JoinBursts {
+			names := make([]string, 0, len(burst.Participants))
+			for _, participant := range burst.Participants {
+				names = append(names, participantLabel(participant))
+			}
+
+			builder.WriteString(fmt.Sprintf("- %s: %s\n", burst.At.Format("15:04:05"), strings.Join(names, ", ")))
+		}
+	}
+
+	return builder.String()
+}
+
+// participantLabel возвращает имя участника вместе с Telegram ID: в отчёте важно однозначно
+// понять, о ком речь, даже если у двух студентов одинаковые имена.
+func participantLabel(participant *engine.Participant) string {
+	return fmt.Sprintf("%s (ID %d)", participantName(participant), participant.TelegramID)
+}
@@ -1,17 +1,212 @@
 package bot
 
-// TODO: подробнее расписать функционал бота (в msgLecturersHelp) и описать каждую его команду
-
 const msgLecturersHelp = `Я - телеграм бот для проведения квизов 🤗.
 
 Инструкции при работе со мной 👇:
 
 1) Отправьте JSON-файл с вопросами и получите от меня ссылку-приглашение для студентов
 2) Нажмите "Начать квиз", когда все готовы
-3) По окончании квиза получите от меня CSV файл с результатами по квизу.`
+3) По окончании квиза получите от меня CSV файл с результатами по квизу.
+
+Квиз можно провести и в групповом чате курса: добавьте меня в группу и отправьте JSON-файл туда. Студенты присоединятся кнопкой "Участвовать", вопросы будут опубликованы в группе, а результаты и проверка ответов придут вам в личные сообщения.`
 
 const msgLecturersSuccessfullVerification = `Вы успешно зарегистрированы в роли преподавателя 👍! Отправьте мне JSON файл с данными по квизу.`
 
 // const msgUnknownQuiz = `Квиз не найден.`
 
 const msgQuizRunning = `Квиз запускается 👍!`
+
+const msgNotQuizOwner = `Недостаточно прав: это может сделать автор квиза или тот, кому он открыл доступ.`
+
+const msgGradingAnswer = `Проверка открытых ответов (осталось: %d)
+
+Вопрос %d: %s
+
+Участник: %s
+Ответ: %s
+
+Максимум баллов: %d
+
+Чтобы добавить комментарий, ответьте на это сообщение (Reply) текстом комментария, а затем выставьте оценку.`
+
+const msgGradingCommentSaved = `Комментарий будет добавлен к оценке этого ответа 📝.`
+
+const msgGradeAccepted = `Оценка сохранена 👌`
+
+const msgAlreadyGraded = `Этот ответ уже проверен.`
+
+const msgGradingExpired = `Открытые ответы квиза %s не проверены за неделю: непроверенные ответы получили 0 баллов, участникам отправлены итоги.`
+
+const msgGradingExpiredComment = `Ответ не проверен вовремя`
+
+const msgGradingFinished = `Все открытые ответы проверены 👍! Отправляю итоговые результаты.`
+
+const msgRegradeUsage = `Использование: /regrade <ID запуска> <номер вопроса> <ключ>
+
+Ключ:
+- буквы правильных вариантов через запятую, например B или A,C; варианты считаются в порядке файла квиза, даже если участникам их перемешали
+- any — засчитать любой ответ
+- void — аннулировать вопрос`
+
+const msgRegradeNotFinished = `Перепроверить можно только завершённый и полностью проверенный квиз.`
+
+const msgRegradeFailed = `Не удалось перепроверить вопрос: проверьте номер вопроса и ключ (открытые вопросы перепроверяются вручную).`
+
+const msgRegradeDone = `Вопрос %d перепроверен 👍. Результат изменился у участников: %d. Исходные результаты сохранены, новый CSV ниже.`
+
+const msgNextSection = `Следующий раунд начинается 👍`
+
+const msgNoIntermission = `Квиз не находится на перерыве`
+
+const msgNoForgottenQuestions = `Пока студенты не ошибались в вопросах ваших квизов.`
+
+const msgMyQuizzesUnavailable = `Библиотека квизов сейчас недоступна 😔.`
+
+const msgNoSavedQuizzes = `У вас пока нет сохранённых квизов. Отправьте мне JSON файл с квизом, и он появится здесь.`
+
+const msgChooseSavedQuiz = `Ваши квизы:`
+
+const msgSavedQuizNotFound = `Квиз не найден: возможно, он уже удалён.`
+
+const msgSavedQuizCard = `Квиз: %s
+Вопросов: %d
+Загружен: %s`
+
+const msgNoSavedQuizRuns = `Этот квиз ещё не запускался.`
+
+const msgConfirmQuizDelete = `Удалить квиз %s? Он пропадёт из библиотеки, а результаты его запусков останутся в статистике студентов и журналах курсов. Это действие нельзя отменить.`
+
+const msgDeleteConfirmExpired = `Подтверждение устарело. Откройте квиз в /myquizzes и удалите его снова.`
+
+const msgQuizDeleted = `Квиз %s удалён 👌.`
+
+const msgChooseHistoryQuiz = `Выберите квиз, историю запусков которого хотите посмотреть:`
+
+const msgHistoryQuizNotFound = `Не нашёл квиз с таким названием. Отправьте /history без аргументов, чтобы выбрать квиз из списка.`
+
+const msgSavedRunNotFound = `Запуск не найден: возможно, квиз уже удалён.`
+
+const msgRunResultsNotReady = `Итоги будут доступны после окончания запуска.`
+
+const msgNothingToCompare = `Других завершённых запусков этого квиза пока нет.`
+
+const msgChooseSecondRun = `Выберите запуск, с которым нужно сравнить:`
+
+const msgCompareExpired = `Выбор устарел. Откройте первый запуск в /history и нажмите "Сравнить" снова.`
+
+const msgRunCancelled = `Квиз %s отменён преподавателем.`
+
+const msgRunNotCancellable = `Квиз уже закончился, отменить его нельзя.`
+
+const msgChooseRunToCancel = `Какой квиз отменить? Участники получат уведомление, результаты не сохранятся.`
+
+const msgLobbyExpired = `Квиз %s так и не запустили, лобби закрыто. Чтобы провести квиз, запустите его заново через /myquizzes.`
+
+const msgNoStudents = `В ваших квизах ещё никто не участвовал.`
+
+const msgStudentNotFound = `Среди участников ваших квизов такого студента нет.`
+
+const msgChooseStudent = `Чью статистику показать?`
+
+const msgStudentHasNoStats = `Студент ещё не завершил ни одного вашего квиза.`
+
+const msgDashboardFinished = `Квиз %s окончен: вопросы закончились.`
+
+const msgIntegrityClean = `Проверка ответов квиза %s на списывание: подозрительных совпадений не найдено.`
+
+const msgIntegrityReport = `Проверка ответов квиза %s на списывание нашла подозрительные совпадения, подробности в файле.
+Это только подсказка: баллы не меняются, решение остаётся за вами.`
+
+const msgShareUsage = `Чтобы открыть квиз другому преподавателю, отправьте:
+/share @username <роль> <название квиза>
+
+Роли:
+- наблюдатель (viewer) — видит квиз, историю запусков и получает результаты;
+- ведущий (runner) — ещё и проводит запуски;
+- редактор (editor) — ещё и проверяет открытые ответы и перепроверяет вопросы.
+
+Пригласить по ссылке можно в карточке квиза в /myquizzes, кнопка «Доступ».`
+
+const msgShareHelp = `Чтобы пригласить преподавателя, создайте одноразовую ссылку кнопкой ниже или отправьте /share @username <роль> <название квиза>.`
+
+const msgShareUserNotFound = `Пользователь с таким username ещё не писал боту. Попросите его отправить /start или пригласите по ссылке в карточке квиза.`
+
+const msgShareSelf = `Это ваш собственный квиз.`
+
+const msgShareNotLecturer = `Открыть квиз можно только преподавателю: попросите пользователя выбрать роль преподавателя в /start.`
+
+const msgShareGranted = `Готово: %s получил доступ к квизу %s (%s).`
+
+const msgShareRemoved = `Доступ закрыт.`
+
+const msgShareRevoked = `Вам закрыли доступ к квизу %s.`
+
+const msgQuizShared = `Вам открыт квиз %s, роль: %s. Он появился в /myquizzes и /history, результаты его запусков будут приходить сюда.`
+
+const msgInviteLink = `Ссылка-приглашение к квизу %s (%s):
+%s
+
+Ссылка одноразовая и действует 7 дней.`
+
+const msgInviteInvalid = `Приглашение недействительно: оно уже использовано, истекло или квиз удалён.`
+
+const msgInviteNotLecturer = `Приглашение к квизу может принять только преподаватель. Выберите роль преподавателя в /start и откройте ссылку снова.`
+
+const msgInviteUsed = `%s принял приглашение к квизу %s (%s).`
+
+const msgLecturerPending = `Роль преподавателя нужно подтвердить, чтобы студенты не могли выдать себя за преподавателя.
+
+Попросите у администратора бота код подтверждения и отправьте его командой /verify <код> или откройте присланную им ссылку.`
+
+const msgLecturerCodeInvalid = `Код подтверждения недействителен: он уже использован или истёк. Попросите у администратора новый код.`
+
+const msgVerifyUsage = `Чтобы подтвердить роль преподавателя, отправьте:
+/verify <код>`
+
+const msgAlreadyLecturer = `Роль преподавателя у вас уже подтверждена.`
+
+const msgVerifyNotPending = `Команда нужна только для подтверждения роли преподавателя. Выберите роль «Преподаватель» в /start.`
+
+const msgLecturerCode = `Код подтверждения преподавателя: %s
+Ссылка: %s
+
+Код одноразовый и действует до %s.`
+
+const msgLecturerCodeUnavailable = `Подтверждение преподавателей сейчас недоступно, попробуйте позже.`
+
+const msgNewCourseUsage = `Чтобы создать курс, отправьте:
+/newcourse <название курса>`
+
+const msgCourseExists = `У вас уже есть курс с таким названием.`
+
+const msgCourseCreated = `Курс %s создан.
+Ссылка для записи студентов: %s
+
+Опубликуйте в нём квизы командой /publish <название квиза>. В запуски опубликованного квиза смогут войти только студенты курса.
+Записать всю учебную группу: /enrollgroup <группа> %s`
+
+const msgNoCourses = `У вас ещё нет курсов. Создайте курс командой /newcourse <название курса>.`
+
+const msgChooseCourse = `Выберите курс:`
+
+const msgCourseNotFound = `Курс не найден.`
+
+const msgNotCourseOwner = `Это не ваш курс.`
+
+const msgPublishUsage = `Чтобы опубликовать квиз в курсе, отправьте:
+/publish <название квиза>`
+
+const msgChoosePublishCourse = `В какой курс опубликовать квиз %s? В его запуски смогут войти только студенты курса.`
+
+const msgQuizPublished = `Квиз %s опубликован в курсе %s.`
+
+const msgQuizAlreadyPublished = `Квиз %s уже опубликован в курсе %s.`
+
+const msgEnrollGroupUsage = `Чтобы записать на курс всю учебную группу, отправьте:
+/enrollgroup <группа> <название курса>`
+
+const msgGroupEnrolled = `На курс %s записано студентов группы %s: %d. Студенты, которые ещё не указали группу в боте, в их число не попали.`
+
+const msgNoCourseStudents = `На курс ещё никто не записан.`
+
+const msgCoursesUnavailable = `Курсы сейчас недоступны, попробуйте позже.`
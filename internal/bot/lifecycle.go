@@ -0,0 +1,373 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+const (
+	lifecycleCheckPeriod = 10 * time.Minute   // как часто ищутся брошенные лобби и устаревшие запуски
+	lobbyTTL             = 2 * time.Hour      // сколько лобби ждёт запуска квиза
+	finishedRunTTL       = 24 * time.Hour     // сколько завершённый запуск хранится в памяти
+	gradingRunTTL        = 7 * 24 * time.Hour // сколько открытые ответы ждут проверки
+	practiceTTL          = 2 * time.Hour      // сколько живёт брошенная тренировка
+	maxRestoredRuns      = 20                 // сколько последних выгруженных запусков студента возвращается в память
+)
+
+// runLifecycle периодически закрывает брошенные лобби, закрывает затянувшуюся проверку открытых ответов,
+// выгружает из памяти завершённые запуски, забывает брошенные тренировки и давно молчащих пользователей в лимитере.
+func (b *Bot) runLifecycle(ctx context.Context) {
+	ticker := time.NewTicker(lifecycleCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			b.expireLobbies(ctx, now)
+			b.expireGrading(now)
+			b.evictFinishedRuns(ctx, now)
+			b.expirePractices(now)
+			b.limiter.Cleanup()
+		}
+	}
+}
+
+// expireLobbies отменяет запуски, которые дольше lobbyTTL ждут в лобби, и сообщает об этом преподавателю.
+func (b *Bot) expireLobbies(ctx context.Context, now time.Time) {
+	for _, run := range b.runsWithStatus(engine.RunStatusLobby) {
+		if now.Sub(run.StartedAt) < lobbyTTL {
+			continue
+		}
+
+		b.mu.Lock()
+		ownerChatID := b.runIDToOwnerChatID[run.ID]
+		title := b.runIDToQuiz[run.ID].Title
+		b.mu.Unlock()
+
+		err := b.cancelRun(ctx, run.ID, ownerChatID)
+		if errors.Is(err, engine.ErrNotCancellable) {
+			// квиз запустили, пока шла проверка
+			continue
+		} else if err != nil {
+			slog.Error("failed to expire lobby", "error", err, "run", run.ID)
+
+			continue
+		}
+
+		_, err = b.sender.Message(ownerChatID, fmt.Sprintf(msgLobbyExpired, title), nil)
+		if err != nil {
+			slog.Error("failed to notify about expired lobby", "error", err, "chat", ownerChatID, "run", run.ID)
+		}
+	}
+}
+
+// expirePractices забывает тренировки, начатые больше practiceTTL назад.
+func (b *Bot) expirePractices(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for userID, session := range b.userIDToPractice {
+		if now.Sub(session.StartedAt) >= practiceTTL {
+			delete(b.userIDToPractice, userID)
+		}
+	}
+}
+
+// expireGrading закрывает проверку открытых ответов, которая идёт дольше gradingRunTTL: непроверенные ответы
+// получают 0 баллов, проверяющий получает уведомление, участники — итоги.
+func (b *Bot) expireGrading(now time.Time) {
+	for _, run := range b.runsWithStatus(engine.RunStatusGrading) {
+		if now.Sub(run.FinishedAt) < gradingRunTTL {
+			continue
+		}
+
+		pending, err := b.engine.GetPendingAnswers(run.ID)
+		if err != nil {
+			slog.Error("failed to get pending answers", "error", err, "run", run.ID)
+
+			continue
+		}
+
+		for _, answer := range pending {
+			_, err = b.engine.GradeAnswer(run.ID, answer.ParticipantID, answer.QuestionIdx, 0, msgGradingExpiredComment)
+			if err != nil && !errors.Is(err, engine.ErrNoPendingAnswer) {
+				slog.Error("failed to grade expired answer", "error", err, "run", run.ID)
+			}
+		}
+
+		b.mu.Lock()
+		graderID, isGrading := b.runIDToGraderID[run.ID]
+		quiz, ok := b.runIDToQuiz[run.ID]
+		b.mu.Unlock()
+
+		b.finishGrading(run.ID)
+
+		if isGrading && ok {
+			_, err = b.sender.Message(graderID, fmt.Sprintf(msgGradingExpired, quiz.Title), nil)
+			if err != nil {
+				slog.Error("failed to notify about expired grading", "error", err, "chat", graderID, "run", run.ID)
+			}
+		}
+
+		if err = b.sendResults(run.ID); err != nil {
+			slog.Error("failed to send results after expired grading", "error", err, "run", run.ID)
+		}
+	}
+}
+
+// evictFinishedRuns сохраняет в хранилище и выгружает из памяти запуски, завершённые раньше finishedRunTTL.
+// Завершённый запуск выгружается, только если удалось сохранить его состояние: по нему ещё строятся
+// отчёты студентов, тренировки и перепроверка, и restoreRun возвращает его в память по требованию.
+func (b *Bot) evictFinishedRuns(ctx context.Context, now time.Time) {
+	runs := b.runsWithStatus(engine.RunStatusFinished, engine.RunStatusCancelled)
+
+	for _, run := range runs {
+		b.mu.Lock()
+		lastUsed := run.FinishedAt
+		if restoredAt, ok := b.runIDToRestoredAt[run.ID]; ok && restoredAt.After(lastUsed) {
+			lastUsed = restoredAt
+		}
+		b.mu.Unlock()
+
+		if now.Sub(lastUsed) < finishedRunTTL {
+			continue
+		}
+
+		b.saveRun(ctx, run.ID)
+
+		if run.Status == engine.RunStatusFinished {
+			b.saveRunResults(run.ID)
+
+			if !b.archiveRun(ctx, run.ID) {
+				continue
+			}
+		}
+
+		if err := b.engine.EvictRun(run.ID); err != nil {
+			slog.Error("failed to evict run", "error", err, "run", run.ID)
+
+			continue
+		}
+
+		b.forgetRun(run.ID)
+
+		// у участников в памяти больше не все их запуски, restoreTakenRuns снова сходит в хранилище
+		b.mu.Lock()
+		for userID := range run.Participants {
+			delete(b.userIDToRestoredAt, userID)
+		}
+		b.mu.Unlock()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for userID, restoredAt := range b.userIDToRestoredAt {
+		if now.Sub(restoredAt) >= finishedRunTTL {
+			delete(b.userIDToRestoredAt, userID)
+		}
+	}
+}
+
+// archiveRun сохраняет состояние завершённого запуска, чтобы его можно было выгрузить из памяти.
+// Возвращает false, если сохранить не удалось.
+func (b *Bot) archiveRun(ctx context.Context, runID string) bool {
+	historyStorage, ok := b.storage.(storage.HistoryStorage)
+	if !ok {
+		return false
+	}
+
+	data, err := b.engine.ArchiveRun(runID)
+	if err != nil {
+		slog.Error("failed to archive run", "error", err, "run", runID)
+
+		return false
+	}
+
+	b.mu.Lock()
+	archive := &models.RunArchiveModel{
+		RunID:     runID,
+		QuizID:    b.runIDToSavedQuizID[runID],
+		StarterID: b.runIDToOwnerChatID[runID],
+		Data:      data,
+	}
+	b.mu.Unlock()
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	err = historyStorage.SaveRunArchive(ctx, archive)
+	if errors.Is(err, storage.ErrRunNotFound) {
+		// запуск квиза, не попавшего в библиотеку, остаётся в памяти
+		return false
+	} else if err != nil {
+		slog.Error("failed to save run archive", "error", err, "run", runID)
+
+		return false
+	}
+
+	return true
+}
+
+// restoreRun возвращает в память запуск, выгруженный evictFinishedRuns.
+// Возвращает false, если запуска нет ни в памяти, ни в хранилище.
+func (b *Bot) restoreRun(ctx context.Context, runID string) bool {
+	if _, err := b.engine.GetRun(runID); err == nil {
+		return true
+	}
+
+	historyStorage, ok := b.storage.(storage.HistoryStorage)
+	if !ok {
+		return false
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	archive, err := historyStorage.GetRunArchive(ctx, runID)
+	if errors.Is(err, storage.ErrRunNotFound) {
+		return false
+	} else if err != nil {
+		slog.Error("failed to get run archive", "error", err, "run", runID)
+
+		return false
+	}
+
+	quiz, err := b.engine.RestoreRun(archive.Data)
+	if err != nil {
+		slog.Error("failed to restore run", "error", err, "run", runID)
+
+		return false
+	}
+
+	b.mu.Lock()
+	b.runIDToQuiz[runID] = quiz
+	b.runIDToOwnerChatID[runID] = archive.StarterID
+	b.runIDToSavedQuizID[runID] = archive.QuizID
+	b.runIDToRestoredAt[runID] = time.Now()
+	b.mu.Unlock()
+
+	return true
+}
+
+// restoreTakenRuns возвращает в память последние выгруженные запуски, в которых участвовал пользователь.
+// Хранилище опрашивается один раз, пока evictFinishedRuns снова не выгрузит один из его запусков.
+func (b *Bot) restoreTakenRuns(ctx context.Context, userID int64) {
+	historyStorage, ok := b.storage.(storage.HistoryStorage)
+	if !ok {
+		return
+	}
+
+	b.mu.Lock()
+	_, restored := b.userIDToRestoredAt[userID]
+	b.mu.Unlock()
+
+	if restored {
+		return
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	runIDs, err := historyStorage.ListArchivedRuns(storageCtx, userID, maxRestoredRuns)
+	if err != nil {
+		slog.Error("failed to list archived runs", "error", err, "user", userID)
+
+		return
+	}
+
+	restored = true
+
+	for _, runID := range runIDs {
+		if !b.restoreRun(ctx, runID) {
+			restored = false
+		}
+	}
+
+	// при ошибке хранилища следующая команда попробует ещё раз
+	if !restored {
+		return
+	}
+
+	b.mu.Lock()
+	b.userIDToRestoredAt[userID] = time.Now()
+	b.mu.Unlock()
+}
+
+// runsWithStatus возвращает известные боту запуски с одним из статусов.
+func (b *Bot) runsWithStatus(statuses ...engine.RunStatus) []*engine.QuizRun {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var runs []*engine.QuizRun
+
+	for runID := range b.runIDToQuiz {
+		run, err := b.engine.GetRun(runID)
+		if err != nil {
+			continue
+		}
+
+		for _, status := range statuses {
+			if run.Status == status {
+				runs = append(runs, run)
+
+				break
+			}
+		}
+	}
+
+	return runs
+}
+
+// releaseParticipants освобождает участников запуска: они снова могут присоединяться к другим квизам.
+func (b *Bot) releaseParticipants(runID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for userID, runIDForUser := range b.userIDToRunID {
+		if runIDForUser != runID {
+			continue
+		}
+
+		delete(b.userIDToRunID, userID)
+		delete(b.userIDToChatID, userID)
+		delete(b.userIDToAnswersCnt, userID)
+		delete(b.userIDToSelection, userID)
+	}
+}
+
+// forgetRun удаляет все данные запуска, которые хранит бот.
+func (b *Bot) forgetRun(runID string) {
+	b.releaseParticipants(runID)
+	b.finishGrading(runID)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.runIDToQuiz, runID)
+	delete(b.runIDToOwnerChatID, runID)
+	delete(b.runIDToQuestion, runID)
+	delete(b.runIDToGroupChatID, runID)
+	delete(b.runIDToSavedQuizID, runID)
+	delete(b.runIDToRestoredAt, runID)
+
+	if lobbyEndChan, ok := b.runIDToLobbyEndChan[runID]; ok {
+		close(lobbyEndChan)
+		delete(b.runIDToLobbyEndChan, runID)
+	}
+
+	for pollID, question := range b.pollIDToQuestion {
+		if question.runID == runID {
+			delete(b.pollIDToQuestion, pollID)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/access"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/dialog"
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+const (
+	timeoutQuizStorage = 2 * time.Second
+	maxListedQuizzes   = 20 // максимум квизов в списке /myquizzes
+)
+
+// myQuizPermissions — права, которые нужны для действий над сохранённым квизом.
+var myQuizPermissions = map[string]access.Permission{
+	"open":     access.PermView,
+	"keep":     access.PermManage, // отмена удаления
+	"download": access.PermView,
+	"runs":     access.PermView,
+	"rerun":    access.PermRun,
+	"share":    access.PermManage,
+	"delete":   access.PermManage,
+	"confirm":  access.PermManage,
+}
+
+// runStatusNames — названия статусов запуска для преподавателя.
+var runStatusNames = map[string]string{
+	string(engine.RunStatusLobby):     "лобби",
+	string(engine.RunStatusRunning):   "идёт",
+	string(engine.RunStatusGrading):   "проверка ответов",
+	string(engine.RunStatusFinished):  "завершён",
+	string(engine.RunStatusCancelled): "отменён",
+}
+
+// saveQuiz сохраняет загруженный квиз в библиотеку преподавателя и возвращает его ID.
+// Ошибки хранилища только логируются: квиз можно провести и без сохранения, тогда возвращается пустая строка.
+func (b *Bot) saveQuiz(ctx context.Context, quiz *engine.Quiz, data []byte) string {
+	quizStorage, ok := b.storage.(storage.QuizStorage)
+	if !ok {
+		return ""
+	}
+
+	model := &models.QuizModel{
+		ID:             quiz.ID,
+		OwnerID:        quiz.OwnerID,
+		Title:          quiz.Title,
+		File:           data,
+		QuestionsCount: len(quiz.Questions),
+		CreatedAt:      quiz.CreatedAt,
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	if err := quizStorage.SaveQuiz(ctx, model); err != nil {
+		slog.Error("failed to save quiz", "error", err, "quiz", quiz.ID)
+
+		return ""
+	}
+
+	return model.ID
+}
+
+// saveRun сохраняет текущее состояние запуска, если он создан по сохранённому квизу.
+func (b *Bot) saveRun(ctx context.Context, runID string) {
+	quizStorage, ok := b.storage.(storage.QuizStorage)
+	if !ok {
+		return
+	}
+
+	b.mu.Lock()
+	savedQuizID, ok := b.runIDToSavedQuizID[runID]
+	quiz := b.runIDToQuiz[runID]
+	b.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	run, err := b.engine.GetRun(runID)
+	if err != nil {
+		return
+	}
+
+	model := &models.RunModel{
+		ID:                run.ID,
+		QuizID:            savedQuizID,
+		OwnerID:           quiz.OwnerID,
+		Status:            string(run.Status),
+		ParticipantsCount: len(run.Participants),
+		StartedAt:         run.StartedAt,
+	}
+
+	if !run.FinishedAt.IsZero() {
+		finishedAt := run.FinishedAt
+		model.FinishedAt = &finishedAt
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	if err = quizStorage.SaveRun(ctx, model); err != nil {
+		slog.Error("failed to save run", "error", err, "run", runID)
+	}
+}
+
+// handleMyQuizzesCommand обрабатывает /myquizzes: показывает сохранённые квизы преподавателя
+// и чужие квизы, открытые ему.
+func (b *Bot) handleMyQuizzesCommand(ctx context.Context, message *client.Message) error {
+	if _, ok := b.storage.(storage.QuizStorage); !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgMyQuizzesUnavailable, nil)
+
+		return err
+	}
+
+	quizzes, err := b.accessibleQuizzes(ctx, message.From.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(quizzes) == 0 {
+		_, err = b.sender.Message(message.Chat.ID, msgNoSavedQuizzes, nil)
+
+		return err
+	}
+
+	keyboard := client.InlineKeyboardMarkup{}
+
+	for _, quiz := range quizzes {
+		details := fmt.Sprintf("%s, запусков: %d", quiz.CreatedAt.Format("02.01.2006"), quiz.RunsCount)
+		// чужой квиз подписывается ролью, с которой он открыт
+		if quiz.SharedRole != "" {
+			details += ", " + access.Role(quiz.SharedRole).Name()
+		}
+
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []client.InlineKeyboardButton{
+			{
+				Text:         fmt.Sprintf("%s (%s)", quiz.Title, details),
+				CallbackData: fmt.Sprintf("myquiz open %s", quiz.ID),
+			},
+		})
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, msgChooseSavedQuiz, &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
+
+// handleMyQuizCallbackUpdate обрабатывает кнопки сохранённого квиза.
+// Формат данных: "myquiz <действие> <quizID>".
+func (b *Bot) handleMyQuizCallbackUpdate(ctx context.Context, callback *client.CallbackQuery) error {
+	fields := strings.Fields(callback.Data)
+	if len(fields) != 3 {
+		return b.client.AnswerCallback(callback.ID, "")
+	}
+
+	action, quizID := fields[1], fields[2]
+
+	quizStorage, ok := b.storage.(storage.QuizStorage)
+	if !ok {
+		return b.client.AnswerCallback(callback.ID, msgMyQuizzesUnavailable)
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	quiz, err := quizStorage.GetQuiz(storageCtx, quizID)
+	if errors.Is(err, storage.ErrQuizNotFound) {
+		return b.client.AnswerCallback(callback.ID, msgSavedQuizNotFound)
+	} else if err != nil {
+		return err
+	}
+
+	role, err := b.quizRole(ctx, quiz.ID, quiz.OwnerID, callback.From.ID)
+	if err != nil {
+		return err
+	}
+
+	if perm, ok := myQuizPermissions[action]; !ok || !role.Can(perm) {
+		return b.client.AnswerCallback(callback.ID, msgNotQuizOwner)
+	}
+
+	switch action {
+	case "open", "keep":
+		return b.handleSavedQuizOpen(ctx, callback, quiz, role)
+	case "download":
+		return b.handleSavedQuizDownload(callback, quiz)
+	case "rerun":
+		return b.handleSavedQuizRerun(ctx, callback, quiz)
+	case "runs":
+		return b.handleSavedQuizRuns(ctx, callback, quiz)
+	case "delete":
+		return b.handleSavedQuizDelete(ctx, callback, quiz)
+	case "confirm":
+		return b.handleSavedQuizDeleteConfirm(ctx, callback, quiz)
+	case "share":
+		if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+			return err
+		}
+
+		return b.sendShareList(ctx, callback.Message.Chat.ID, quiz)
+	default:
+		return b.client.AnswerCallback(callback.ID, "")
+	}
+}
+
+// handleSavedQuizOpen показывает карточку квиза с действиями, доступными роли пользователя.
+func (b *Bot) handleSavedQuizOpen(
+	ctx context.Context,
+	callback *client.CallbackQuery,
+	quiz *models.QuizModel,
+	role access.Role,
+) error {
+	data := map[string]string{dialog.KeyQuizID: quiz.ID}
+
+	err := b.dialogs.Transition(ctx, callback.From.ID, dialog.StateEditingQuiz, data, time.Now())
+	if errors.Is(err, dialog.ErrInvalidTransition) {
+		return b.client.AnswerCallback(callback.ID, msgDialogBusy)
+	} else if err != nil {
+		return err
+	}
+
+	if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	keyboard := savedQuizKeyboard(quiz.ID, role)
+	text := fmt.Sprintf(msgSavedQuizCard, quiz.Title, quiz.QuestionsCount, quiz.CreatedAt.Format("02.01.2006 15:04"))
+
+	if role != access.RoleOwner {
+		text += "\nВаша роль: " + role.Name()
+	}
+
+	// после отмены удаления карточка возвращается в то же сообщение
+	if strings.HasPrefix(callback.Data, "myquiz keep ") {
+		return b.client.EditMessage(
+			callback.Message.Chat.ID,
+			callback.Message.MessageID,
+			text,
+			&client.SendOptions{ReplyMarkup: &keyboard},
+		)
+	}
+
+	_, err = b.sender.Message(callback.Message.Chat.ID, text, &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
+
+// handleSavedQuizDownload отправляет исходный JSON файл квиза.
+func (b *Bot) handleSavedQuizDownload(callback *client.CallbackQuery, quiz *models.QuizModel) error {
+	if err := b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	fileName := fmt.Sprintf("%s.json", quiz.Title)
+
+	return b.sender.Document(callback.Message.Chat.ID, fileName, quiz.File)
+}
+
+// handleSavedQuizRerun создаёт новый запуск сохранённого квиза.
+func (b *Bot) handleSavedQuizRerun(ctx context.Context, callback *client.CallbackQuery, quiz *models.QuizModel) error {
+	engineQuiz, err := b.engine.LoadQuiz(quiz.File)
+	if err != nil {
+		return err
+	}
+
+	engineQuiz.OwnerID = quiz.OwnerID
+	engineQuiz.CreatedAt = quiz.CreatedAt
+
+	if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	b.resetDialogState(ctx, callback.From.ID, dialog.StateEditingQuiz)
+
+	return b.openLobby(ctx, callback.Message.Chat, callback.From.ID, engineQuiz, quiz.ID)
+}
+
+// handleSavedQuizRuns показывает историю запусков квиза.
+func (b *Bot) handleSavedQuizRuns(ctx context.Context, callback *client.CallbackQuery, quiz *models.QuizModel) error {
+	if err := b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	return b.sendQuizHistory(ctx, callback.Message.Chat.ID, quiz)
+}
+
+// handleSavedQuizDelete просит подтвердить удаление квиза.
+func (b *Bot) handleSavedQuizDelete(ctx context.Context, callback *client.CallbackQuery, quiz *models.QuizModel) error {
+	data := map[string]string{dialog.KeyQuizID: quiz.ID}
+
+	err := b.dialogs.Transition(ctx, callback.From.ID, dialog.StateConfirmingDelete, data, time.Now())
+	if errors.Is(err, dialog.ErrInvalidTransition) {
+		return b.client.AnswerCallback(callback.ID, msgDialogBusy)
+	} else if err != nil {
+		return err
+	}
+
+	if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	keyboard := client.InlineKeyboardMarkup{
+		InlineKeyboard: [][]client.InlineKeyboardButton{
+			{
+				{Text: "Да, удалить", CallbackData: fmt.Sprintf("myquiz confirm %s", quiz.ID)},
+				{Text: "Отмена", CallbackData: fmt.Sprintf("myquiz keep %s", quiz.ID)},
+			},
+		},
+	}
+
+	return b.client.EditMessage(
+		callback.Message.Chat.ID,
+		callback.Message.MessageID,
+		fmt.Sprintf(msgConfirmQuizDelete, quiz.Title),
+		&client.SendOptions{ReplyMarkup: &keyboard},
+	)
+}
+
+// handleSavedQuizDeleteConfirm удаляет квиз, если удаление подтверждено в рамках текущего диалога.
+func (b *Bot) handleSavedQuizDeleteConfirm(
+	ctx context.Context,
+	callback *client.CallbackQuery,
+	quiz *models.QuizModel,
+) error {
+	current, _, err := b.dialogs.Current(ctx, callback.From.ID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if current.State != dialog.StateConfirmingDelete || current.Data[dialog.KeyQuizID] != quiz.ID {
+		return b.client.AnswerCallback(callback.ID, msgDeleteConfirmExpired)
+	}
+
+	quizStorage := b.storage.(storage.QuizStorage)
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	if err = quizStorage.DeleteQuiz(storageCtx, quiz.ID); err != nil {
+		return err
+	}
+
+	b.resetDialog(ctx, callback.From.ID)
+
+	if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	return b.client.EditMessage(
+		callback.Message.Chat.ID,
+		callback.Message.MessageID,
+		fmt.Sprintf(msgQuizDeleted, quiz.Title),
+		nil,
+	)
+}
+
+// savedQuizKeyboard возвращает кнопки действий над сохранённым квизом, доступных роли.
+func savedQuizKeyboard(quizID string, role access.Role) client.InlineKeyboardMarkup {
+	first := []client.InlineKeyboardButton{
+		{Text: "Скачать JSON", CallbackData: fmt.Sprintf("myquiz download %s", quizID)},
+	}
+	if role.Can(access.PermRun) {
+		first = append(first, client.InlineKeyboardButton{
+			Text:         "Новый запуск",
+			CallbackData: fmt.Sprintf("myquiz rerun %s", quizID),
+		})
+	}
+
+	second := []client.InlineKeyboardButton{
+		{Text: "Запуски", CallbackData: fmt.Sprintf("myquiz runs %s", quizID)},
+	}
+	if role.Can(access.PermManage) {
+		second = append(second,
+			client.InlineKeyboardButton{Text: "Доступ", CallbackData: fmt.Sprintf("myquiz share %s", quizID)},
+			client.InlineKeyboardButton{Text: "Удалить", CallbackData: fmt.Sprintf("myquiz delete %s", quizID)},
+		)
+	}
+
+	return client.InlineKeyboardMarkup{InlineKeyboard: [][]client.InlineKeyboardButton{first, second}}
+}
@@ -0,0 +1,148 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+)
+
+// Ограничения Telegram на опрос-викторину.
+const (
+	maxPollQuestionLen    = 300
+	maxPollExplanationLen = 200
+	minPollOpenPeriod     = 5
+	maxPollOpenPeriod     = 600
+)
+
+// pollQuestion — вопрос запуска, отправленный участнику опросом.
+type pollQuestion struct {
+	runID       string
+	questionIdx int
+}
+
+// sendQuestionPolls отправляет вопрос опросом-викториной Telegram в каждый из чатов chatIDs.
+// Если время на вопрос не укладывается в open_period, опросы закрываются вручную.
+func (b *Bot) sendQuestionPolls(
+	ctx context.Context,
+	runID string,
+	chatIDs []int64,
+	event engine.QuizEvent,
+	questionTime int,
+) error {
+	pollOpts := &client.PollOptions{
+		CorrectOptionID: event.Question.Correct,
+		Explanation:     truncateRunes(event.Question.Explanation, maxPollExplanationLen),
+	}
+
+	if questionTime >= minPollOpenPeriod && questionTime <= maxPollOpenPeriod {
+		pollOpts.OpenPeriod = questionTime
+	}
+
+	// в режиме опросов клавиатура содержит только кнопку подсказки
+	if opts := questionOptions(runID, event, engine.AnswerModePoll); opts != nil {
+		pollOpts.ReplyMarkup = opts.ReplyMarkup
+	}
+
+	question := truncateRunes(fmt.Sprintf("Вопрос %d. %s", event.QuestionIdx+1, event.Question.Text), maxPollQuestionLen)
+
+	var polls []*client.Message
+
+	for _, chatID := range chatIDs {
+		message, err := b.client.SendPoll(chatID, question, event.Question.Options, pollOpts)
+		if err != nil {
+			return err
+		}
+
+		if message.Poll == nil {
+			continue
+		}
+
+		b.mu.Lock()
+		b.pollIDToQuestion[message.Poll.ID] = pollQuestion{runID: runID, questionIdx: event.QuestionIdx}
+		b.mu.Unlock()
+
+		polls = append(polls, message)
+	}
+
+	go b.closeQuestionPolls(ctx, polls, questionTime, pollOpts.OpenPeriod == 0)
+
+	return nil
+}
+
+// closeQuestionPolls по истечении времени на вопрос забывает опросы и, если нужно, закрывает их.
+func (b *Bot) closeQuestionPolls(ctx context.Context, polls []*client.Message, questionTime int, stop bool) {
+	timer := time.NewTimer(time.Duration(questionTime) * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+
+	for _, message := range polls {
+		if stop {
+			_ = b.client.StopPoll(message.Chat.ID, message.MessageID)
+		}
+
+		b.mu.Lock()
+		delete(b.pollIDToQuestion, message.Poll.ID)
+		b.mu.Unlock()
+	}
+}
+
+// handlePollAnswerUpdate принимает ответ участника в опросе-викторине.
+// Ответы в опросах, которые бот не отправлял в рамках запуска, игнорируются.
+func (b *Bot) handlePollAnswerUpdate(ctx context.Context, answer *client.PollAnswer) error {
+	if answer.User == nil || len(answer.OptionIDs) == 0 { // голос отозван
+		return nil
+	}
+
+	userID := answer.User.ID
+
+	b.mu.Lock()
+	question, ok := b.pollIDToQuestion[answer.PollID]
+	userRunID := b.userIDToRunID[userID]
+	chatID := b.userIDToChatID[userID]
+	b.mu.Unlock()
+
+	if !ok || userRunID != question.runID {
+		return nil
+	}
+
+	err := b.engine.SubmitAnswer(ctx, question.runID, userID, question.questionIdx, answer.OptionIDs[0])
+
+	switch {
+	case errors.Is(err, engine.ErrStaleQuestion):
+		_, err = b.sender.Message(chatID, msgStaleAnswer, nil)
+
+		return err
+	case errors.Is(err, engine.ErrAlreadyAnswered):
+		_, err = b.sender.Message(chatID, msgRepeatedAnswer, nil)
+
+		return err
+	case err != nil:
+		_, err = b.sender.Message(chatID, msgAnswerRejected, nil)
+
+		return err
+	}
+
+	b.mu.Lock()
+	b.userIDToAnswersCnt[userID]++
+	b.mu.Unlock()
+
+	return nil
+}
+
+// truncateRunes обрезает строку до limit символов.
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+
+	return string(runes[:limit-1]) + "…"
+}
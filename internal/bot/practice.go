@@ -0,0 +1,362 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+const timeoutPracticeSave = time.Second
+
+// handlePracticeCommand обрабатывает /practice и /practice <название квиза>.
+// Без аргумента показывает список пройденных студентом квизов.
+func (b *Bot) handlePracticeCommand(message *client.Message, text []string) error {
+	if b.inActiveRun(message.From.ID) {
+		_, err := b.sender.Message(message.Chat.ID, msgPracticeDuringRun, nil)
+
+		return err
+	}
+
+	quizzes := b.takenQuizzes(message.From.ID)
+	if len(quizzes) == 0 {
+		_, err := b.sender.Message(message.Chat.ID, msgNoPracticeQuizzes, nil)
+
+		return err
+	}
+
+	if len(text) > 1 {
+		quiz := findQuizByTitle(quizzes, strings.Join(text[1:], " "))
+		if quiz == nil {
+			_, err := b.sender.Message(message.Chat.ID, msgPracticeQuizNotFound, nil)
+
+			return err
+		}
+
+		return b.sendPracticeModeChoice(message.Chat.ID, quiz)
+	}
+
+	keyboard := client.InlineKeyboardMarkup{}
+	for _, quiz := range quizzes {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []client.InlineKeyboardButton{
+			{Text: quiz.Title, CallbackData: fmt.Sprintf("practice %s", quiz.ID)},
+		})
+	}
+
+	_, err := b.sender.Message(message.Chat.ID, msgPracticeChooseQuiz, &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
+
+// sendPracticeModeChoice предлагает выбрать режим тренировки: без таймера или с таймером.
+func (b *Bot) sendPracticeModeChoice(chatID int64, quiz *engine.Quiz) error {
+	keyboard := client.InlineKeyboardMarkup{
+		InlineKeyboard: [][]client.InlineKeyboardButton{
+			{
+				{Text: "Без таймера", CallbackData: fmt.Sprintf("practice %s free", quiz.ID)},
+				{Text: "С таймером", CallbackData: fmt.Sprintf("practice %s timed", quiz.ID)},
+			},
+		},
+	}
+
+	msg := fmt.Sprintf(msgPracticeChooseMode, quiz.Title)
+	_, err := b.sender.Message(chatID, msg, &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
+
+// handlePracticeCallbackUpdate обрабатывает выбор квиза и режима тренировки.
+// Формат данных: "practice <quizID>" или "practice <quizID> free|timed".
+func (b *Bot) handlePracticeCallbackUpdate(callback *client.CallbackQuery) error {
+	data := strings.Fields(callback.Data)
+	if len(data) < 2 || len(data) > 3 {
+		return fmt.Errorf("invalid practice callback data: %q", callback.Data)
+	}
+
+	if b.inActiveRun(callback.From.ID) {
+		return b.client.AnswerCallback(callback.ID, msgPracticeDuringRun)
+	}
+
+	quizzes := b.takenQuizzes(callback.From.ID)
+
+	idx := slices.IndexFunc(quizzes, func(quiz *engine.Quiz) bool {
+		return quiz.ID == data[1]
+	})
+	if idx < 0 {
+		return b.client.AnswerCallback(callback.ID, msgPracticeQuizNotFound)
+	}
+
+	quiz := quizzes[idx]
+
+	err := b.client.AnswerCallback(callback.ID, "")
+	if err != nil {
+		return err
+	}
+
+	if len(data) == 2 {
+		return b.sendPracticeModeChoice(callback.Message.Chat.ID, quiz)
+	}
+
+	session := engine.NewPracticeSession(quiz, callback.From.ID, data[2] == "timed", time.Now())
+
+	b.mu.Lock()
+	delete(b.userIDToReview, callback.From.ID)
+	b.userIDToPractice[callback.From.ID] = session
+	b.mu.Unlock()
+
+	_, err = b.sender.Message(callback.Message.Chat.ID, fmt.Sprintf(msgPracticeStarted, quiz.Title), nil)
+	if err != nil {
+		return err
+	}
+
+	return b.sendPracticeQuestion(callback.Message.Chat.ID, session)
+}
+
+// handlePracticeAnswer проверяет ответ в тренировке и сразу показывает пояснение.
+func (b *Bot) handlePracticeAnswer(
+	ctx context.Context,
+	chatID int64,
+	session *engine.PracticeSession,
+	text string,
+) error {
+	feedback, err := session.Answer(text, time.Now())
+
+	if errors.Is(err, engine.ErrEmptyTextAnswer) {
+		_, err = b.sender.Message(chatID, msgEmptyTextAnswer, nil)
+
+		return err
+	} else if errors.Is(err, engine.ErrConvertLetterToIndex) {
+		_, err = b.sender.Message(chatID, msgPracticeInvalidAnswer, nil)
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	_, err = b.sender.Message(chatID, formatPracticeFeedback(feedback), nil)
+	if err != nil {
+		return err
+	}
+
+	if !feedback.Finished {
+		return b.sendPracticeQuestion(chatID, session)
+	}
+
+	b.mu.Lock()
+	delete(b.userIDToPractice, session.ParticipantID)
+	b.mu.Unlock()
+
+	b.savePracticeResult(ctx, session)
+
+	result := session.Result()
+	msg := fmt.Sprintf(
+		msgPracticeFinished,
+		session.Quiz.Title,
+		result.Score,
+		result.MaxScore,
+		result.CorrectCount,
+		result.Total,
+		result.Duration.Round(time.Second),
+	)
+	_, err = b.sender.Message(chatID, msg, nil)
+
+	return err
+}
+
+// sendPracticeQuestion отправляет текущий вопрос тренировки.
+func (b *Bot) sendPracticeQuestion(chatID int64, session *engine.PracticeSession) error {
+	questionIdx, question, ok := session.CurrentQuestion()
+	if !ok {
+		return nil
+	}
+
+	header := fmt.Sprintf("Тренировка: вопрос %d из %d", questionIdx+1, len(session.Questions))
+
+	timeLimit := 0
+	if session.Timed {
+		timeLimit = session.QuestionTime()
+	}
+
+	session.ShowQuestion(time.Now())
+
+	_, err := b.sender.Message(chatID, formatSoloQuestion(header, question, timeLimit), nil)
+
+	return err
+}
+
+// formatSoloQuestion формирует вопрос для тренировки или повторения.
+// Время выводится, только если timeLimit больше нуля.
+func formatSoloQuestion(header string, question *engine.Question, timeLimit int) string {
+	var builder strings.Builder
+
+	builder.WriteString(header + "\n\n")
+	builder.WriteString(question.Text + "\n\n")
+
+	for i, option := range question.Options {
+		builder.WriteString(fmt.Sprintf("%s. %s", engine.IndexToLetter(i), option) + "\n")
+	}
+
+	if timeLimit > 0 {
+		builder.WriteString("\n" + fmt.Sprintf("Время: %d секунд", timeLimit) + "\n")
+	}
+
+	if question.IsOpen() {
+		builder.WriteString("\nОтправьте ответ текстом одним сообщением")
+	} else {
+		builder.WriteString("\nОтправьте букву ответа (A, B, C, ...)")
+	}
+
+	return builder.String()
+}
+
+// savePracticeResult сохраняет результат тренировки, если хранилище это поддерживает.
+// Ошибка сохранения не мешает студенту увидеть свой результат.
+func (b *Bot) savePracticeResult(ctx context.Context, session *engine.PracticeSession) {
+	practiceStorage, ok := b.storage.(storage.PracticeStorage)
+	if !ok {
+		return
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutPracticeSave)
+	defer cancelFunc()
+
+	result := session.Result()
+
+	err := practiceStorage.SavePracticeResult(ctx, &models.PracticeModel{
+		ID:             session.ID,
+		TelegramID:     session.ParticipantID,
+		QuizID:         session.Quiz.ID,
+		QuizTitle:      session.Quiz.Title,
+		Timed:          session.Timed,
+		Score:          result.Score,
+		MaxScore:       result.MaxScore,
+		CorrectCount:   result.CorrectCount,
+		QuestionsCount: result.Total,
+		StartedAt:      session.StartedAt,
+		FinishedAt:     session.FinishedAt,
+	})
+	if err != nil {
+		slog.Error("failed to save practice result", "error", err, "user", session.ParticipantID)
+	}
+}
+
+// inActiveRun сообщает, участвует ли пользователь в незавершённом запуске.
+func (b *Bot) inActiveRun(userID int64) bool {
+	b.mu.Lock()
+	runID, ok := b.userIDToRunID[userID]
+	b.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	run, err := b.engine.GetRun(runID)
+	if err != nil {
+		return false
+	}
+
+	return run.Status == engine.RunStatusLobby || run.Status == engine.RunStatusRunning
+}
+
+// takenQuizzes возвращает квизы, которые пользователь уже прошёл, от новых к старым.
+func (b *Bot) takenQuizzes(userID int64) []*engine.Quiz {
+	var quizzes []*engine.Quiz
+
+	for _, run := range b.takenRuns(userID) {
+		b.mu.Lock()
+		quiz, ok := b.runIDToQuiz[run.ID]
+		b.mu.Unlock()
+
+		// запуск выгрузили из памяти после takenRuns
+		if !ok {
+			continue
+		}
+
+		if !slices.ContainsFunc(quizzes, func(taken *engine.Quiz) bool { return taken.ID == quiz.ID }) {
+			quizzes = append(quizzes, quiz)
+		}
+	}
+
+	return quizzes
+}
+
+// takenRuns возвращает завершённые запуски, в которых участвовал пользователь, от новых к старым.
+// Последние выгруженные из памяти запуски сначала возвращаются из хранилища.
+func (b *Bot) takenRuns(userID int64) []*engine.QuizRun {
+	b.restoreTakenRuns(context.Background(), userID)
+
+	b.mu.Lock()
+	runIDs := slices.Collect(maps.Keys(b.runIDToQuiz))
+	b.mu.Unlock()
+
+	var runs []*engine.QuizRun
+
+	for _, runID := range runIDs {
+		run, err := b.engine.GetRun(runID)
+		if err != nil || (run.Status != engine.RunStatusFinished && run.Status != engine.RunStatusGrading) {
+			continue
+		}
+
+		if _, ok := run.Participants[userID]; ok {
+			runs = append(runs, run)
+		}
+	}
+
+	slices.SortFunc(runs, func(a, b *engine.QuizRun) int {
+		return b.StartedAt.Compare(a.StartedAt)
+	})
+
+	return runs
+}
+
+// findQuizByTitle ищет квиз по названию без учёта регистра: сначала точное совпадение, затем по подстроке.
+func findQuizByTitle(quizzes []*engine.Quiz, title string) *engine.Quiz {
+	title = strings.ToLower(strings.TrimSpace(title))
+
+	for _, quiz := range quizzes {
+		if strings.ToLower(quiz.Title) == title {
+			return quiz
+		}
+	}
+
+	for _, quiz := range quizzes {
+		if strings.Contains(strings.ToLower(quiz.Title), title) {
+			return quiz
+		}
+	}
+
+	return nil
+}
+
+// formatPracticeFeedback формирует ответ бота на ответ в тренировке.
+func formatPracticeFeedback(feedback *engine.PracticeFeedback) string {
+	var msg string
+
+	question := feedback.Question
+
+	switch {
+	case question.IsOpen():
+		msg = msgPracticeOpenAnswer
+	case feedback.Late:
+		msg = fmt.Sprintf(msgPracticeLate, formatCorrectLetters(question))
+	case feedback.IsCorrect:
+		msg = fmt.Sprintf(msgPracticeCorrect, feedback.Points)
+	default:
+		msg = fmt.Sprintf(msgPracticeWrong, formatCorrectLetters(question))
+	}
+
+	if question.Explanation != "" {
+		msg += "\n\nПояснение: " + question.Explanation
+	}
+
+	return msg
+}
@@ -0,0 +1,179 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/auth"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/dialog"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+const timeoutRegistrationStorage = time.Second
+
+// fieldPrompts — вопросы для известных полей регистрации. Остальные поля спрашиваются по названию.
+var fieldPrompts = map[string]string{
+	auth.FieldName:      "Введите ФИО полностью, например: Иванов Иван Иванович",
+	auth.FieldGroup:     "Введите учебную группу, например: ИВТБ101",
+	auth.FieldEmail:     "Введите почту, например: student@example.com",
+	auth.FieldStudentID: "Введите номер студенческого билета",
+}
+
+// runFields возвращает значения полей регистрации, которых требует квиз запуска runID, и список
+// полей, которые ещё не заполнены. Значения берутся из сохранённых полей пользователя и из entered —
+// введённых в текущем диалоге.
+func (b *Bot) runFields(
+	ctx context.Context,
+	userID int64,
+	runID string,
+	entered map[string]string,
+) (values map[string]string, missing []string, err error) {
+	b.mu.Lock()
+	quiz, ok := b.runIDToQuiz[runID]
+	b.mu.Unlock()
+
+	if !ok {
+		return nil, nil, fmt.Errorf("quiz of run %s is not found", runID)
+	}
+
+	if len(quiz.Settings.Registration) == 0 {
+		return nil, nil, nil
+	}
+
+	known := b.userFields(ctx, userID)
+	maps.Copy(known, entered)
+
+	values = make(map[string]string, len(quiz.Settings.Registration))
+
+	for _, field := range quiz.Settings.Registration {
+		if value := known[field]; value != "" {
+			values[field] = value
+		} else {
+			missing = append(missing, field)
+		}
+	}
+
+	return values, missing, nil
+}
+
+// userFields возвращает сохранённые поля регистрации пользователя.
+// Ошибка хранилища только логируется: тогда поля будут спрошены заново.
+func (b *Bot) userFields(ctx context.Context, userID int64) map[string]string {
+	registrationStorage, ok := b.storage.(storage.RegistrationStorage)
+	if !ok {
+		return make(map[string]string)
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutRegistrationStorage)
+	defer cancelFunc()
+
+	fields, err := registrationStorage.GetUserFields(ctx, userID)
+	if err != nil {
+		slog.Error("failed to get user fields", "error", err, "user", userID)
+
+		return make(map[string]string)
+	}
+
+	return fields
+}
+
+// saveUserField сохраняет поле регистрации, чтобы не спрашивать его в следующих квизах.
+// Ошибка только логируется: значение уже есть в диалоге.
+func (b *Bot) saveUserField(ctx context.Context, userID int64, field, value string) {
+	registrationStorage, ok := b.storage.(storage.RegistrationStorage)
+	if !ok {
+		return
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutRegistrationStorage)
+	defer cancelFunc()
+
+	if err := registrationStorage.SaveUserField(ctx, userID, field, value); err != nil {
+		slog.Error("failed to save user field", "error", err, "user", userID, "field", field)
+	}
+}
+
+// askRunField переводит студента в диалог заполнения полей квиза и спрашивает поле field.
+// В диалоге хранятся уже введённые значения, так что бот обходится и без хранилища полей.
+// Возвращает dialog.ErrInvalidTransition, если студент занят другим диалогом.
+func (b *Bot) askRunField(
+	ctx context.Context,
+	chatID, userID int64,
+	runID, field string,
+	entered map[string]string,
+) error {
+	data := map[string]string{dialog.KeyRunID: runID, dialog.KeyField: field}
+	for enteredField, value := range entered {
+		data[dialog.KeyFieldValuePrefix+enteredField] = value
+	}
+
+	err := b.dialogs.Transition(ctx, userID, dialog.StateAwaitingRunFields, data, time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = b.client.SendMessage(chatID, fieldPrompt(field), nil)
+
+	return err
+}
+
+// handleRunFieldMessage проверяет значение очередного поля регистрации. Когда все поля заполнены,
+// присоединяет студента к квизу.
+func (b *Bot) handleRunFieldMessage(ctx context.Context, message *client.Message, current dialog.Dialog) error {
+	userID := message.From.ID
+	runID := current.Data[dialog.KeyRunID]
+	field := current.Data[dialog.KeyField]
+
+	value, err := auth.ParseField(field, message.Text)
+	if errors.Is(err, auth.ErrValidation) {
+		slog.Debug("incorrect registration field", "error", err, "field", field)
+
+		_, err = b.sender.Message(message.Chat.ID, msgInvalidField+"\n\n"+fieldPrompt(field), nil)
+
+		return err
+	}
+
+	entered := make(map[string]string)
+
+	for key, enteredValue := range current.Data {
+		if enteredField, ok := strings.CutPrefix(key, dialog.KeyFieldValuePrefix); ok {
+			entered[enteredField] = enteredValue
+		}
+	}
+
+	entered[field] = value
+	b.saveUserField(ctx, userID, field, value)
+
+	_, missing, err := b.runFields(ctx, userID, runID, entered)
+	if err != nil {
+		// запуск успели выгрузить из памяти
+		b.resetDialog(ctx, userID)
+
+		_, err = b.sender.Message(message.Chat.ID, msgUnknownQuiz, nil)
+
+		return err
+	}
+
+	if len(missing) > 0 {
+		return b.askRunField(ctx, message.Chat.ID, userID, runID, missing[0], entered)
+	}
+
+	b.resetDialogState(ctx, userID, dialog.StateAwaitingRunFields)
+
+	return b.handleStudentsJoin(ctx, message, runID, entered)
+}
+
+// fieldPrompt возвращает вопрос для поля регистрации.
+func fieldPrompt(field string) string {
+	if prompt, ok := fieldPrompts[field]; ok {
+		return prompt
+	}
+
+	return fmt.Sprintf("Квиз просит указать поле «%s». Отправьте значение одним сообщением.", field)
+}
@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/letsssgooo/quizBot/internal/access"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+)
+
+// handleRegradeCommand обрабатывает /regrade <runID> <номер вопроса> <ключ>.
+// Ключ: буквы правильных вариантов через запятую ("B" или "A,C"),
+// "any" — засчитать любой ответ, "void" — аннулировать вопрос.
+func (b *Bot) handleRegradeCommand(ctx context.Context, message *client.Message, text []string) error {
+	if len(text) != 4 {
+		_, err := b.sender.Message(message.Chat.ID, msgRegradeUsage, nil)
+
+		return err
+	}
+
+	runID := text[1]
+
+	restored := b.restoreRun(ctx, runID)
+
+	b.mu.Lock()
+	quiz, ok := b.runIDToQuiz[runID]
+	b.mu.Unlock()
+
+	if !restored || !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgUnknownQuiz, nil)
+
+		return err
+	}
+
+	allowed, err := b.canAccessRun(ctx, runID, message.From.ID, access.PermEdit)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		_, err = b.sender.Message(message.Chat.ID, msgNotQuizOwner, nil)
+
+		return err
+	}
+
+	questionNumber, err := strconv.Atoi(text[2])
+	if err != nil {
+		_, err = b.sender.Message(message.Chat.ID, msgRegradeUsage, nil)
+
+		return err
+	}
+
+	key, err := parseRegradeKey(text[3])
+	if err != nil {
+		_, err = b.sender.Message(message.Chat.ID, msgRegradeUsage, nil)
+
+		return err
+	}
+
+	changes, err := b.engine.RegradeQuestion(runID, questionNumber-1, key)
+	if err != nil {
+		reply := msgRegradeFailed
+		if errors.Is(err, engine.ErrNotFinished) {
+			reply = msgRegradeNotFinished
+		}
+
+		_, err = b.sender.Message(message.Chat.ID, reply, nil)
+
+		return err
+	}
+
+	b.saveRunResults(runID)
+	// сохранённое состояние выгруженного запуска не должно откатить перепроверку после рестарта
+	b.archiveRun(ctx, runID)
+
+	for _, change := range changes {
+		// участники завершённого запуска освобождены, личный чат совпадает с ID пользователя
+		chatID := change.Participant.TelegramID
+
+		msg := fmt.Sprintf(
+			msgRegradeStudentNotice,
+			quiz.Title,
+			questionNumber,
+			change.OldScore,
+			change.OldRank,
+			change.NewScore,
+			change.NewRank,
+		)
+
+		// один заблокировавший бота студент не должен оставить остальных без уведомления
+		_, err = b.client.SendMessage(chatID, msg, nil)
+		if err != nil {
+			slog.Error("failed to notify about regrade", "error", err, "chat", chatID, "run", runID)
+		}
+	}
+
+	msg := fmt.Sprintf(msgRegradeDone, questionNumber, len(changes))
+
+	_, err = b.sender.Message(message.Chat.ID, msg, nil)
+	if err != nil {
+		return err
+	}
+
+	csvData, err := b.engine.ExportCSV(runID)
+	if err != nil {
+		return err
+	}
+
+	fileName := fmt.Sprintf(`Результаты квиза "%s" (перепроверка)`, quiz.Title)
+
+	return b.sender.Document(message.Chat.ID, fileName, csvData)
+}
+
+// parseRegradeKey разбирает ключ перепроверки из аргумента команды.
+func parseRegradeKey(arg string) (engine.RegradeKey, error) {
+	switch strings.ToLower(arg) {
+	case "any":
+		return engine.RegradeKey{AcceptAny: true}, nil
+	case "void":
+		return engine.RegradeKey{Void: true}, nil
+	}
+
+	var key engine.RegradeKey
+
+	for _, letter := range strings.Split(arg, ",") {
+		idx, ok := engine.LetterToIndex(strings.ToUpper(strings.TrimSpace(letter)))
+		if !ok {
+			return engine.RegradeKey{}, engine.ErrConvertLetterToIndex
+		}
+
+		key.Correct = append(key.Correct, idx)
+	}
+
+	return key, nil
+}
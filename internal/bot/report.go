@@ -0,0 +1,268 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+)
+
+const (
+	messageLimit      = 4000 // лимит Telegram — 4096 символов, оставляем запас
+	maxReportMessages = 3    // более длинный отчёт отправляется файлом
+)
+
+// sendParticipantReport отправляет участнику подробный отчёт по запуску.
+func (b *Bot) sendParticipantReport(chatID int64, runID string, userID int64) error {
+	report, err := b.engine.GetParticipantReport(runID, userID)
+	if err != nil {
+		return err
+	}
+
+	blocks := append([]string{formatReportHeader(report)}, formatReportItems(report)...)
+
+	chunks := splitMessage(blocks)
+	if len(chunks) > maxReportMessages {
+		fileName := fmt.Sprintf(`Отчёт по квизу "%s".txt`, report.QuizTitle)
+
+		return b.sender.Document(chatID, fileName, []byte(strings.Join(blocks, "")))
+	}
+
+	for _, chunk := range chunks {
+		_, err = b.sender.Message(chatID, chunk, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleReportCommand обрабатывает /report и /report <ID запуска>.
+// Без аргумента показывает список запусков, в которых участвовал студент.
+func (b *Bot) handleReportCommand(message *client.Message, text []string) error {
+	if len(text) > 1 {
+		return b.sendReportOrError(message.Chat.ID, text[1], message.From.ID)
+	}
+
+	runs := b.takenRuns(message.From.ID)
+	if len(runs) == 0 {
+		_, err := b.sender.Message(message.Chat.ID, msgNoReports, nil)
+
+		return err
+	}
+
+	keyboard := client.InlineKeyboardMarkup{}
+
+	for _, run := range runs {
+		b.mu.Lock()
+		quiz, ok := b.runIDToQuiz[run.ID]
+		b.mu.Unlock()
+
+		// запуск выгрузили из памяти после takenRuns
+		if !ok {
+			continue
+		}
+
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []client.InlineKeyboardButton{
+			{
+				Text:         fmt.Sprintf("%s (%s)", quiz.Title, run.StartedAt.Format("02.01.2006 15:04")),
+				CallbackData: fmt.Sprintf("report %s", run.ID),
+			},
+		})
+	}
+
+	_, err := b.sender.Message(message.Chat.ID, msgChooseReport, &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
+
+// handleReportCallbackUpdate отправляет отчёт по выбранному запуску.
+// Формат данных: "report <runID>".
+func (b *Bot) handleReportCallbackUpdate(callback *client.CallbackQuery) error {
+	err := b.client.AnswerCallback(callback.ID, "")
+	if err != nil {
+		return err
+	}
+
+	runID := strings.TrimPrefix(callback.Data, "report ")
+
+	return b.sendReportOrError(callback.Message.Chat.ID, runID, callback.From.ID)
+}
+
+// sendReportOrError отправляет отчёт или объясняет, почему он недоступен.
+// Выгруженный из памяти запуск возвращается из хранилища.
+func (b *Bot) sendReportOrError(chatID int64, runID string, userID int64) error {
+	b.restoreRun(context.Background(), runID)
+
+	err := b.sendParticipantReport(chatID, runID, userID)
+
+	switch {
+	case errors.Is(err, engine.ErrNotFinished):
+		_, err = b.sender.Message(chatID, msgReportNotFinished, nil)
+	case errors.Is(err, engine.ErrNotParticipant):
+		_, err = b.sender.Message(chatID, msgReportNotParticipant, nil)
+	case err != nil:
+		_, err = b.sender.Message(chatID, msgUnknownQuiz, nil)
+	}
+
+	return err
+}
+
+// formatReportHeader формирует заголовок отчёта.
+func formatReportHeader(report *engine.ParticipantReport) string {
+	return fmt.Sprintf(
+		"Отчёт по квизу %s\n\nБаллы: %d из %d, место %d\nОбщее время ответов: %.1f с\n\n",
+		report.QuizTitle,
+		report.Entry.Score,
+		report.MaxScore,
+		report.Entry.Rank,
+		report.Entry.TotalTime.Seconds(),
+	)
+}
+
+// formatReportItems формирует блоки отчёта, по одному на вопрос.
+func formatReportItems(report *engine.ParticipantReport) []string {
+	items := make([]string, 0, len(report.Items))
+
+	for _, item := range report.Items {
+		var str strings.Builder
+
+		answer := item.Answer
+		question := item.Question
+
+		points := 0
+		if answer != nil {
+			points = answer.Points
+		}
+
+		str.WriteString(fmt.Sprintf(
+			"%s Вопрос %d (%d из %d баллов)\n%s\n",
+			reportMark(item),
+			item.QuestionIdx+1,
+			points,
+			item.MaxPoints,
+			question.Text,
+		))
+
+		switch {
+		case answer == nil:
+			str.WriteString("Ваш ответ: нет ответа\n")
+		case question.IsOpen():
+			str.WriteString("Ваш ответ: " + answer.Text + "\n")
+		default:
+			str.WriteString("Ваш ответ: " + formatOption(&question, answer.AnswerIdx) + "\n")
+		}
+
+		if !question.IsOpen() {
+			str.WriteString("Правильный ответ: " + formatCorrectOptions(&question) + "\n")
+		}
+
+		if answer != nil {
+			str.WriteString(fmt.Sprintf("Время ответа: %.1f с\n", answer.ResponseTime.Seconds()))
+
+			if answer.Pending {
+				str.WriteString("Ответ ещё на проверке у преподавателя\n")
+			}
+
+			if answer.Comment != "" {
+				str.WriteString("Комментарий преподавателя: " + answer.Comment + "\n")
+			}
+		}
+
+		if question.Explanation != "" {
+			str.WriteString("Пояснение: " + question.Explanation + "\n")
+		}
+
+		str.WriteString("\n")
+		items = append(items, str.String())
+	}
+
+	return items
+}
+
+// reportMark возвращает значок результата по вопросу.
+func reportMark(item engine.ReportItem) string {
+	switch {
+	case item.Answer == nil:
+		return "➖"
+	case item.Answer.Pending:
+		return "⏳"
+	case item.Answer.Points >= item.MaxPoints && item.MaxPoints > 0:
+		return "✅"
+	case item.Answer.Points > 0:
+		return "🟡"
+	default:
+		return "❌"
+	}
+}
+
+// formatOption возвращает вариант ответа вместе с буквой.
+func formatOption(question *engine.Question, idx int) string {
+	if idx < 0 || idx >= len(question.Options) {
+		return "—"
+	}
+
+	return fmt.Sprintf("%s. %s", engine.IndexToLetter(idx), question.Options[idx])
+}
+
+// formatCorrectOptions перечисляет правильные варианты вопроса с текстом.
+func formatCorrectOptions(question *engine.Question) string {
+	var options []string
+
+	for _, idx := range question.CorrectOptions() {
+		options = append(options, formatOption(question, idx))
+	}
+
+	return strings.Join(options, "; ")
+}
+
+// formatCorrectLetters перечисляет буквы правильных вариантов вопроса через запятую.
+func formatCorrectLetters(question *engine.Question) string {
+	var letters []string
+
+	for _, idx := range question.CorrectOptions() {
+		letters = append(letters, engine.IndexToLetter(idx))
+	}
+
+	return strings.Join(letters, ", ")
+}
+
+// splitMessage собирает блоки в сообщения, не превышающие messageLimit символов.
+// Слишком длинный блок разбивается по символам.
+func splitMessage(blocks []string) []string {
+	var (
+		chunks  []string
+		current strings.Builder
+	)
+
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, block := range blocks {
+		runes := []rune(block)
+
+		for len(runes) > messageLimit {
+			flush()
+			chunks = append(chunks, string(runes[:messageLimit]))
+			runes = runes[messageLimit:]
+		}
+
+		if len([]rune(current.String()))+len(runes) > messageLimit {
+			flush()
+		}
+
+		current.WriteString(string(runes))
+	}
+
+	flush()
+
+	return chunks
+}
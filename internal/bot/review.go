@@ -0,0 +1,329 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+	"github.com/letsssgooo/quizBot/internal/review"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+const (
+	timeoutReviewStorage = 2 * time.Second
+	reviewSessionSize    = 20        // максимум карточек за одно повторение
+	reviewCheckPeriod    = time.Hour // как часто планировщик ищет карточки к повторению
+	reviewRemindPeriod   = 24 * time.Hour
+	forgottenLimit       = 10
+)
+
+// reviewSession — текущее повторение студента.
+type reviewSession struct {
+	cards   []*review.Card
+	current int
+	correct int
+}
+
+// addReviewCards добавляет в колоды студентов вопросы, на которых они ошиблись в запуске.
+// Ошибки хранилища только логируются: результаты квиза важнее колоды повторения.
+func (b *Bot) addReviewCards(runID string) {
+	reviewStorage, ok := b.storage.(storage.ReviewStorage)
+	if !ok {
+		return
+	}
+
+	b.mu.Lock()
+	quiz, ok := b.runIDToQuiz[runID]
+	savedQuizID, saved := b.runIDToSavedQuizID[runID]
+	b.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	// повторные запуски сохранённого квиза должны попадать в те же карточки
+	quizID := quiz.ID
+	if saved {
+		quizID = savedQuizID
+	}
+
+	missed, err := b.engine.GetMissedQuestions(runID)
+	if err != nil {
+		slog.Error("failed to get missed questions", "error", err, "run", runID)
+
+		return
+	}
+
+	questions, err := b.engine.GetRunQuestions(runID)
+	if err != nil {
+		slog.Error("failed to get run questions", "error", err, "run", runID)
+
+		return
+	}
+
+	cardModels := make([]*models.ReviewCardModel, 0)
+
+	for _, card := range review.CardsFromRun(quiz, quizID, questions, missed, time.Now()) {
+		model, err := card.ToModel()
+		if err != nil {
+			slog.Error("failed to encode review card", "error", err, "run", runID)
+
+			return
+		}
+
+		cardModels = append(cardModels, model)
+	}
+
+	if len(cardModels) == 0 {
+		return
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutReviewStorage)
+	defer cancelFunc()
+
+	if err = reviewStorage.AddReviewCards(ctx, cardModels); err != nil {
+		slog.Error("failed to save review cards", "error", err, "run", runID)
+	}
+}
+
+// handleReviewCommand начинает повторение карточек, срок которых наступил.
+func (b *Bot) handleReviewCommand(ctx context.Context, message *client.Message) error {
+	reviewStorage, ok := b.storage.(storage.ReviewStorage)
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgReviewUnavailable, nil)
+
+		return err
+	}
+
+	if b.inActiveRun(message.From.ID) {
+		_, err := b.sender.Message(message.Chat.ID, msgReviewDuringRun, nil)
+
+		return err
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutReviewStorage)
+	defer cancelFunc()
+
+	cardModels, err := reviewStorage.GetDueReviewCards(ctx, message.From.ID, time.Now(), reviewSessionSize)
+	if err != nil {
+		return err
+	}
+
+	if len(cardModels) == 0 {
+		_, err = b.sender.Message(message.Chat.ID, msgNoReviewCards, nil)
+
+		return err
+	}
+
+	session := &reviewSession{cards: make([]*review.Card, 0, len(cardModels))}
+
+	for _, model := range cardModels {
+		card, err := review.FromModel(model)
+		if err != nil {
+			return err
+		}
+
+		session.cards = append(session.cards, card)
+	}
+
+	b.mu.Lock()
+	delete(b.userIDToPractice, message.From.ID)
+	b.userIDToReview[message.From.ID] = session
+	b.mu.Unlock()
+
+	_, err = b.sender.Message(message.Chat.ID, fmt.Sprintf(msgReviewStarted, len(session.cards)), nil)
+	if err != nil {
+		return err
+	}
+
+	return b.sendReviewCard(message.Chat.ID, session)
+}
+
+// sendReviewCard отправляет вопрос текущей карточки повторения.
+func (b *Bot) sendReviewCard(chatID int64, session *reviewSession) error {
+	card := session.cards[session.current]
+	header := fmt.Sprintf(
+		"Повторение %d из %d (квиз «%s»)",
+		session.current+1,
+		len(session.cards),
+		card.QuizTitle,
+	)
+
+	_, err := b.sender.Message(chatID, formatSoloQuestion(header, &card.Question, 0), nil)
+
+	return err
+}
+
+// handleReviewAnswer проверяет ответ на карточку, назначает следующее повторение и показывает пояснение.
+func (b *Bot) handleReviewAnswer(
+	ctx context.Context,
+	message *client.Message,
+	session *reviewSession,
+) error {
+	card := session.cards[session.current]
+
+	answerIdx, ok := engine.LetterToIndex(strings.ToUpper(strings.TrimSpace(message.Text)))
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgPracticeInvalidAnswer, nil)
+
+		return err
+	}
+
+	correct, ok := card.Answer(answerIdx, time.Now())
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgPracticeInvalidAnswer, nil)
+
+		return err
+	}
+
+	model, err := card.ToModel()
+	if err != nil {
+		return err
+	}
+
+	saveCtx, cancelFunc := context.WithTimeout(ctx, timeoutReviewStorage)
+	defer cancelFunc()
+
+	reviewStorage := b.storage.(storage.ReviewStorage)
+	if err = reviewStorage.UpdateReviewCard(saveCtx, model); err != nil {
+		slog.Error("failed to update review card", "error", err, "user", card.StudentID)
+	}
+
+	var msg string
+	if correct {
+		session.correct++
+		msg = fmt.Sprintf(msgReviewCorrect, card.Interval)
+	} else {
+		msg = fmt.Sprintf(msgReviewWrong, formatCorrectLetters(&card.Question))
+	}
+
+	if card.Question.Explanation != "" {
+		msg += "\n\nПояснение: " + card.Question.Explanation
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, msg, nil)
+	if err != nil {
+		return err
+	}
+
+	session.current++
+	if session.current < len(session.cards) {
+		return b.sendReviewCard(message.Chat.ID, session)
+	}
+
+	b.mu.Lock()
+	delete(b.userIDToReview, message.From.ID)
+	b.mu.Unlock()
+
+	msg = fmt.Sprintf(msgReviewFinished, session.correct, len(session.cards))
+	_, err = b.sender.Message(message.Chat.ID, msg, nil)
+
+	return err
+}
+
+// runReviewReminders периодически напоминает студентам о карточках, которые пора повторить.
+// Каждый студент получает не больше одного напоминания за reviewRemindPeriod.
+func (b *Bot) runReviewReminders(ctx context.Context) {
+	reviewStorage, ok := b.storage.(storage.ReviewStorage)
+	if !ok {
+		return
+	}
+
+	remindedAt := make(map[int64]time.Time)
+
+	ticker := time.NewTicker(reviewCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			queryCtx, cancelFunc := context.WithTimeout(ctx, timeoutReviewStorage)
+			counts, err := reviewStorage.CountDueReviewCards(queryCtx, now)
+			cancelFunc()
+
+			if err != nil {
+				slog.Error("failed to count due review cards", "error", err)
+
+				continue
+			}
+
+			for studentID, cnt := range counts {
+				if now.Sub(remindedAt[studentID]) < reviewRemindPeriod || b.isBusy(studentID) {
+					continue
+				}
+
+				// в личном чате ID чата совпадает с ID пользователя
+				_, err = b.client.SendMessage(studentID, fmt.Sprintf(msgReviewReminder, cnt), nil)
+				if err != nil {
+					slog.Error("failed to send review reminder", "error", err, "user", studentID)
+
+					continue
+				}
+
+				remindedAt[studentID] = now
+			}
+		}
+	}
+}
+
+// isBusy сообщает, занят ли студент квизом, тренировкой или повторением.
+func (b *Bot) isBusy(userID int64) bool {
+	b.mu.Lock()
+	_, isPractising := b.userIDToPractice[userID]
+	_, isReviewing := b.userIDToReview[userID]
+	b.mu.Unlock()
+
+	return isPractising || isReviewing || b.inActiveRun(userID)
+}
+
+// handleForgottenCommand показывает преподавателю вопросы его квизов, в которых студенты ошибаются чаще всего.
+// Роль преподавателя проверяет роутер.
+func (b *Bot) handleForgottenCommand(ctx context.Context, message *client.Message) error {
+	reviewStorage, ok := b.storage.(storage.ReviewStorage)
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgReviewUnavailable, nil)
+
+		return err
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutReviewStorage)
+	defer cancelFunc()
+
+	questions, err := reviewStorage.GetForgottenQuestions(ctx, message.From.ID, forgottenLimit)
+	if err != nil {
+		return err
+	}
+
+	if len(questions) == 0 {
+		_, err = b.sender.Message(message.Chat.ID, msgNoForgottenQuestions, nil)
+
+		return err
+	}
+
+	var str strings.Builder
+
+	str.WriteString("Вопросы, в которых студенты ошибаются чаще всего:\n\n")
+
+	for i, question := range questions {
+		str.WriteString(fmt.Sprintf(
+			"%d. «%s», вопрос %d: %s\nОшибок: %d, студентов: %d\n\n",
+			i+1,
+			question.QuizTitle,
+			question.QuestionIdx+1,
+			question.QuestionText,
+			question.Lapses,
+			question.Students,
+		))
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, str.String(), nil)
+
+	return err
+}
@@ -0,0 +1,130 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/letsssgooo/quizBot/internal/access"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+)
+
+// handleSectionFinishedEvent публикует итоги раунда участникам и преподавателю.
+// Преподаватель получает кнопку для продолжения квиза, если впереди есть раунды.
+func (b *Bot) handleSectionFinishedEvent(runID string, event engine.QuizEvent) error {
+	standings, err := b.engine.GetSectionStandings(runID, event.Section)
+	if err != nil {
+		return err
+	}
+
+	var top strings.Builder
+
+	top.WriteString("Топ-10 раунда:\n")
+
+	for _, entry := range standings[:min(10, len(standings))] {
+		top.WriteString(fmt.Sprintf(
+			"%d. %s - %d баллов\n",
+			entry.Rank,
+			participantName(entry.Participant),
+			entry.SectionScores[event.Section],
+		))
+	}
+
+	intermission := ""
+	if !event.LastSection {
+		intermission = "\n" + msgIntermissionByLecturer
+		if event.TimeLeft > 0 {
+			intermission = "\n" + fmt.Sprintf(msgIntermissionByTimer, int(event.TimeLeft.Seconds()))
+		}
+	}
+
+	if groupChatID, ok := b.groupChatID(runID); ok {
+		msg := fmt.Sprintf("Раунд «%s» завершён!\n\n%s%s", event.Section, top.String(), intermission)
+
+		_, err = b.client.SendMessage(groupChatID, msg, nil)
+		if err != nil {
+			return err
+		}
+
+		return b.sendOwnerStandings(runID, event, top.String())
+	}
+
+	for _, entry := range standings {
+		b.mu.Lock()
+		chatID, ok := b.userIDToChatID[entry.Participant.TelegramID]
+		b.mu.Unlock()
+
+		if !ok {
+			continue
+		}
+
+		msg := fmt.Sprintf(
+			"Раунд «%s» завершён!\n\nВаш результат за раунд: %d баллов (место %d)\n\n%s%s",
+			event.Section,
+			entry.SectionScores[event.Section],
+			entry.Rank,
+			top.String(),
+			intermission,
+		)
+
+		_, err = b.client.SendMessage(chatID, msg, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.sendOwnerStandings(runID, event, top.String())
+}
+
+// sendOwnerStandings отправляет преподавателю итоги раунда и кнопку продолжения квиза.
+func (b *Bot) sendOwnerStandings(runID string, event engine.QuizEvent, top string) error {
+	b.mu.Lock()
+	ownerChatID := b.runIDToOwnerChatID[runID]
+	b.mu.Unlock()
+
+	msg := fmt.Sprintf("Раунд «%s» завершён.\n\n%s", event.Section, top)
+
+	var opts *client.SendOptions
+
+	if !event.LastSection {
+		opts = &client.SendOptions{
+			ReplyMarkup: &client.InlineKeyboardMarkup{
+				InlineKeyboard: [][]client.InlineKeyboardButton{
+					{
+						{Text: "▶️ Продолжить", CallbackData: fmt.Sprintf("continue %s", runID)},
+					},
+				},
+			},
+		}
+	}
+
+	_, err := b.client.SendMessage(ownerChatID, msg, opts)
+
+	return err
+}
+
+// handleContinueCallbackUpdate завершает перерыв между раундами по кнопке преподавателя.
+func (b *Bot) handleContinueCallbackUpdate(ctx context.Context, callback *client.CallbackQuery) error {
+	runID := strings.TrimPrefix(callback.Data, "continue ")
+
+	allowed, err := b.canAccessRun(ctx, runID, callback.From.ID, access.PermRun)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return b.client.AnswerCallback(callback.ID, msgNotQuizOwner)
+	}
+
+	if err = b.engine.ContinueRun(runID); err != nil {
+		return b.client.AnswerCallback(callback.ID, msgNoIntermission)
+	}
+
+	err = b.client.AnswerCallback(callback.ID, msgNextSection)
+	if err != nil {
+		return err
+	}
+
+	return b.client.EditMessage(callback.Message.Chat.ID, callback.Message.MessageID, callback.Message.Text, nil)
+}
@@ -0,0 +1,506 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/letsssgooo/quizBot/internal/access"
+	"github.com/letsssgooo/quizBot/internal/auth"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+const inviteTTL = 7 * 24 * time.Hour // срок действия ссылки-приглашения
+
+// resultRecipient — чат, которому отправляются итоги запуска, и роль его владельца в квизе.
+type resultRecipient struct {
+	chatID int64
+	role   access.Role
+}
+
+// quizRole возвращает роль пользователя в сохранённом квизе: владелец, роль из списка доступа
+// или access.RoleNone, если квиз ему не открыт.
+func (b *Bot) quizRole(ctx context.Context, quizID string, ownerID, userID int64) (access.Role, error) {
+	if ownerID == userID {
+		return access.RoleOwner, nil
+	}
+
+	shareStorage, ok := b.storage.(storage.ShareStorage)
+	if !ok || quizID == "" {
+		return access.RoleNone, nil
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	share, err := shareStorage.GetShare(ctx, quizID, userID)
+	if errors.Is(err, storage.ErrShareNotFound) {
+		return access.RoleNone, nil
+	} else if err != nil {
+		return access.RoleNone, err
+	}
+
+	return access.Role(share.Role), nil
+}
+
+// canAccessRun сообщает, может ли пользователь выполнить действие perm над запуском в памяти.
+// Владельцу квиза доступно всё. Тот, кто запустил квиз, ведёт запуск до конца, а проверять открытые
+// ответы и перепроверять вопросы может, только если это разрешает его роль в сохранённом квизе.
+func (b *Bot) canAccessRun(ctx context.Context, runID string, userID int64, perm access.Permission) (bool, error) {
+	b.mu.Lock()
+	quiz, ok := b.runIDToQuiz[runID]
+	starterID := b.runIDToOwnerChatID[runID] // ID личного чата совпадает с ID пользователя
+	savedQuizID := b.runIDToSavedQuizID[runID]
+	b.mu.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	role, err := b.quizRole(ctx, savedQuizID, quiz.OwnerID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return role.CanInRun(perm, starterID == userID), nil
+}
+
+// resultRecipients возвращает чаты, которым отправляются итоги запуска: чат того, кто запустил квиз,
+// владелец квиза и все, кому квиз открыт. Ошибка списка доступа только логируется.
+func (b *Bot) resultRecipients(ctx context.Context, runID string) []resultRecipient {
+	b.mu.Lock()
+	starterChatID := b.runIDToOwnerChatID[runID]
+	ownerID := b.runIDToQuiz[runID].OwnerID
+	savedQuizID, saved := b.runIDToSavedQuizID[runID]
+	b.mu.Unlock()
+
+	// тот, кто запустил квиз, получает итоги всегда, а подсказку про /regrade — только если роль это разрешает
+	starterRole, err := b.quizRole(ctx, savedQuizID, ownerID, starterChatID)
+	if err != nil {
+		slog.Error("failed to get quiz role", "error", err, "run", runID)
+	}
+
+	recipients := []resultRecipient{{chatID: starterChatID, role: starterRole}}
+	if ownerID != starterChatID {
+		recipients = append(recipients, resultRecipient{chatID: ownerID, role: access.RoleOwner})
+	}
+
+	shareStorage, ok := b.storage.(storage.ShareStorage)
+	if !ok || !saved {
+		return recipients
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	shares, err := shareStorage.ListShares(ctx, savedQuizID)
+	if err != nil {
+		slog.Error("failed to list quiz shares", "error", err, "run", runID)
+
+		return recipients
+	}
+
+	for _, share := range shares {
+		if share.TelegramID != starterChatID {
+			recipients = append(recipients, resultRecipient{chatID: share.TelegramID, role: access.Role(share.Role)})
+		}
+	}
+
+	return recipients
+}
+
+// accessibleQuizzes возвращает сохранённые квизы преподавателя и чужие квизы, открытые ему.
+func (b *Bot) accessibleQuizzes(ctx context.Context, userID int64) ([]*models.QuizModel, error) {
+	quizStorage := b.storage.(storage.QuizStorage)
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	quizzes, err := quizStorage.ListQuizzes(ctx, userID, maxListedQuizzes)
+	if err != nil {
+		return nil, err
+	}
+
+	shareStorage, ok := b.storage.(storage.ShareStorage)
+	if !ok || len(quizzes) >= maxListedQuizzes {
+		return quizzes, nil
+	}
+
+	shared, err := shareStorage.ListSharedQuizzes(ctx, userID, maxListedQuizzes-len(quizzes))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(quizzes, shared...), nil
+}
+
+// saveUsername запоминает username пользователя, чтобы его можно было пригласить к квизу.
+func (b *Bot) saveUsername(ctx context.Context, user *client.User) {
+	shareStorage, ok := b.storage.(storage.ShareStorage)
+	if !ok || user.Username == "" {
+		return
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	if err := shareStorage.SaveUsername(ctx, user.ID, user.Username); err != nil {
+		slog.Error("failed to save username", "error", err, "user", user.ID)
+	}
+}
+
+// handleShareCommand обрабатывает /share @username <роль> <название квиза>: открывает свой квиз
+// другому преподавателю.
+func (b *Bot) handleShareCommand(ctx context.Context, message *client.Message, args []string) error {
+	shareStorage, ok := b.storage.(storage.ShareStorage)
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgMyQuizzesUnavailable, nil)
+
+		return err
+	}
+
+	if len(args) < 3 {
+		_, err := b.sender.Message(message.Chat.ID, msgShareUsage, nil)
+
+		return err
+	}
+
+	role, err := access.ParseRole(args[1])
+	if err != nil {
+		_, err = b.sender.Message(message.Chat.ID, msgShareUsage, nil)
+
+		return err
+	}
+
+	quizStorage := b.storage.(storage.QuizStorage)
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	quizzes, err := quizStorage.ListQuizzes(storageCtx, message.From.ID, maxListedQuizzes)
+	if err != nil {
+		return err
+	}
+
+	quiz := findSavedQuizByTitle(quizzes, strings.Join(args[2:], " "))
+	if quiz == nil {
+		_, err = b.sender.Message(message.Chat.ID, msgHistoryQuizNotFound, nil)
+
+		return err
+	}
+
+	userID, err := shareStorage.FindUserByUsername(storageCtx, strings.TrimPrefix(args[0], "@"))
+	if errors.Is(err, storage.ErrUserNotFound) {
+		_, err = b.sender.Message(message.Chat.ID, msgShareUserNotFound, nil)
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if userID == message.From.ID {
+		_, err = b.sender.Message(message.Chat.ID, msgShareSelf, nil)
+
+		return err
+	}
+
+	userRole, err := b.roleOf(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if userRole != auth.RoleLecturer {
+		_, err = b.sender.Message(message.Chat.ID, msgShareNotLecturer, nil)
+
+		return err
+	}
+
+	err = shareStorage.SaveShare(storageCtx, &models.QuizShareModel{
+		QuizID:     quiz.ID,
+		TelegramID: userID,
+		Role:       string(role),
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// ID личного чата совпадает с ID пользователя; он уже писал боту, раз бот знает его username
+	_, err = b.sender.Message(userID, fmt.Sprintf(msgQuizShared, quiz.Title, role.Name()), nil)
+	if err != nil {
+		slog.Debug("failed to notify about quiz share", "error", err, "user", userID)
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, fmt.Sprintf(msgShareGranted, args[0], quiz.Title, role.Name()), nil)
+
+	return err
+}
+
+// sendShareList показывает владельцу, кому открыт квиз, с кнопками отзыва доступа и создания приглашений.
+func (b *Bot) sendShareList(ctx context.Context, chatID int64, quiz *models.QuizModel) error {
+	shareStorage, ok := b.storage.(storage.ShareStorage)
+	if !ok {
+		_, err := b.sender.Message(chatID, msgMyQuizzesUnavailable, nil)
+
+		return err
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	shares, err := shareStorage.ListShares(storageCtx, quiz.ID)
+	if err != nil {
+		return err
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Доступ к квизу %s\n\n", quiz.Title))
+
+	keyboard := client.InlineKeyboardMarkup{}
+
+	if len(shares) == 0 {
+		builder.WriteString("Квиз пока никому не открыт.\n")
+	}
+
+	for _, share := range shares {
+		name := shareName(share)
+		builder.WriteString(fmt.Sprintf("- %s — %s\n", name, access.Role(share.Role).Name()))
+
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []client.InlineKeyboardButton{
+			{
+				Text:         fmt.Sprintf("Закрыть доступ: %s", name),
+				CallbackData: fmt.Sprintf("share rm %s %d", quiz.ID, share.TelegramID),
+			},
+		})
+	}
+
+	var inviteRow []client.InlineKeyboardButton
+	for _, role := range access.ShareableRoles {
+		inviteRow = append(inviteRow, client.InlineKeyboardButton{
+			Text:         fmt.Sprintf("Ссылка: %s", role.Name()),
+			CallbackData: fmt.Sprintf("share link %s %s", role, quiz.ID),
+		})
+	}
+
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, inviteRow)
+
+	builder.WriteString("\n" + msgShareHelp)
+
+	_, err = b.sender.Message(chatID, builder.String(), &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
+
+// handleShareCallbackUpdate обрабатывает кнопки управления доступом к квизу.
+// Формат данных: "share link <роль> <quizID>" или "share rm <quizID> <telegramID>".
+func (b *Bot) handleShareCallbackUpdate(ctx context.Context, callback *client.CallbackQuery) error {
+	fields := strings.Fields(callback.Data)
+	if len(fields) != 4 {
+		return b.client.AnswerCallback(callback.ID, "")
+	}
+
+	shareStorage, ok := b.storage.(storage.ShareStorage)
+	if !ok {
+		return b.client.AnswerCallback(callback.ID, msgMyQuizzesUnavailable)
+	}
+
+	quizID := fields[3]
+	if fields[1] == "rm" {
+		quizID = fields[2]
+	}
+
+	quizStorage := b.storage.(storage.QuizStorage)
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	quiz, err := quizStorage.GetQuiz(storageCtx, quizID)
+	if errors.Is(err, storage.ErrQuizNotFound) {
+		return b.client.AnswerCallback(callback.ID, msgSavedQuizNotFound)
+	} else if err != nil {
+		return err
+	}
+
+	if quiz.OwnerID != callback.From.ID {
+		return b.client.AnswerCallback(callback.ID, msgNotQuizOwner)
+	}
+
+	switch fields[1] {
+	case "link":
+		role, err := access.ParseRole(fields[2])
+		if err != nil {
+			return b.client.AnswerCallback(callback.ID, "")
+		}
+
+		invite := &models.QuizInviteModel{
+			Code:      strings.ReplaceAll(uuid.NewString(), "-", ""),
+			QuizID:    quiz.ID,
+			Role:      string(role),
+			CreatedBy: callback.From.ID,
+			ExpiresAt: time.Now().Add(inviteTTL),
+		}
+
+		if err = shareStorage.SaveInvite(storageCtx, invite); err != nil {
+			return err
+		}
+
+		if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+			return err
+		}
+
+		link := fmt.Sprintf("https://t.me/%s?start=invite_%s", b.botUsername, invite.Code)
+		_, err = b.sender.Message(
+			callback.Message.Chat.ID,
+			fmt.Sprintf(msgInviteLink, quiz.Title, role.Name(), link),
+			nil,
+		)
+
+		return err
+	case "rm":
+		userID, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return b.client.AnswerCallback(callback.ID, "")
+		}
+
+		if err = shareStorage.DeleteShare(storageCtx, quiz.ID, userID); err != nil {
+			return err
+		}
+
+		_, err = b.sender.Message(userID, fmt.Sprintf(msgShareRevoked, quiz.Title), nil)
+		if err != nil {
+			slog.Debug("failed to notify about revoked share", "error", err, "user", userID)
+		}
+
+		if err = b.client.AnswerCallback(callback.ID, msgShareRemoved); err != nil {
+			return err
+		}
+
+		return b.sendShareList(ctx, callback.Message.Chat.ID, quiz)
+	default:
+		return b.client.AnswerCallback(callback.ID, "")
+	}
+}
+
+// handleInviteAccept открывает квиз преподавателю, перешедшему по ссылке-приглашению /start invite_<code>.
+func (b *Bot) handleInviteAccept(ctx context.Context, message *client.Message, code string) error {
+	shareStorage, ok := b.storage.(storage.ShareStorage)
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgMyQuizzesUnavailable, nil)
+
+		return err
+	}
+
+	role, err := b.roleOf(ctx, message.From.ID)
+	if err != nil {
+		return err
+	}
+
+	// приглашение не тратится, пока пользователь не выбрал роль преподавателя
+	if role != auth.RoleLecturer {
+		_, err = b.sender.Message(message.Chat.ID, msgInviteNotLecturer, nil)
+
+		return err
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutQuizStorage)
+	defer cancelFunc()
+
+	invite, err := shareStorage.TakeInvite(storageCtx, code, time.Now())
+	if errors.Is(err, storage.ErrInviteNotFound) {
+		_, err = b.sender.Message(message.Chat.ID, msgInviteInvalid, nil)
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	quiz, err := b.storage.(storage.QuizStorage).GetQuiz(storageCtx, invite.QuizID)
+	if errors.Is(err, storage.ErrQuizNotFound) {
+		_, err = b.sender.Message(message.Chat.ID, msgInviteInvalid, nil)
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if quiz.OwnerID == message.From.ID {
+		_, err = b.sender.Message(message.Chat.ID, msgShareSelf, nil)
+
+		return err
+	}
+
+	err = shareStorage.SaveShare(storageCtx, &models.QuizShareModel{
+		QuizID:     quiz.ID,
+		TelegramID: message.From.ID,
+		Role:       invite.Role,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	roleName := access.Role(invite.Role).Name()
+
+	_, err = b.sender.Message(
+		invite.CreatedBy,
+		fmt.Sprintf(msgInviteUsed, userName(message.From), quiz.Title, roleName),
+		nil,
+	)
+	if err != nil {
+		slog.Debug("failed to notify about accepted invite", "error", err, "user", invite.CreatedBy)
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, fmt.Sprintf(msgQuizShared, quiz.Title, roleName), nil)
+
+	return err
+}
+
+// findSavedQuizByTitle ищет сохранённый квиз по названию без учёта регистра:
+// сначала точное совпадение, затем по подстроке.
+func findSavedQuizByTitle(quizzes []*models.QuizModel, title string) *models.QuizModel {
+	title = strings.ToLower(strings.TrimSpace(title))
+
+	for _, quiz := range quizzes {
+		if strings.ToLower(quiz.Title) == title {
+			return quiz
+		}
+	}
+
+	for _, quiz := range quizzes {
+		if strings.Contains(strings.ToLower(quiz.Title), title) {
+			return quiz
+		}
+	}
+
+	return nil
+}
+
+// shareName возвращает имя того, кому открыт квиз.
+func shareName(share *models.QuizShareModel) string {
+	switch {
+	case share.Username != "":
+		return "@" + share.Username
+	case share.FullName != "":
+		return share.FullName
+	default:
+		return fmt.Sprintf("ID %d", share.TelegramID)
+	}
+}
+
+// userName возвращает имя пользователя Telegram для отображения.
+func userName(user *client.User) string {
+	if user.Username != "" {
+		return "@" + user.Username
+	}
+
+	return strings.TrimSpace(user.FirstName + " " + user.LastName)
+}
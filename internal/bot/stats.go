@@ -0,0 +1,229 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/analytics"
+	"github.com/letsssgooo/quizBot/internal/auth"
+	"github.com/letsssgooo/quizBot/internal/client"
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+const (
+	timeoutStatsStorage = 2 * time.Second
+	maxFoundStudents    = 10 // максимум студентов в ответе на поиск
+	maxStatsTopics      = 10 // максимум тем в статистике
+	maxTrendMonths      = 6  // сколько последних месяцев показывать в динамике, примерно семестр
+)
+
+// handleStatsCommand обрабатывает /stats. Студент получает свою статистику по всем пройденным квизам.
+// Преподаватель ищет студента по имени или ФИО (/stats <запрос>) и видит его статистику по своим квизам.
+func (b *Bot) handleStatsCommand(ctx context.Context, message *client.Message, args []string) error {
+	statsStorage, ok := b.storage.(storage.StatsStorage)
+	if !ok {
+		_, err := b.sender.Message(message.Chat.ID, msgStatsUnavailable, nil)
+
+		return err
+	}
+
+	role, err := b.roleOf(ctx, message.From.ID)
+	if err != nil {
+		return err
+	}
+
+	if role == auth.RoleLecturer {
+		return b.handleLecturerStats(ctx, statsStorage, message, strings.Join(args, " "))
+	}
+
+	stats, err := b.studentStats(ctx, statsStorage, message.From.ID, 0)
+	if err != nil {
+		return err
+	}
+
+	if stats.Quizzes == 0 {
+		_, err = b.sender.Message(message.Chat.ID, msgNoStats, nil)
+
+		return err
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, formatStudentStats("Ваша статистика", stats), nil)
+
+	return err
+}
+
+// handleLecturerStats находит студента среди участников запусков преподавателя.
+// Если найдено несколько студентов, предлагает выбрать одного кнопкой.
+func (b *Bot) handleLecturerStats(
+	ctx context.Context,
+	statsStorage storage.StatsStorage,
+	message *client.Message,
+	query string,
+) error {
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutStatsStorage)
+	defer cancelFunc()
+
+	students, err := statsStorage.FindStudents(storageCtx, message.From.ID, query, maxFoundStudents)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(students) == 0 && query != "":
+		_, err = b.sender.Message(message.Chat.ID, msgStudentNotFound, nil)
+
+		return err
+	case len(students) == 0:
+		_, err = b.sender.Message(message.Chat.ID, msgNoStudents, nil)
+
+		return err
+	case len(students) == 1:
+		return b.sendLecturerStats(ctx, statsStorage, message.Chat.ID, message.From.ID, students[0])
+	}
+
+	keyboard := client.InlineKeyboardMarkup{}
+
+	for _, student := range students {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []client.InlineKeyboardButton{
+			{Text: studentTitle(student), CallbackData: fmt.Sprintf("stats %d", student.TelegramID)},
+		})
+	}
+
+	_, err = b.sender.Message(message.Chat.ID, msgChooseStudent, &client.SendOptions{ReplyMarkup: &keyboard})
+
+	return err
+}
+
+// handleStatsCallbackUpdate показывает преподавателю статистику выбранного студента.
+// Формат данных: "stats <telegramID>".
+func (b *Bot) handleStatsCallbackUpdate(ctx context.Context, callback *client.CallbackQuery) error {
+	statsStorage, ok := b.storage.(storage.StatsStorage)
+	if !ok {
+		return b.client.AnswerCallback(callback.ID, msgStatsUnavailable)
+	}
+
+	telegramID, err := strconv.ParseInt(strings.TrimPrefix(callback.Data, "stats "), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid student in stats callback: %w", err)
+	}
+
+	storageCtx, cancelFunc := context.WithTimeout(ctx, timeoutStatsStorage)
+	defer cancelFunc()
+
+	// студента можно посмотреть, только если он проходил квизы этого преподавателя
+	student, err := statsStorage.GetStudent(storageCtx, callback.From.ID, telegramID)
+	if errors.Is(err, storage.ErrStudentNotFound) {
+		return b.client.AnswerCallback(callback.ID, msgStudentNotFound)
+	} else if err != nil {
+		return err
+	}
+
+	if err = b.client.AnswerCallback(callback.ID, ""); err != nil {
+		return err
+	}
+
+	return b.sendLecturerStats(ctx, statsStorage, callback.Message.Chat.ID, callback.From.ID, student)
+}
+
+// sendLecturerStats отправляет преподавателю статистику студента по его квизам.
+func (b *Bot) sendLecturerStats(
+	ctx context.Context,
+	statsStorage storage.StatsStorage,
+	chatID, ownerID int64,
+	student *models.StudentModel,
+) error {
+	stats, err := b.studentStats(ctx, statsStorage, student.TelegramID, ownerID)
+	if err != nil {
+		return err
+	}
+
+	if stats.Quizzes == 0 {
+		_, err = b.sender.Message(chatID, msgStudentHasNoStats, nil)
+
+		return err
+	}
+
+	title := fmt.Sprintf("Статистика студента %s по вашим квизам", studentTitle(student))
+	_, err = b.sender.Message(chatID, formatStudentStats(title, stats), nil)
+
+	return err
+}
+
+// studentStats загружает сохранённые итоги студента и считает по ним статистику.
+func (b *Bot) studentStats(
+	ctx context.Context,
+	statsStorage storage.StatsStorage,
+	telegramID, ownerID int64,
+) (analytics.StudentStats, error) {
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutStatsStorage)
+	defer cancelFunc()
+
+	runs, err := statsStorage.GetStudentRuns(ctx, telegramID, ownerID)
+	if err != nil {
+		return analytics.StudentStats{}, err
+	}
+
+	topics, err := statsStorage.GetStudentTopics(ctx, telegramID, ownerID)
+	if err != nil {
+		return analytics.StudentStats{}, err
+	}
+
+	return analytics.Student(runs, topics), nil
+}
+
+// studentTitle возвращает ФИО и группу студента, а если он не зарегистрирован — имя из результатов.
+func studentTitle(student *models.StudentModel) string {
+	if student.FullName == "" {
+		return student.Name
+	}
+
+	if student.Group == "" {
+		return student.FullName
+	}
+
+	return fmt.Sprintf("%s (%s)", student.FullName, student.Group)
+}
+
+// formatStudentStats формирует сообщение со статистикой студента.
+func formatStudentStats(title string, stats analytics.StudentStats) string {
+	var str strings.Builder
+
+	str.WriteString(fmt.Sprintf("%s:\n\n", title))
+	str.WriteString(fmt.Sprintf("Пройдено квизов: %d\n", stats.Quizzes))
+	str.WriteString(fmt.Sprintf("Средний результат: %.0f%% от максимума баллов\n", stats.MeanPercent*100))
+	str.WriteString(fmt.Sprintf("Место в рейтинге: в среднем выше %.0f%% участников\n", stats.MeanPercentile*100))
+
+	if len(stats.Topics) > 0 {
+		str.WriteString("\nТемы, от слабых к сильным:\n")
+
+		for _, topic := range stats.Topics[:min(len(stats.Topics), maxStatsTopics)] {
+			str.WriteString(fmt.Sprintf(
+				"• %s: %.0f%% (%d из %d)\n",
+				topic.Topic,
+				topic.Rate*100,
+				topic.Correct,
+				topic.Answered,
+			))
+		}
+	}
+
+	if len(stats.Trend) > 0 {
+		str.WriteString("\nДинамика по месяцам:\n")
+
+		for _, month := range stats.Trend[max(len(stats.Trend)-maxTrendMonths, 0):] {
+			str.WriteString(fmt.Sprintf(
+				"• %s: %.0f%%, квизов: %d\n",
+				month.Month.Format("01.2006"),
+				month.MeanPercent*100,
+				month.Quizzes,
+			))
+		}
+	}
+
+	return str.String()
+}
@@ -6,7 +6,13 @@ const msgStudentsHelp = `Я - телеграм бот для проведени
 
 1) Перейдите по ссылке от преподавателя
 2) Отвечайте на вопросы
-3) Получите от меня свой результат и топ-10 игроков.`
+3) Получите от меня свой результат, топ-10 игроков и подробный отчёт по каждому вопросу.
+
+Отчёт по любому пройденному квизу можно получить позже командой /report.
+
+После квиза его можно пройти ещё раз в режиме тренировки: /practice. Результаты тренировки не влияют на оценку.
+
+Вопросы, в которых вы ошиблись, попадают в вашу колоду повторения: /review покажет те, что пора повторить.`
 
 const msgStudentsData = `Скажите, пожалуйста, ваше ФИО и номер группы (пример: Иванов Иван Иванович БПМИ248).`
 
@@ -25,6 +31,119 @@ const msgMaxParticipantNumber = `К сожалению, в лобби больш
 
 const msgQuizJoin = `Вы присоединены к квизу 👍!`
 
+const msgAlreadyJoined = `Вы уже участвуете в этом квизе`
+
 const msgAnswerAcceptance = `Ваш ответ принят 👌!`
 
 const msgRepeatedAnswer = `Вы уже отвечали, засчитывается только первый ответ.`
+
+const msgEmptyTextAnswer = `Ответ не может быть пустым, напишите его текстом.`
+
+const msgNoActiveQuestion = `Сейчас нет активного вопроса.`
+
+const msgInvalidLetter = `Такого варианта нет, отправьте букву одного из вариантов ответа.`
+
+const msgStaleAnswer = `Этот вопрос уже закрыт`
+
+const msgAnswerRejected = `Ответ не принят`
+
+const msgNotParticipant = `Вы не участвуете в этом квизе`
+
+const msgResultsPendingGrading = `Вопросы закончились 🏁! Преподаватель проверяет открытые ответы, итоговый результат придёт после проверки.`
+
+const msgRegradeStudentNotice = `Результаты квиза %s пересчитаны: преподаватель исправил ключ вопроса %d.
+
+Было: %d баллов (место %d)
+Стало: %d баллов (место %d)`
+
+const msgHint = `💡 Подсказка к вопросу %d: %s
+
+Стоимость: −%d баллов за этот вопрос.`
+
+const msgHintSent = `Подсказка отправлена`
+
+const msgNoMoreHints = `Подсказок к этому вопросу больше нет`
+
+const msgHintAfterAnswer = `Вы уже ответили на этот вопрос`
+
+const msgHintUnavailable = `Вопрос уже закрыт`
+
+const msgIntermissionByLecturer = `Перерыв ☕. Следующий раунд начнётся, когда преподаватель его запустит.`
+
+const msgIntermissionByTimer = `Перерыв ☕. Следующий раунд начнётся через %d секунд или раньше, если преподаватель его запустит.`
+
+const msgPracticeChooseQuiz = `Выберите квиз для тренировки 🏋️:`
+
+const msgPracticeChooseMode = `Тренировка по квизу «%s». Выберите режим:`
+
+const msgPracticeStarted = `Тренировка по квизу «%s» началась! После каждого ответа я покажу правильный вариант и пояснение.`
+
+const msgNoPracticeQuizzes = `Вы ещё не прошли ни одного квиза, тренироваться пока не на чем.`
+
+const msgPracticeQuizNotFound = `Среди пройденных вами квизов такого нет 😔.`
+
+const msgPracticeDuringRun = `Вы участвуете в квизе. Тренировка будет доступна после его окончания.`
+
+const msgPracticeInvalidAnswer = `Отправьте букву одного из вариантов ответа.`
+
+const msgPracticeCorrect = `✅ Верно! +%d баллов`
+
+const msgPracticeWrong = `❌ Неверно. Правильный ответ: %s`
+
+const msgPracticeLate = `⌛ Время вышло, ответ не засчитан. Правильный ответ: %s`
+
+const msgPracticeOpenAnswer = `Ответ записан. Открытые ответы в тренировке не оцениваются.`
+
+const msgPracticeFinished = `Тренировка по квизу «%s» завершена 🏁!
+
+Баллы: %d из %d
+Правильных ответов: %d из %d
+Время: %s
+
+Результат тренировки не попадает в таблицу лидеров и результаты преподавателя.`
+
+const msgReviewStarted = `Повторение началось: вопросов к повторению — %d 🧠.`
+
+const msgNoReviewCards = `Сейчас повторять нечего 👍. Я напомню, когда придёт время.`
+
+const msgReviewDuringRun = `Вы участвуете в квизе. Повторение будет доступно после его окончания.`
+
+const msgReviewUnavailable = `Повторение сейчас недоступно 😔.`
+
+const msgReviewCorrect = `✅ Верно! Следующее повторение через %d дн.`
+
+const msgReviewWrong = `❌ Неверно. Правильный ответ: %s. Повторим этот вопрос завтра.`
+
+const msgReviewFinished = `Повторение завершено 🏁! Правильных ответов: %d из %d.`
+
+const msgReviewReminder = `🧠 Пора повторить вопросы, в которых вы ошибались: %d шт. Отправьте /review, чтобы начать.`
+
+const msgChooseReport = `Выберите квиз, по которому нужен отчёт 📋:`
+
+const msgNoReports = `Вы ещё не прошли ни одного квиза.`
+
+const msgReportNotFinished = `Отчёт будет доступен после окончания квиза.`
+
+const msgReportNotParticipant = `Вы не участвовали в этом квизе.`
+
+const msgNoStats = `Вы ещё не прошли ни одного квиза. Статистика появится после первого завершённого квиза.`
+
+const msgInvalidField = `Значение не подходит, попробуйте ещё раз.`
+
+const msgFillFieldsInPrivate = `Квиз просит заполнить данные о себе. Ответьте боту в личных сообщениях, и вы будете участвовать.`
+
+const msgWriteToBotFirst = `Квиз просит заполнить данные о себе. Напишите боту /start в личные сообщения и нажмите «Участвовать» ещё раз.`
+
+const msgCourseEnrolled = `Вы записаны на курс %s 🎓. В запуски его квизов смогут войти только студенты курса.`
+
+const msgCourseAlreadyEnrolled = `Вы уже записаны на курс %s.`
+
+const msgCourseInviteInvalid = `Ссылка для записи на курс недействительна: курс удалён или ссылка скопирована не полностью.`
+
+const msgCourseNotStudent = `Записаться на курс может только студент. Выберите роль студента в /start и откройте ссылку снова.`
+
+const msgNoEnrolledCourses = `Вы ещё не записаны ни на один курс. Попросите у преподавателя ссылку для записи.`
+
+const msgCourseOnly = `В этот квиз могут войти только студенты его курса. Попросите у преподавателя ссылку для записи на курс.`
+
+const msgCoursesNoRole = `Курсы доступны преподавателям и студентам. Выберите роль в /start.`
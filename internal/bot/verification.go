@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/letsssgooo/quizBot/internal/auth"
+	"github.com/letsssgooo/quizBot/internal/client"
+)
+
+// handleVerifyCommand подтверждает роль преподавателя кодом: /verify <код>.
+func (b *Bot) handleVerifyCommand(ctx context.Context, message *client.Message, args []string) error {
+	if len(args) != 1 {
+		_, err := b.sender.Message(message.Chat.ID, msgVerifyUsage, nil)
+
+		return err
+	}
+
+	return b.verifyLecturer(ctx, message, args[0])
+}
+
+// verifyLecturer выдаёт роль преподавателя по одноразовому коду из /verify или ссылки /start lecturer_<код>.
+func (b *Bot) verifyLecturer(ctx context.Context, message *client.Message, code string) error {
+	role, err := b.roleOf(ctx, message.From.ID)
+	if err != nil {
+		return err
+	}
+
+	// код не тратится впустую, если роль уже подтверждена
+	if role == auth.RoleLecturer {
+		_, err = b.sender.Message(message.Chat.ID, msgAlreadyLecturer, nil)
+
+		return err
+	}
+
+	err = b.auth.VerifyLecturer(b.storage, message.From.ID, code)
+	if errors.Is(err, auth.ErrInvalidLecturerCode) {
+		_, err = b.sender.Message(message.Chat.ID, msgLecturerCodeInvalid, nil)
+
+		return err
+	} else if errors.Is(err, auth.ErrVerificationUnavailable) {
+		_, err = b.sender.Message(message.Chat.ID, msgLecturerCodeUnavailable, nil)
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	b.setUserCommandMenu(message.From.ID, auth.RoleLecturer)
+
+	_, err = b.sender.Message(message.Chat.ID, msgLecturersSuccessfullVerification, nil)
+
+	return err
+}
+
+// handleLecturerCodeCommand выдаёт администратору одноразовый код подтверждения преподавателя
+// вместе со ссылкой, по которой код применяется без ввода.
+func (b *Bot) handleLecturerCodeCommand(message *client.Message) error {
+	code, err := b.auth.IssueLecturerCode(b.storage, message.From.ID)
+	if errors.Is(err, auth.ErrNotAdmin) {
+		_, err = b.sender.Message(message.Chat.ID, msgAdminsOnly, nil)
+
+		return err
+	} else if errors.Is(err, auth.ErrVerificationUnavailable) {
+		_, err = b.sender.Message(message.Chat.ID, msgLecturerCodeUnavailable, nil)
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("https://t.me/%s?start=lecturer_%s", b.botUsername, code.Code)
+	_, err = b.sender.Message(
+		message.Chat.ID,
+		fmt.Sprintf(msgLecturerCode, code.Code, link, code.ExpiresAt.Format("02.01.2006 15:04")),
+		nil,
+	)
+
+	return err
+}
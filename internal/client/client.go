@@ -301,6 +301,100 @@ func (c *HTTPClient) SendDocument(
 	return nil
 }
 
+// SendPoll отправляет в чат chatID неанонимный опрос-викторину с вопросом question и вариантами options.
+// Возвращает указатель на структуру Message с опросом в случае успеха.
+func (c *HTTPClient) SendPoll(
+	chatID int64,
+	question string,
+	options []string,
+	opts *PollOptions,
+) (*Message, error) {
+	pollOptions := make([]map[string]string, 0, len(options))
+	for _, option := range options {
+		pollOptions = append(pollOptions, map[string]string{"text": option})
+	}
+
+	params := map[string]interface{}{
+		"chat_id":      chatID,
+		"question":     question,
+		"options":      pollOptions,
+		"type":         "quiz",
+		"is_anonymous": false, // иначе Telegram не присылает poll_answer
+	}
+
+	if opts != nil {
+		params["correct_option_id"] = opts.CorrectOptionID
+
+		if opts.Explanation != "" {
+			params["explanation"] = opts.Explanation
+		}
+
+		if opts.OpenPeriod > 0 {
+			params["open_period"] = opts.OpenPeriod
+		}
+
+		if opts.ReplyMarkup != nil {
+			params["reply_markup"] = opts.ReplyMarkup
+		}
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutSend)
+	defer cancelFunc()
+
+	rawResp, err := c.doRequest(ctx, "sendPoll", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var message Message
+	if err = json.Unmarshal(rawResp, &message); err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// StopPoll закрывает опрос в сообщении messageID чата chatID.
+// Возвращает nil в случае успеха.
+func (c *HTTPClient) StopPoll(chatID int64, messageID int) error {
+	params := map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": messageID,
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutSend)
+	defer cancelFunc()
+
+	_, err := c.doRequest(ctx, "stopPoll", params)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetMyCommands задаёт меню команд бота. Если scope равен nil, меню задаётся для всех чатов.
+// Возвращает nil в случае успеха.
+func (c *HTTPClient) SetMyCommands(commands []BotCommand, scope *BotCommandScope) error {
+	params := map[string]interface{}{
+		"commands": commands,
+	}
+
+	if scope != nil {
+		params["scope"] = scope
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutSend)
+	defer cancelFunc()
+
+	_, err := c.doRequest(ctx, "setMyCommands", params)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // doRequest выполняет запрос к Telegram API.
 // Возвращает результат запроса в случае успеха.
 func (c *HTTPClient) doRequest(
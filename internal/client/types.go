@@ -12,15 +12,19 @@ type Update struct {
 	UpdateID      int            `json:"update_id"`
 	Message       *Message       `json:"message"`
 	CallbackQuery *CallbackQuery `json:"callback_query"`
+	PollAnswer    *PollAnswer    `json:"poll_answer"`
+	Poll          *Poll          `json:"poll"`
 }
 
 // Message представляет сообщение.
 type Message struct {
-	MessageID int       `json:"message_id"`
-	From      *User     `json:"from"`
-	Chat      *Chat     `json:"chat"`
-	Text      string    `json:"text"`
-	Document  *Document `json:"document"`
+	MessageID      int       `json:"message_id"`
+	From           *User     `json:"from"`
+	Chat           *Chat     `json:"chat"`
+	Text           string    `json:"text"`
+	Document       *Document `json:"document"`
+	Poll           *Poll     `json:"poll"`
+	ReplyToMessage *Message  `json:"reply_to_message"` // сообщение, на которое отвечает это
 }
 
 // User представляет пользователя Telegram.
@@ -53,6 +57,29 @@ type CallbackQuery struct {
 	Data    string   `json:"data"`
 }
 
+// Poll представляет опрос.
+type Poll struct {
+	ID              string       `json:"id"`
+	Question        string       `json:"question"`
+	Options         []PollOption `json:"options"`
+	IsClosed        bool         `json:"is_closed"`
+	Type            string       `json:"type"`
+	CorrectOptionID *int         `json:"correct_option_id"`
+}
+
+// PollOption представляет вариант ответа в опросе.
+type PollOption struct {
+	Text       string `json:"text"`
+	VoterCount int    `json:"voter_count"`
+}
+
+// PollAnswer представляет ответ пользователя в неанонимном опросе.
+type PollAnswer struct {
+	PollID    string `json:"poll_id"`
+	User      *User  `json:"user"`
+	OptionIDs []int  `json:"option_ids"`
+}
+
 // InlineKeyboardMarkup представляет inline клавиатуру.
 type InlineKeyboardMarkup struct {
 	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
@@ -71,6 +98,26 @@ type SendOptions struct {
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 }
 
+// BotCommand представляет команду в меню бота.
+type BotCommand struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+}
+
+// BotCommandScope задаёт, кому показывается меню команд.
+type BotCommandScope struct {
+	Type   string `json:"type"`              // default, all_private_chats, all_group_chats или chat
+	ChatID int64  `json:"chat_id,omitempty"` // только для type = chat
+}
+
+// PollOptions содержит опции отправки опроса-викторины.
+type PollOptions struct {
+	CorrectOptionID int                   // индекс правильного варианта
+	Explanation     string                // пояснение, которое Telegram покажет после ответа
+	OpenPeriod      int                   // время жизни опроса в секундах (5-600), 0 — без ограничения
+	ReplyMarkup     *InlineKeyboardMarkup // клавиатура под опросом
+}
+
 // Client определяет интерфейс Telegram клиента.
 type Client interface {
 	// SendMessage отправляет сообщение.
@@ -96,6 +143,15 @@ type Client interface {
 
 	// SendDocument отправляет файл как документ.
 	SendDocument(chatID int64, fileName string, data []byte) error
+
+	// SendPoll отправляет опрос-викторину.
+	SendPoll(chatID int64, question string, options []string, opts *PollOptions) (*Message, error)
+
+	// StopPoll закрывает опрос.
+	StopPoll(chatID int64, messageID int) error
+
+	// SetMyCommands задаёт меню команд бота для scope.
+	SetMyCommands(commands []BotCommand, scope *BotCommandScope) error
 }
 
 // Таймауты
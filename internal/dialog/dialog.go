@@ -0,0 +1,324 @@
+// Package dialog реализует конечный автомат многошаговых диалогов с пользователями:
+// в каждый момент пользователь находится не больше чем в одном диалоге, у каждого
+// состояния есть таймаут, а состояния сохраняются в хранилище и переживают перезапуск бота.
+package dialog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+	"github.com/letsssgooo/quizBot/internal/storage"
+)
+
+// State — шаг диалога.
+type State string
+
+// Состояния диалога.
+const (
+	StateIdle                 State = ""                      // диалога нет
+	StateAwaitingRegistration State = "awaiting_registration" // ждём ФИО и группу студента
+	StateAwaitingAnswer       State = "awaiting_answer"       // сообщения студента — ответы в запуске
+	StateAwaitingRunFields    State = "awaiting_run_fields"   // ждём поля регистрации, которых требует квиз
+	StateEditingQuiz          State = "editing_quiz"          // преподаватель работает с сохранённым квизом
+	StateConfirmingDelete     State = "confirming_delete"     // ждём подтверждения удаления
+	StateComparingRuns        State = "comparing_runs"        // ждём выбора второго запуска для сравнения
+)
+
+// Ключи данных диалога.
+const (
+	KeyRunID  = "run_id"
+	KeyQuizID = "quiz_id"
+	KeyField  = "field" // поле регистрации, которое сейчас заполняет студент
+
+	// KeyFieldValuePrefix — префикс ключей с уже введёнными значениями полей регистрации
+	KeyFieldValuePrefix = "field_value:"
+)
+
+// Timeouts — время жизни каждого состояния. По истечении диалог сбрасывается.
+var Timeouts = map[State]time.Duration{
+	StateAwaitingRegistration: 30 * time.Minute,
+	StateAwaitingAnswer:       3 * time.Hour, // ограничивает запуск, который так и не закончился
+	StateAwaitingRunFields:    15 * time.Minute,
+	StateEditingQuiz:          30 * time.Minute,
+	StateConfirmingDelete:     5 * time.Minute,
+	StateComparingRuns:        10 * time.Minute,
+}
+
+// transitions — разрешённые переходы. Переход в StateIdle разрешён всегда.
+var transitions = map[State][]State{
+	StateIdle: {
+		StateAwaitingRegistration,
+		StateAwaitingAnswer,
+		StateAwaitingRunFields,
+		StateEditingQuiz,
+		StateConfirmingDelete,
+		StateComparingRuns,
+	},
+	StateAwaitingRegistration: {StateAwaitingRegistration, StateAwaitingAnswer, StateAwaitingRunFields},
+	StateAwaitingRunFields:    {StateAwaitingRunFields, StateAwaitingAnswer},
+	StateAwaitingAnswer:       {StateAwaitingAnswer},
+	StateEditingQuiz:          {StateEditingQuiz, StateConfirmingDelete, StateComparingRuns},
+	StateConfirmingDelete:     {StateEditingQuiz},
+	StateComparingRuns:        {StateComparingRuns, StateEditingQuiz},
+}
+
+// Ошибки диалогов.
+var (
+	ErrInvalidTransition = errors.New("invalid dialog transition")
+	ErrUnknownState      = errors.New("unknown dialog state")
+)
+
+// timeoutStorage — таймаут одного запроса к хранилищу.
+const timeoutStorage = time.Second
+
+// Dialog — текущий диалог пользователя.
+type Dialog struct {
+	UserID    int64
+	State     State
+	Data      map[string]string
+	ExpiresAt time.Time
+	UpdatedAt time.Time
+}
+
+// Manager хранит диалоги пользователей в памяти и, если задано хранилище, дублирует их туда.
+type Manager struct {
+	store   storage.DialogStorage
+	dialogs map[int64]*Dialog
+	mu      sync.Mutex
+}
+
+// NewManager создаёт менеджер диалогов. store может быть nil — тогда диалоги живут только в памяти.
+func NewManager(store storage.DialogStorage) *Manager {
+	return &Manager{
+		store:   store,
+		dialogs: make(map[int64]*Dialog),
+	}
+}
+
+// Load восстанавливает из хранилища диалоги, которые ещё не истекли.
+func (m *Manager) Load(ctx context.Context, now time.Time) error {
+	if m.store == nil {
+		return nil
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutStorage)
+	defer cancelFunc()
+
+	dialogModels, err := m.store.ListDialogs(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, model := range dialogModels {
+		dialog, err := fromModel(model)
+		if err != nil {
+			return err
+		}
+
+		m.dialogs[dialog.UserID] = dialog
+	}
+
+	return nil
+}
+
+// Current возвращает текущий диалог пользователя.
+// Истёкший диалог сбрасывается и возвращается с expired = true, чтобы пользователю можно было об этом сообщить.
+func (m *Manager) Current(ctx context.Context, userID int64, now time.Time) (dialog Dialog, expired bool, err error) {
+	m.mu.Lock()
+	current, ok := m.dialogs[userID]
+	if !ok {
+		m.mu.Unlock()
+
+		return Dialog{UserID: userID}, false, nil
+	}
+
+	dialog = current.copy()
+	expired = !now.Before(current.ExpiresAt)
+
+	if expired {
+		delete(m.dialogs, userID)
+	}
+	m.mu.Unlock()
+
+	if expired {
+		return dialog, true, m.deleteStored(ctx, userID)
+	}
+
+	return dialog, false, nil
+}
+
+// Transition переводит пользователя в состояние to с данными data.
+// Возвращает ErrInvalidTransition, если из текущего состояния в to перейти нельзя.
+func (m *Manager) Transition(
+	ctx context.Context,
+	userID int64,
+	to State,
+	data map[string]string,
+	now time.Time,
+) error {
+	if to == StateIdle {
+		return m.Reset(ctx, userID)
+	}
+
+	timeout, ok := Timeouts[to]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownState, to)
+	}
+
+	m.mu.Lock()
+
+	from := StateIdle
+	if current, ok := m.dialogs[userID]; ok && now.Before(current.ExpiresAt) {
+		from = current.State
+	}
+
+	if !slices.Contains(transitions[from], to) {
+		m.mu.Unlock()
+
+		return fmt.Errorf("%w: %q -> %q", ErrInvalidTransition, from, to)
+	}
+
+	dialog := &Dialog{
+		UserID:    userID,
+		State:     to,
+		Data:      maps.Clone(data),
+		ExpiresAt: now.Add(timeout),
+		UpdatedAt: now,
+	}
+
+	m.dialogs[userID] = dialog
+	stored := dialog.copy()
+	m.mu.Unlock()
+
+	return m.save(ctx, &stored)
+}
+
+// Reset завершает диалог пользователя.
+func (m *Manager) Reset(ctx context.Context, userID int64) error {
+	m.mu.Lock()
+	_, ok := m.dialogs[userID]
+	delete(m.dialogs, userID)
+	m.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return m.deleteStored(ctx, userID)
+}
+
+// ResetState завершает диалог пользователя, только если он находится в состоянии state.
+func (m *Manager) ResetState(ctx context.Context, userID int64, state State) error {
+	m.mu.Lock()
+	current, ok := m.dialogs[userID]
+	m.mu.Unlock()
+
+	if !ok || current.State != state {
+		return nil
+	}
+
+	return m.Reset(ctx, userID)
+}
+
+// Expire сбрасывает все истёкшие к now диалоги и возвращает их.
+func (m *Manager) Expire(ctx context.Context, now time.Time) ([]Dialog, error) {
+	var expired []Dialog
+
+	m.mu.Lock()
+	for userID, dialog := range m.dialogs {
+		if !now.Before(dialog.ExpiresAt) {
+			expired = append(expired, dialog.copy())
+			delete(m.dialogs, userID)
+		}
+	}
+	m.mu.Unlock()
+
+	var errs []error
+
+	for _, dialog := range expired {
+		errs = append(errs, m.deleteStored(ctx, dialog.UserID))
+	}
+
+	return expired, errors.Join(errs...)
+}
+
+// save сохраняет диалог в хранилище, если оно задано.
+func (m *Manager) save(ctx context.Context, dialog *Dialog) error {
+	if m.store == nil {
+		return nil
+	}
+
+	model, err := dialog.toModel()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutStorage)
+	defer cancelFunc()
+
+	return m.store.SaveDialog(ctx, model)
+}
+
+// deleteStored удаляет диалог из хранилища, если оно задано.
+func (m *Manager) deleteStored(ctx context.Context, userID int64) error {
+	if m.store == nil {
+		return nil
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, timeoutStorage)
+	defer cancelFunc()
+
+	return m.store.DeleteDialog(ctx, userID)
+}
+
+// copy возвращает копию диалога, которую можно отдавать наружу без мьютекса.
+func (d *Dialog) copy() Dialog {
+	dialog := *d
+	dialog.Data = maps.Clone(d.Data)
+
+	return dialog
+}
+
+// toModel преобразует диалог в модель для хранилища.
+func (d *Dialog) toModel() (*models.DialogModel, error) {
+	data, err := json.Marshal(d.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DialogModel{
+		TelegramID: d.UserID,
+		State:      string(d.State),
+		Data:       data,
+		ExpiresAt:  d.ExpiresAt,
+		UpdatedAt:  d.UpdatedAt,
+	}, nil
+}
+
+// fromModel восстанавливает диалог из модели хранилища.
+func fromModel(model *models.DialogModel) (*Dialog, error) {
+	dialog := &Dialog{
+		UserID:    model.TelegramID,
+		State:     State(model.State),
+		ExpiresAt: model.ExpiresAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+
+	if len(model.Data) > 0 {
+		if err := json.Unmarshal(model.Data, &dialog.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	return dialog, nil
+}
@@ -0,0 +1,189 @@
+package dialog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+// memoryStore — хранилище диалогов в памяти для тестов.
+type memoryStore struct {
+	dialogs map[int64]*models.DialogModel
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{dialogs: make(map[int64]*models.DialogModel)}
+}
+
+func (s *memoryStore) SaveDialog(_ context.Context, dialog *models.DialogModel) error {
+	s.dialogs[dialog.TelegramID] = dialog
+
+	return nil
+}
+
+func (s *memoryStore) DeleteDialog(_ context.Context, telegramID int64) error {
+	delete(s.dialogs, telegramID)
+
+	return nil
+}
+
+func (s *memoryStore) ListDialogs(_ context.Context, now time.Time) ([]*models.DialogModel, error) {
+	var dialogs []*models.DialogModel
+
+	for _, dialog := range s.dialogs {
+		if dialog.ExpiresAt.After(now) {
+			dialogs = append(dialogs, dialog)
+		}
+	}
+
+	return dialogs, nil
+}
+
+func TestTransition(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	manager := NewManager(nil)
+
+	dialog, expired, err := manager.Current(ctx, 1, now)
+	require.NoError(t, err)
+	assert.False(t, expired)
+	assert.Equal(t, StateIdle, dialog.State)
+
+	require.NoError(t, manager.Transition(ctx, 1, StateAwaitingAnswer, map[string]string{KeyRunID: "run"}, now))
+
+	dialog, _, err = manager.Current(ctx, 1, now)
+	require.NoError(t, err)
+	assert.Equal(t, StateAwaitingAnswer, dialog.State)
+	assert.Equal(t, "run", dialog.Data[KeyRunID])
+	assert.Equal(t, now.Add(Timeouts[StateAwaitingAnswer]), dialog.ExpiresAt)
+
+	// во время квиза нельзя начать удаление квиза
+	err = manager.Transition(ctx, 1, StateConfirmingDelete, nil, now)
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+
+	assert.ErrorIs(t, manager.Transition(ctx, 1, State("unknown"), nil, now), ErrUnknownState)
+
+	// в ожидание ответа на новый запуск перейти можно
+	require.NoError(t, manager.Transition(ctx, 1, StateAwaitingAnswer, map[string]string{KeyRunID: "next"}, now))
+
+	require.NoError(t, manager.Transition(ctx, 1, StateIdle, nil, now))
+
+	dialog, _, err = manager.Current(ctx, 1, now)
+	require.NoError(t, err)
+	assert.Equal(t, StateIdle, dialog.State)
+}
+
+func TestTransition_RunFields(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	manager := NewManager(nil)
+
+	// регистрацию можно прервать входом в квиз, который спрашивает свои поля
+	require.NoError(t, manager.Transition(ctx, 1, StateAwaitingRegistration, nil, now))
+	require.NoError(t, manager.Transition(ctx, 1, StateAwaitingRunFields, map[string]string{KeyField: "name"}, now))
+	require.NoError(t, manager.Transition(ctx, 1, StateAwaitingRunFields, map[string]string{KeyField: "group"}, now))
+
+	err := manager.Transition(ctx, 1, StateEditingQuiz, nil, now)
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+
+	require.NoError(t, manager.Transition(ctx, 1, StateAwaitingAnswer, map[string]string{KeyRunID: "run"}, now))
+
+	// во время квиза поля другого квиза не спрашиваются
+	err = manager.Transition(ctx, 1, StateAwaitingRunFields, nil, now)
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+}
+
+func TestCurrent_ReturnsCopy(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	manager := NewManager(nil)
+
+	data := map[string]string{KeyQuizID: "quiz"}
+	require.NoError(t, manager.Transition(ctx, 1, StateEditingQuiz, data, now))
+
+	data[KeyQuizID] = "changed"
+
+	dialog, _, err := manager.Current(ctx, 1, now)
+	require.NoError(t, err)
+
+	dialog.Data[KeyQuizID] = "changed"
+
+	dialog, _, err = manager.Current(ctx, 1, now)
+	require.NoError(t, err)
+	assert.Equal(t, "quiz", dialog.Data[KeyQuizID])
+}
+
+func TestTimeout(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := newMemoryStore()
+	manager := NewManager(store)
+
+	require.NoError(t, manager.Transition(ctx, 1, StateAwaitingRegistration, nil, now))
+	require.NoError(t, manager.Transition(ctx, 2, StateEditingQuiz, nil, now))
+	require.NoError(t, manager.Transition(ctx, 2, StateConfirmingDelete, nil, now))
+
+	later := now.Add(Timeouts[StateConfirmingDelete])
+
+	// истёкший диалог сбрасывается при обращении
+	dialog, expired, err := manager.Current(ctx, 2, later)
+	require.NoError(t, err)
+	assert.True(t, expired)
+	assert.Equal(t, StateConfirmingDelete, dialog.State)
+	assert.NotContains(t, store.dialogs, int64(2))
+
+	dialog, expired, err = manager.Current(ctx, 2, later)
+	require.NoError(t, err)
+	assert.False(t, expired)
+	assert.Equal(t, StateIdle, dialog.State)
+
+	expiredDialogs, err := manager.Expire(ctx, now.Add(Timeouts[StateAwaitingRegistration]))
+	require.NoError(t, err)
+	require.Len(t, expiredDialogs, 1)
+	assert.Equal(t, int64(1), expiredDialogs[0].UserID)
+	assert.Empty(t, store.dialogs)
+}
+
+func TestResetState(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	manager := NewManager(nil)
+
+	require.NoError(t, manager.Transition(ctx, 1, StateAwaitingRegistration, nil, now))
+
+	// сбрасывается только указанное состояние
+	require.NoError(t, manager.ResetState(ctx, 1, StateAwaitingAnswer))
+
+	dialog, _, err := manager.Current(ctx, 1, now)
+	require.NoError(t, err)
+	assert.Equal(t, StateAwaitingRegistration, dialog.State)
+
+	require.NoError(t, manager.ResetState(ctx, 1, StateAwaitingRegistration))
+
+	dialog, _, err = manager.Current(ctx, 1, now)
+	require.NoError(t, err)
+	assert.Equal(t, StateIdle, dialog.State)
+}
+
+func TestLoad_SurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := newMemoryStore()
+
+	manager := NewManager(store)
+	require.NoError(t, manager.Transition(ctx, 1, StateAwaitingAnswer, map[string]string{KeyRunID: "run"}, now))
+
+	restarted := NewManager(store)
+	require.NoError(t, restarted.Load(ctx, now.Add(time.Minute)))
+
+	dialog, expired, err := restarted.Current(ctx, 1, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, expired)
+	assert.Equal(t, StateAwaitingAnswer, dialog.State)
+	assert.Equal(t, "run", dialog.Data[KeyRunID])
+}
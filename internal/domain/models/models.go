@@ -12,6 +12,7 @@ import (
 type UserModel struct {
 	ID         int
 	TelegramID int64
+	Username   string
 	FullName   string
 	Role       string
 	Group      string
@@ -38,3 +39,211 @@ type StatisticModel struct {
 	Points    int
 	MaxPoints int
 }
+
+// PracticeModel определяет модель для таблицы с результатами тренировок
+type PracticeModel struct {
+	ID             string
+	TelegramID     int64
+	QuizID         string
+	QuizTitle      string
+	Timed          bool
+	Score          int
+	MaxScore       int
+	CorrectCount   int
+	QuestionsCount int
+	StartedAt      time.Time
+	FinishedAt     time.Time
+}
+
+// ReviewCardModel определяет модель для таблицы карточек повторения
+type ReviewCardModel struct {
+	TelegramID  int64
+	OwnerID     int64
+	QuizID      string
+	QuizTitle   string
+	QuestionIdx int
+	Question    []byte // вопрос в JSON
+	EaseFactor  float64
+	Interval    int
+	Repetitions int
+	Lapses      int
+	DueAt       time.Time
+}
+
+// ForgottenQuestionModel определяет модель для статистики самых забываемых вопросов
+type ForgottenQuestionModel struct {
+	QuizTitle    string
+	QuestionIdx  int
+	QuestionText string
+	Students     int
+	Lapses       int
+}
+
+// DialogModel определяет модель для таблицы состояний диалогов пользователей
+type DialogModel struct {
+	TelegramID int64
+	State      string
+	Data       []byte // данные шага диалога в JSON
+	ExpiresAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// QuizModel определяет модель для таблицы сохранённых квизов
+type QuizModel struct {
+	ID             string
+	OwnerID        int64
+	Title          string
+	File           []byte // исходный JSON файл
+	QuestionsCount int
+	RunsCount      int    // заполняется только в списке квизов
+	SharedRole     string // роль пользователя в чужом квизе, заполняется только в списке открытых ему квизов
+	Deleted        bool   // квиз удалён владельцем, заполняется только в списке квизов курса
+	CreatedAt      time.Time
+}
+
+// RunModel определяет модель для таблицы запусков квизов
+type RunModel struct {
+	ID                string
+	QuizID            string
+	OwnerID           int64
+	Status            string
+	ParticipantsCount int
+	StartedAt         time.Time
+	FinishedAt        *time.Time // nil, пока запуск не завершён
+}
+
+// RunResultsModel определяет итоги завершённого запуска
+type RunResultsModel struct {
+	RunID        string
+	CSV          []byte
+	Participants []*RunParticipantModel
+	Questions    []*RunQuestionModel
+	Answers      []*RunAnswerModel
+}
+
+// RunArchiveModel определяет завершённый запуск, выгруженный из памяти бота
+type RunArchiveModel struct {
+	RunID     string
+	QuizID    string
+	StarterID int64  // кто провёл запуск, ID его личного чата
+	Data      []byte // состояние запуска в движке, см. engine.ArchiveRun
+}
+
+// RunParticipantModel определяет модель для таблицы результатов участников запуска
+type RunParticipantModel struct {
+	TelegramID    int64
+	Name          string
+	Score         int
+	Rank          int
+	CorrectCount  int
+	AnsweredCount int
+	MaxScore      int
+}
+
+// RunQuestionModel определяет модель для таблицы ответов на вопросы запуска
+type RunQuestionModel struct {
+	QuestionIdx  int
+	QuestionText string
+	Answered     int
+	Correct      int
+	Voided       bool
+}
+
+// RunAnswerModel определяет модель для таблицы ответов участника на вопросы запуска
+type RunAnswerModel struct {
+	TelegramID  int64
+	QuestionIdx int
+	Topics      []string
+	Answered    bool
+	Correct     bool
+}
+
+// StudentRunModel определяет результат студента в одном завершённом запуске
+type StudentRunModel struct {
+	RunID        string
+	QuizTitle    string
+	StartedAt    time.Time
+	Score        int
+	MaxScore     int
+	Rank         int
+	Participants int
+}
+
+// TopicAccuracyModel определяет ответы студента на вопросы одной темы
+type TopicAccuracyModel struct {
+	Topic    string
+	Answered int // вопросов темы в пройденных запусках, включая пропущенные
+	Correct  int
+}
+
+// StudentModel определяет студента, участвовавшего в запусках преподавателя
+type StudentModel struct {
+	TelegramID int64
+	Name       string
+	FullName   string // ФИО из регистрации, пустое, если студент не зарегистрирован
+	Group      string
+}
+
+// QuizShareModel определяет доступ пользователя к чужому квизу
+type QuizShareModel struct {
+	QuizID     string
+	TelegramID int64
+	Username   string // заполняется только в списке доступа
+	FullName   string // заполняется только в списке доступа
+	Role       string
+	CreatedAt  time.Time
+}
+
+// QuizInviteModel определяет одноразовую ссылку-приглашение к квизу
+type QuizInviteModel struct {
+	Code      string
+	QuizID    string
+	Role      string
+	CreatedBy int64
+	ExpiresAt time.Time
+}
+
+// LecturerCodeModel определяет одноразовый код подтверждения роли преподавателя
+type LecturerCodeModel struct {
+	Code      string
+	CreatedBy int64
+	ExpiresAt time.Time
+}
+
+// BanModel определяет блокировку пользователя администратором
+type BanModel struct {
+	TelegramID int64
+	Username   string
+	FullName   string
+	Reason     string
+	BannedBy   int64
+	CreatedAt  time.Time
+}
+
+// BotStatsModel определяет общую статистику бота для администратора
+type BotStatsModel struct {
+	UsersByRole  map[string]int // пустая строка — пользователи без роли
+	BannedUsers  int
+	Quizzes      int
+	Runs         int
+	FinishedRuns int
+}
+
+// CourseModel определяет курс преподавателя
+type CourseModel struct {
+	ID            int64
+	OwnerID       int64
+	Title         string
+	InviteCode    string // код ссылки для записи студентов
+	CreatedAt     time.Time
+	StudentsCount int // заполняется только в списках курсов
+	QuizzesCount  int // заполняется только в списках курсов
+}
+
+// CourseResultModel определяет результаты студента курса по одному квизу курса
+type CourseResultModel struct {
+	TelegramID int64
+	QuizID     string
+	BestScore  int
+	Attempts   int
+}
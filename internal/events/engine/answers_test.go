@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitAnswer_StaleAndRepeated(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Buttons",
+		"settings": {"time_per_question": 5, "answer_mode": "buttons"},
+		"questions": [
+			{"text": "Q1", "options": ["A", "B"], "correct": 0},
+			{"text": "Q2", "options": ["A", "B"], "correct": 1}
+		]
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, AnswerModeButtons, quiz.Settings.AnswerMode)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 1}))
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 2}))
+
+	events, err := engine.StartQuiz(ctx, run.ID)
+	require.NoError(t, err)
+
+	defer drainEvents(events)
+
+	<-events
+
+	// ошибки ввода больше не останавливают квиз
+	assert.ErrorIs(t, engine.SubmitAnswerByLetter(ctx, run.ID, 1, "Z"), ErrConvertLetterToIndex)
+	assert.ErrorIs(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, 7), ErrInvalidAnswerIndex)
+	assert.ErrorIs(t, engine.SubmitAnswer(ctx, run.ID, 1, 1, 0), ErrStaleQuestion)
+
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, 0))
+	assert.ErrorIs(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, 1), ErrAlreadyAnswered)
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 2, 0, 1))
+
+	event := <-events
+	require.Equal(t, EventTypeQuestion, event.Type)
+	assert.Equal(t, 1, event.QuestionIdx)
+
+	assert.ErrorIs(t, engine.SubmitAnswer(ctx, run.ID, 2, 0, 0), ErrStaleQuestion)
+
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 1, 1))
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 2, 1, 1))
+
+	event = <-events
+	require.Equal(t, EventTypeFinished, event.Type)
+
+	results, err := engine.GetResults(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, results.Leaderboard[0].Score)
+	assert.Equal(t, 1, results.Leaderboard[1].Score)
+}
+
+func TestLoadQuiz_UnknownAnswerMode(t *testing.T) {
+	_, err := NewEngine().LoadQuiz([]byte(`{
+		"title": "Bad mode",
+		"settings": {"time_per_question": 5, "answer_mode": "telepathy"},
+		"questions": [{"text": "Q1", "options": ["A", "B"], "correct": 0}]
+	}`))
+	assert.Error(t, err)
+}
+
+func TestLoadQuiz_PollMode(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Polls",
+		"settings": {"time_per_question": 30, "answer_mode": "poll"},
+		"questions": [
+			{"text": "Q1", "options": ["A", "B"], "correct": 0},
+			{"text": "Open", "type": "text"}
+		]
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, AnswerModePoll, quiz.Settings.AnswerMode)
+
+	_, err = engine.LoadQuiz([]byte(`{
+		"title": "Too many options",
+		"settings": {"time_per_question": 30, "answer_mode": "poll"},
+		"questions": [
+			{"text": "Q1", "options": ["1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"], "correct": 0}
+		]
+	}`))
+	assert.Error(t, err)
+
+	// в текстовом режиме ограничения опросов не действуют
+	_, err = engine.LoadQuiz([]byte(`{
+		"title": "Many options",
+		"settings": {"time_per_question": 30},
+		"questions": [
+			{"text": "Q1", "options": ["1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"], "correct": 0}
+		]
+	}`))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,32 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+)
+
+// CancelRun отменяет запуск в лобби или во время квиза. Запуск переходит в статус RunStatusCancelled,
+// горутина квиза останавливается, а канал событий закрывается без события EventTypeFinished.
+func (e *Engine) CancelRun(runID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	activeQuizRun, ok := e.activeQuizzesRun[runID]
+	if !ok {
+		return fmt.Errorf("quiz with runID: %s is not found", runID)
+	}
+
+	if activeQuizRun.Status != RunStatusLobby && activeQuizRun.Status != RunStatusRunning {
+		return ErrNotCancellable
+	}
+
+	activeQuizRun.Status = RunStatusCancelled
+	activeQuizRun.FinishedAt = time.Now()
+
+	// закрытый канал будит горутину квиза, где бы она ни ждала: в вопросе или на перерыве
+	if quizErrChan, ok := e.quizErrChan[runID]; ok {
+		close(quizErrChan)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelRun_Lobby(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Cancel",
+		"settings": {"time_per_question": 5},
+		"questions": [{"text": "Q1", "options": ["A", "B"], "correct": 0}]
+	}`))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.CancelRun(run.ID))
+	assert.Equal(t, RunStatusCancelled, run.Status)
+
+	assert.ErrorIs(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 1}), ErrRunCancelled)
+	assert.ErrorIs(t, engine.CancelRun(run.ID), ErrNotCancellable)
+
+	_, err = engine.StartQuiz(ctx, run.ID)
+	assert.ErrorIs(t, err, ErrNoRunningStatus)
+}
+
+func TestCancelRun_Running(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Cancel",
+		"settings": {"time_per_question": 30},
+		"questions": [
+			{"text": "Q1", "options": ["A", "B"], "correct": 0},
+			{"text": "Q2", "options": ["A", "B"], "correct": 0}
+		]
+	}`))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 1}))
+
+	events, err := engine.StartQuiz(ctx, run.ID)
+	require.NoError(t, err)
+
+	event := <-events
+	assert.Equal(t, EventTypeQuestion, event.Type)
+
+	require.NoError(t, engine.CancelRun(run.ID))
+
+	// канал закрывается сразу, без оставшихся вопросов и события окончания
+	select {
+	case event, ok := <-events:
+		assert.False(t, ok, "unexpected event %v", event.Type)
+	case <-time.After(time.Second):
+		t.Fatal("events channel is not closed after cancel")
+	}
+
+	assert.Equal(t, RunStatusCancelled, run.Status)
+	assert.Error(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, 0))
+
+	_, err = engine.GetResults(run.ID)
+	assert.Error(t, err)
+}
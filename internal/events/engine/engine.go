@@ -6,9 +6,12 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"math/rand"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,12 +20,14 @@ import (
 
 // Engine реализует QuizEngine.
 type Engine struct {
-	quizzes               map[string]*Quiz    // ключ - quizID
-	activeQuizzesRun      map[string]*QuizRun // ключ - runID
+	quizzes               map[string]*Quiz      // ключ - quizID
+	activeQuizzesRun      map[string]*QuizRun   // ключ - runID
+	runIDToQuestions      map[string][]Question // копия вопросов квиза для запуска (с учетом перемешивания)
 	runIDToEvents         map[string]chan QuizEvent
 	runIDToQuestionNumber map[string]int
-	startTimeOfQuestion   map[int]time.Time
-	quizErrChan           map[string]chan struct{} // для выхода из горутины при ошибке
+	startTimeOfQuestion   map[string]map[int]time.Time // ключ - runID, затем номер вопроса
+	quizErrChan           map[string]chan struct{}     // для выхода из горутины при ошибке
+	runIDToContinue       map[string]chan struct{}     // сигнал преподавателя о продолжении после перерыва
 	mu                    sync.RWMutex
 }
 
@@ -31,10 +36,12 @@ func NewEngine() *Engine {
 	return &Engine{
 		quizzes:               make(map[string]*Quiz),
 		activeQuizzesRun:      make(map[string]*QuizRun),
+		runIDToQuestions:      make(map[string][]Question),
 		runIDToEvents:         make(map[string]chan QuizEvent),
 		runIDToQuestionNumber: make(map[string]int),
-		startTimeOfQuestion:   make(map[int]time.Time),
+		startTimeOfQuestion:   make(map[string]map[int]time.Time),
 		quizErrChan:           make(map[string]chan struct{}),
+		runIDToContinue:       make(map[string]chan struct{}),
 	}
 }
 
@@ -46,6 +53,10 @@ func (e *Engine) LoadQuiz(data []byte) (*Quiz, error) {
 		return nil, err
 	}
 
+	if err := flattenSections(quiz); err != nil {
+		return nil, fmt.Errorf("cannot load events, %w", err)
+	}
+
 	if err := isCorrectQuiz(quiz); err != nil {
 		return nil, fmt.Errorf("cannot load events, %w", err)
 	}
@@ -82,10 +93,12 @@ func (e *Engine) StartRun(ctx context.Context, quiz *Quiz) (*QuizRun, error) {
 		Participants: make(map[int64]*Participant),
 		Answers:      make(map[int64][]Answer),
 		StartedAt:    time.Now(),
+		HintsUsed:    make(map[int64]map[int]int),
 	}
 
 	e.mu.Lock()
 	e.activeQuizzesRun[runID] = activeQuizRun
+	e.runIDToQuestions[runID] = copyQuestions(quiz.Questions)
 	e.mu.Unlock()
 
 	return activeQuizRun, nil
@@ -111,6 +124,10 @@ func (e *Engine) JoinRun(ctx context.Context, runID string, participant *Partici
 		return ErrNoRunLobby
 	}
 
+	if activeQuizRun.Status == RunStatusCancelled {
+		return ErrRunCancelled
+	}
+
 	quiz := e.quizzes[activeQuizRun.QuizID]
 	if quiz.Settings.MaxParticipants != 0 &&
 		len(activeQuizRun.Participants) >= quiz.Settings.MaxParticipants {
@@ -121,6 +138,12 @@ func (e *Engine) JoinRun(ctx context.Context, runID string, participant *Partici
 		return ErrRepeatedJoin
 	}
 
+	for _, field := range quiz.Settings.Registration {
+		if participant.RegData[field] == "" {
+			return fmt.Errorf("%w: %s", ErrRegistrationRequired, field)
+		}
+	}
+
 	activeQuizRun.Participants[participant.TelegramID] = participant
 	activeQuizRun.Answers[participant.TelegramID] = make([]Answer, 0, len(quiz.Questions))
 	participant.JoinedAt = time.Now()
@@ -160,52 +183,90 @@ func (e *Engine) StartQuiz(ctx context.Context, runID string) (<-chan QuizEvent,
 	activeQuizRun.Status = RunStatusRunning
 
 	quiz := e.quizzes[activeQuizRun.QuizID]
+	questions := e.runIDToQuestions[runID]
 
 	e.runIDToEvents[runID] = make(chan QuizEvent, MaxCountOfEvents)
 	quizEvents := e.runIDToEvents[runID]
-	e.quizErrChan[runID] = make(chan struct{}, 1)
+	quizErrChan := make(chan struct{}, 1)
+	e.quizErrChan[runID] = quizErrChan
+	e.startTimeOfQuestion[runID] = make(map[int]time.Time, len(questions))
 
 	e.mu.Unlock()
 
 	go func() {
 		defer close(quizEvents)
 
-		for i, question := range quiz.Questions {
+		for i := range questions {
+			question := &questions[i]
+
 			select {
 			case <-ctx.Done():
 				return
+			case <-quizErrChan:
+				return
 			default:
 				e.mu.Lock()
 
 				e.runIDToQuestionNumber[runID] = i
 
-				e.startTimeOfQuestion[i] = time.Now()
+				e.startTimeOfQuestion[runID][i] = time.Now()
 
 				e.mu.Unlock()
 
-				timePerQuestion := quiz.Settings.TimePerQuestion
-				if question.Time != 0 {
-					timePerQuestion = question.Time
-				}
+				timePerQuestion := questionTime(quiz, question)
 
 				questionEvent := QuizEvent{
 					Type:        EventTypeQuestion,
 					QuestionIdx: i,
-					Question:    &question,
+					Question:    question,
 					TimeLeft:    time.Duration(timePerQuestion) * time.Second,
 				}
 				quizEvents <- questionEvent
 
-				ok = e.waitEndOfQuestion(ctx, activeQuizRun, i, timePerQuestion, quiz, quizEvents, e.quizErrChan[runID])
+				ok = e.waitEndOfQuestion(ctx, activeQuizRun, i, timePerQuestion, question, quizEvents, quizErrChan)
 				if !ok {
 					return
 				}
+
+				if question.Section == "" || (i+1 < len(questions) && questions[i+1].Section == question.Section) {
+					continue
+				}
+
+				lastSection := i+1 == len(questions)
+
+				var continueChan chan struct{}
+				if !lastSection {
+					continueChan = e.openIntermission(runID)
+				}
+
+				quizEvents <- QuizEvent{
+					Type:        EventTypeSectionFinished,
+					QuestionIdx: i,
+					Section:     question.Section,
+					TimeLeft:    time.Duration(quiz.Settings.Intermission) * time.Second,
+					LastSection: lastSection,
+				}
+
+				if !lastSection && !e.waitIntermission(ctx, runID, continueChan, quiz.Settings.Intermission) {
+					return
+				}
 			}
 		}
 
 		e.mu.Lock()
 
+		// запуск отменили, пока шёл последний вопрос
+		if activeQuizRun.Status == RunStatusCancelled {
+			e.mu.Unlock()
+
+			return
+		}
+
 		activeQuizRun.Status = RunStatusFinished
+		if countPending(activeQuizRun) > 0 {
+			activeQuizRun.Status = RunStatusGrading
+		}
+
 		activeQuizRun.FinishedAt = time.Now()
 
 		e.mu.Unlock()
@@ -229,23 +290,19 @@ func (e *Engine) ShuffleAnswers(event *QuizEvent) error {
 
 	randGen := rand.New(rand.NewSource(time.Now().UnixNano()))
 
-	correctOption := event.Question.Options[event.Question.Correct]
+	options := slices.Clone(event.Question.Options)
 	randGen.Shuffle(len(event.Question.Options), func(i, j int) {
 		event.Question.Options[i], event.Question.Options[j] = event.Question.Options[j], event.Question.Options[i]
 	})
 
-	for j, option := range event.Question.Options {
-		if option == correctOption {
-			event.Question.Correct = j
-			break
-		}
-	}
+	setCorrect(event.Question, toRunOrder(event.Question.CorrectOptions(), options, event.Question.Options))
 
 	return nil
 }
 
 // SubmitAnswer регистрирует ответ участника.
 func (e *Engine) SubmitAnswer(
+	_ context.Context,
 	runID string,
 	participantID int64,
 	questionIdx int,
@@ -254,49 +311,49 @@ func (e *Engine) SubmitAnswer(
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	quizErrChan := e.quizErrChan[runID]
-
 	activeQuizRun, ok := e.activeQuizzesRun[runID]
 	if !ok || activeQuizRun.Status != RunStatusRunning {
-		close(quizErrChan)
 		return fmt.Errorf("quiz with runID: %s not running", runID)
 	}
 
-	quiz := e.quizzes[activeQuizRun.QuizID]
+	questions := e.runIDToQuestions[runID]
 
-	questionsLength := len(quiz.Questions)
+	questionsLength := len(questions)
 	if questionIdx < 0 || questionIdx >= questionsLength {
-		close(quizErrChan)
 		return ErrInvalidQuestionIndex
 	}
 
-	optionsLength := len(quiz.Questions[questionIdx].Options)
+	if questions[questionIdx].IsOpen() {
+		return ErrOpenQuestion
+	}
+
+	optionsLength := len(questions[questionIdx].Options)
 	if answerIdx < 0 || answerIdx >= optionsLength {
-		close(quizErrChan)
 		return ErrInvalidAnswerIndex
 	}
 
 	if _, ok = activeQuizRun.Participants[participantID]; !ok {
-		close(quizErrChan)
 		return fmt.Errorf("no such participant with id %d", participantID)
 	}
 
-	isCorrect := false
-
-	question := quiz.Questions[questionIdx]
-	if question.Correct == answerIdx {
-		isCorrect = true
+	if err := e.checkAnswerable(activeQuizRun, participantID, questionIdx); err != nil {
+		return err
 	}
 
+	question := questions[questionIdx]
+	isCorrect := question.IsCorrectOption(answerIdx)
+
+	now := time.Now()
 	answer := Answer{
-		QuestionIdx: questionIdx,
-		AnswerIdx:   answerIdx,
-		IsCorrect:   isCorrect,
-		Points:      0,
-		AnsweredAt:  time.Now(),
+		QuestionIdx:  questionIdx,
+		AnswerIdx:    answerIdx,
+		IsCorrect:    isCorrect,
+		Points:       0,
+		AnsweredAt:   now,
+		ResponseTime: now.Sub(e.startTimeOfQuestion[runID][questionIdx]),
 	}
 	if isCorrect {
-		answer.Points += question.Points
+		answer.Points = hintedPoints(activeQuizRun, &question, participantID, questionIdx, questionPoints(&question))
 	}
 
 	activeQuizRun.Answers[participantID] = append(activeQuizRun.Answers[participantID], answer)
@@ -306,21 +363,170 @@ func (e *Engine) SubmitAnswer(
 
 // SubmitAnswerByLetter регистрирует ответ участника по букве.
 func (e *Engine) SubmitAnswerByLetter(
+	ctx context.Context,
 	runID string,
 	participantID int64,
 	letter string,
 ) error {
-	e.mu.Lock()
-	quizErrChan := e.quizErrChan[runID]
-	e.mu.Unlock()
-
 	answerIndex, ok := LetterToIndex(letter)
 	if !ok {
-		close(quizErrChan)
 		return ErrConvertLetterToIndex
 	}
 
-	return e.SubmitAnswer(runID, participantID, e.GetCurrentQuestion(runID), answerIndex)
+	return e.SubmitAnswer(ctx, runID, participantID, e.GetCurrentQuestion(runID), answerIndex)
+}
+
+// SubmitTextAnswer регистрирует ответ участника на текущий открытый вопрос.
+// Ответ сохраняется без оценки и ждёт ручной проверки преподавателем.
+func (e *Engine) SubmitTextAnswer(
+	ctx context.Context,
+	runID string,
+	participantID int64,
+	text string,
+) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ErrEmptyTextAnswer
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	activeQuizRun, ok := e.activeQuizzesRun[runID]
+	if !ok || activeQuizRun.Status != RunStatusRunning {
+		return fmt.Errorf("quiz with runID: %s not running", runID)
+	}
+
+	if _, ok = activeQuizRun.Participants[participantID]; !ok {
+		return fmt.Errorf("no such participant with id %d", participantID)
+	}
+
+	questionIdx := e.runIDToQuestionNumber[runID]
+
+	if !e.runIDToQuestions[runID][questionIdx].IsOpen() {
+		return ErrNotOpenQuestion
+	}
+
+	if err := e.checkAnswerable(activeQuizRun, participantID, questionIdx); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	activeQuizRun.Answers[participantID] = append(activeQuizRun.Answers[participantID], Answer{
+		QuestionIdx:  questionIdx,
+		AnswerIdx:    -1,
+		AnsweredAt:   now,
+		ResponseTime: now.Sub(e.startTimeOfQuestion[runID][questionIdx]),
+		Text:         text,
+		Pending:      true,
+	})
+
+	return nil
+}
+
+// GetPendingAnswers возвращает открытые ответы, ожидающие проверки,
+// упорядоченные по номеру вопроса и ID участника.
+func (e *Engine) GetPendingAnswers(runID string) ([]PendingAnswer, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	activeQuizRun, ok := e.activeQuizzesRun[runID]
+	if !ok {
+		return nil, fmt.Errorf("quiz with runID: %s is not found", runID)
+	}
+
+	questions := e.runIDToQuestions[runID]
+
+	var pending []PendingAnswer
+
+	for participantID, answers := range activeQuizRun.Answers {
+		for _, answer := range answers {
+			if !answer.Pending {
+				continue
+			}
+
+			question := &questions[answer.QuestionIdx]
+			pending = append(pending, PendingAnswer{
+				ParticipantID: participantID,
+				QuestionIdx:   answer.QuestionIdx,
+				Question:      question,
+				Text:          answer.Text,
+				MaxPoints:     questionPoints(question),
+			})
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].QuestionIdx != pending[j].QuestionIdx {
+			return pending[i].QuestionIdx < pending[j].QuestionIdx
+		}
+
+		return pending[i].ParticipantID < pending[j].ParticipantID
+	})
+
+	return pending, nil
+}
+
+// GradeAnswer выставляет баллы за открытый ответ участника.
+// Когда проверены все ответы, запуск переходит в статус "finished".
+// Возвращает количество ответов, которые ещё ожидают проверки.
+func (e *Engine) GradeAnswer(
+	runID string,
+	participantID int64,
+	questionIdx int,
+	points int,
+	comment string,
+) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	activeQuizRun, ok := e.activeQuizzesRun[runID]
+	if !ok || activeQuizRun.Status != RunStatusGrading {
+		return 0, fmt.Errorf("quiz with runID: %s is not awaiting grading", runID)
+	}
+
+	questions := e.runIDToQuestions[runID]
+	if questionIdx < 0 || questionIdx >= len(questions) {
+		return 0, ErrInvalidQuestionIndex
+	}
+
+	maxPoints := questionPoints(&questions[questionIdx])
+	if points < 0 || points > maxPoints {
+		return 0, ErrInvalidPoints
+	}
+
+	answers := activeQuizRun.Answers[participantID]
+
+	graded := false
+
+	for i := range answers {
+		if answers[i].QuestionIdx == questionIdx && answers[i].Pending {
+			answers[i].Pending = false
+			answers[i].Points = hintedPoints(activeQuizRun, &questions[questionIdx], participantID, questionIdx, points)
+			answers[i].IsCorrect = points == maxPoints
+			answers[i].Comment = comment
+			graded = true
+
+			break
+		}
+	}
+
+	if !graded {
+		return 0, ErrNoPendingAnswer
+	}
+
+	remaining := countPending(activeQuizRun)
+	if remaining == 0 {
+		activeQuizRun.Status = RunStatusFinished
+	}
+
+	return remaining, nil
 }
 
 // GetCurrentQuestion возвращает текущий номер вопроса.
@@ -342,60 +548,84 @@ func (e *Engine) GetResults(runID string) (*QuizResults, error) {
 	defer e.mu.Unlock()
 
 	activeQuizRun, ok := e.activeQuizzesRun[runID]
-	if !ok || activeQuizRun.Status != RunStatusFinished {
+	if !ok || (activeQuizRun.Status != RunStatusFinished && activeQuizRun.Status != RunStatusGrading) {
 		return nil, fmt.Errorf("events %s is not finished", runID)
 	}
 
+	return e.buildResults(activeQuizRun), nil
+}
+
+// buildResults подсчитывает баллы и строит таблицу лидеров запуска.
+// Вызывается под мьютексом.
+func (e *Engine) buildResults(activeQuizRun *QuizRun) *QuizResults {
 	results := &QuizResults{
-		RunID:       runID,
-		QuizTitle:   e.quizzes[activeQuizRun.QuizID].Title,
-		Leaderboard: make([]LeaderboardEntry, 0, len(activeQuizRun.Participants)),
-		TotalTime:   activeQuizRun.FinishedAt.Sub(activeQuizRun.StartedAt),
+		RunID:        activeQuizRun.ID,
+		QuizTitle:    e.quizzes[activeQuizRun.QuizID].Title,
+		Leaderboard:  make([]LeaderboardEntry, 0, len(activeQuizRun.Participants)),
+		TotalTime:    activeQuizRun.FinishedAt.Sub(activeQuizRun.StartedAt),
+		Pending:      countPending(activeQuizRun),
+		Registration: e.quizzes[activeQuizRun.QuizID].Settings.Registration,
+	}
+
+	questions := e.runIDToQuestions[activeQuizRun.ID]
+	for _, question := range questions {
+		if question.Section != "" && !slices.Contains(results.Sections, question.Section) {
+			results.Sections = append(results.Sections, question.Section)
+		}
 	}
 
 	for participantTelegramID, participant := range activeQuizRun.Participants {
 		participantScore := 0
 		correctCount := 0
+		sectionScores := make(map[string]int, len(results.Sections))
 
 		var timeResult time.Duration
 
 		answers := activeQuizRun.Answers[participantTelegramID]
 		for _, answer := range answers {
-			if answer.IsCorrect {
-				if answer.Points == 0 {
-					participantScore += 1
-				} else {
-					participantScore += answer.Points
-				}
+			participantScore += answer.Points
+
+			if section := questions[answer.QuestionIdx].Section; section != "" {
+				sectionScores[section] += answer.Points
+			}
 
+			if answer.IsCorrect {
 				correctCount++
 			}
 
-			timeResult += answer.AnsweredAt.Sub(e.startTimeOfQuestion[answer.QuestionIdx])
+			timeResult += answer.ResponseTime
 		}
 
 		results.Leaderboard = append(results.Leaderboard, LeaderboardEntry{
-			Participant:  participant,
-			Score:        participantScore,
-			CorrectCount: correctCount,
-			TotalTime:    timeResult,
-			Rank:         0,
+			Participant:   participant,
+			Score:         participantScore,
+			CorrectCount:  correctCount,
+			TotalTime:     timeResult,
+			Rank:          0,
+			HintsUsed:     maps.Clone(activeQuizRun.HintsUsed[participantTelegramID]),
+			SectionScores: sectionScores,
 		})
 	}
 
-	sort.Slice(results.Leaderboard, func(i, j int) bool {
-		if results.Leaderboard[i].Score != results.Leaderboard[j].Score {
-			return results.Leaderboard[i].Score > results.Leaderboard[j].Score
+	rankLeaderboard(results.Leaderboard)
+
+	return results
+}
+
+// rankLeaderboard сортирует таблицу лидеров по баллам, при равенстве — по суммарному времени ответов,
+// и проставляет места.
+func rankLeaderboard(leaderboard []LeaderboardEntry) {
+	sort.Slice(leaderboard, func(i, j int) bool {
+		if leaderboard[i].Score != leaderboard[j].Score {
+			return leaderboard[i].Score > leaderboard[j].Score
 		}
 
-		return results.Leaderboard[i].TotalTime < results.Leaderboard[j].TotalTime
+		return leaderboard[i].TotalTime < leaderboard[j].TotalTime
 	})
 
-	for i := range results.Leaderboard {
-		results.Leaderboard[i].Rank = i + 1
+	for i := range leaderboard {
+		leaderboard[i].Rank = i + 1
 	}
-
-	return results, nil
 }
 
 // ExportCSV экспортирует результаты в CSV.
@@ -407,22 +637,30 @@ func (e *Engine) ExportCSV(runID string) ([]byte, error) {
 
 	var buf bytes.Buffer
 
+	header := []string{
+		"Rank",
+		"TelegramID",
+		"Username",
+		"FirstName",
+		"LastName",
+		"Score",
+		"CorrectCount",
+		"TotalTime",
+		"Hints",
+	}
+	for _, section := range quizResults.Sections {
+		header = append(header, "Section: "+section)
+	}
+
+	for _, field := range quizResults.Registration {
+		header = append(header, "Reg: "+field)
+	}
+
 	w := csv.NewWriter(&buf)
-	_ = w.Write(
-		[]string{
-			"Rank",
-			"TelegramID",
-			"Username",
-			"FirstName",
-			"LastName",
-			"Score",
-			"CorrectCount",
-			"TotalTime",
-		},
-	)
+	_ = w.Write(header)
 
 	for _, ld := range quizResults.Leaderboard {
-		_ = w.Write([]string{
+		record := []string{
 			strconv.Itoa(ld.Rank),
 			strconv.FormatInt(ld.Participant.TelegramID, 10),
 			ld.Participant.Username,
@@ -431,7 +669,17 @@ func (e *Engine) ExportCSV(runID string) ([]byte, error) {
 			strconv.Itoa(ld.Score),
 			strconv.Itoa(ld.CorrectCount),
 			ld.TotalTime.String(),
-		})
+			formatHintsUsed(ld.HintsUsed),
+		}
+		for _, section := range quizResults.Sections {
+			record = append(record, strconv.Itoa(ld.SectionScores[section]))
+		}
+
+		for _, field := range quizResults.Registration {
+			record = append(record, ld.Participant.RegData[field])
+		}
+
+		_ = w.Write(record)
 	}
 
 	w.Flush()
@@ -444,6 +692,18 @@ func (e *Engine) ExportCSV(runID string) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// formatHintsUsed форматирует использование подсказок для CSV: "Q1:1;Q3:2".
+func formatHintsUsed(hintsUsed map[int]int) string {
+	questions := slices.Sorted(maps.Keys(hintsUsed))
+
+	parts := make([]string, 0, len(questions))
+	for _, questionIdx := range questions {
+		parts = append(parts, fmt.Sprintf("Q%d:%d", questionIdx+1, hintsUsed[questionIdx]))
+	}
+
+	return strings.Join(parts, ";")
+}
+
 // GetRun возвращает запуск по ID.
 func (e *Engine) GetRun(runID string) (*QuizRun, error) {
 	e.mu.RLock()
@@ -457,12 +717,81 @@ func (e *Engine) GetRun(runID string) (*QuizRun, error) {
 	return activeQuizRun, nil
 }
 
+// copyQuestions возвращает глубокую копию вопросов, чтобы перемешивание
+// вариантов в одном запуске не затрагивало квиз и другие запуски.
+func copyQuestions(questions []Question) []Question {
+	copied := make([]Question, len(questions))
+	for i, question := range questions {
+		copied[i] = question
+		copied[i].Options = append([]string(nil), question.Options...)
+		copied[i].Accepted = slices.Clone(question.Accepted)
+	}
+
+	return copied
+}
+
+// checkAnswerable проверяет, что вопрос questionIdx ещё открыт и участник на него не отвечал.
+// Вызывается под мьютексом.
+func (e *Engine) checkAnswerable(activeQuizRun *QuizRun, participantID int64, questionIdx int) error {
+	if e.runIDToQuestionNumber[activeQuizRun.ID] != questionIdx {
+		return ErrStaleQuestion
+	}
+
+	quiz := e.quizzes[activeQuizRun.QuizID]
+	limit := time.Duration(questionTime(quiz, &e.runIDToQuestions[activeQuizRun.ID][questionIdx])) * time.Second
+
+	if time.Since(e.startTimeOfQuestion[activeQuizRun.ID][questionIdx]) > limit {
+		return ErrStaleQuestion
+	}
+
+	for _, answer := range activeQuizRun.Answers[participantID] {
+		if answer.QuestionIdx == questionIdx {
+			return ErrAlreadyAnswered
+		}
+	}
+
+	return nil
+}
+
+// questionTime возвращает время на вопрос в секундах с учётом настройки вопроса.
+func questionTime(quiz *Quiz, question *Question) int {
+	if question.Time > 0 {
+		return question.Time
+	}
+
+	return quiz.Settings.TimePerQuestion
+}
+
+// questionPoints возвращает количество баллов за вопрос (по умолчанию 1).
+func questionPoints(question *Question) int {
+	if question.Points == 0 {
+		return 1
+	}
+
+	return question.Points
+}
+
+// countPending возвращает количество непроверенных открытых ответов в запуске.
+func countPending(activeQuizRun *QuizRun) int {
+	cnt := 0
+
+	for _, answers := range activeQuizRun.Answers {
+		for _, answer := range answers {
+			if answer.Pending {
+				cnt++
+			}
+		}
+	}
+
+	return cnt
+}
+
 // waitEndOfQuestion ждет окончание вопроса.
 func (e *Engine) waitEndOfQuestion(
 	ctx context.Context,
 	activeQuizRun *QuizRun,
 	questionIndex, questionTime int,
-	quiz *Quiz,
+	question *Question,
 	events chan QuizEvent,
 	quizErrChan chan struct{},
 ) bool {
@@ -475,7 +804,7 @@ func (e *Engine) waitEndOfQuestion(
 			event := QuizEvent{
 				Type:        EventTypeTimeUp,
 				QuestionIdx: questionIndex,
-				Question:    &quiz.Questions[questionIndex],
+				Question:    question,
 			}
 			events <- event
 
@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// runArchive — выгруженный из памяти запуск: квиз, вопросы в порядке запуска с перемешанными вариантами
+// и сам запуск с ответами участников и историей перепроверок.
+type runArchive struct {
+	Quiz      *Quiz
+	Questions []Question
+	Run       *QuizRun
+}
+
+// EvictRun удаляет из памяти завершённый или отменённый запуск со всеми его данными.
+// Квиз удаляется вместе с последним своим запуском. Для активного запуска возвращает ErrRunActive.
+func (e *Engine) EvictRun(runID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	activeQuizRun, ok := e.activeQuizzesRun[runID]
+	if !ok {
+		return fmt.Errorf("quiz with runID: %s is not found", runID)
+	}
+
+	if activeQuizRun.Status != RunStatusFinished && activeQuizRun.Status != RunStatusCancelled {
+		return ErrRunActive
+	}
+
+	delete(e.activeQuizzesRun, runID)
+	delete(e.runIDToQuestions, runID)
+	delete(e.runIDToEvents, runID)
+	delete(e.runIDToQuestionNumber, runID)
+	delete(e.startTimeOfQuestion, runID)
+	delete(e.quizErrChan, runID)
+	delete(e.runIDToContinue, runID)
+
+	for _, other := range e.activeQuizzesRun {
+		if other.QuizID == activeQuizRun.QuizID {
+			return nil
+		}
+	}
+
+	delete(e.quizzes, activeQuizRun.QuizID)
+
+	return nil
+}
+
+// ArchiveRun возвращает завершённый запуск в JSON, из которого RestoreRun вернёт его в память после EvictRun.
+// Для незавершённого запуска возвращает ErrNotFinished.
+func (e *Engine) ArchiveRun(runID string) ([]byte, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	activeQuizRun, ok := e.activeQuizzesRun[runID]
+	if !ok {
+		return nil, fmt.Errorf("quiz with runID: %s is not found", runID)
+	}
+
+	if activeQuizRun.Status != RunStatusFinished {
+		return nil, ErrNotFinished
+	}
+
+	return json.Marshal(runArchive{
+		Quiz:      e.quizzes[activeQuizRun.QuizID],
+		Questions: e.runIDToQuestions[runID],
+		Run:       activeQuizRun,
+	})
+}
+
+// RestoreRun возвращает в память запуск, сохранённый ArchiveRun, и возвращает его квиз.
+// Запуск, который уже есть в памяти, не меняется.
+func (e *Engine) RestoreRun(data []byte) (*Quiz, error) {
+	archive := &runArchive{}
+	if err := json.Unmarshal(data, archive); err != nil {
+		return nil, err
+	}
+
+	if archive.Quiz == nil || archive.Run == nil {
+		return nil, ErrNilQuiz
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if activeQuizRun, ok := e.activeQuizzesRun[archive.Run.ID]; ok {
+		return e.quizzes[activeQuizRun.QuizID], nil
+	}
+
+	quiz, ok := e.quizzes[archive.Quiz.ID]
+	if !ok {
+		quiz = archive.Quiz
+		e.quizzes[quiz.ID] = quiz
+	}
+
+	e.activeQuizzesRun[archive.Run.ID] = archive.Run
+	e.runIDToQuestions[archive.Run.ID] = archive.Questions
+
+	return quiz, nil
+}
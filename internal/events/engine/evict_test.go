@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvictRun(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Evict",
+		"settings": {"time_per_question": 5},
+		"questions": [{"text": "Q1", "options": ["A", "B"], "correct": 0}]
+	}`))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	first, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+
+	second, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+
+	// запуск в лобби ещё активен
+	assert.ErrorIs(t, engine.EvictRun(first.ID), ErrRunActive)
+
+	require.NoError(t, engine.CancelRun(first.ID))
+	require.NoError(t, engine.EvictRun(first.ID))
+
+	_, err = engine.GetRun(first.ID)
+	assert.Error(t, err)
+	assert.Error(t, engine.EvictRun(first.ID))
+
+	// квиз остаётся, пока есть другой его запуск
+	assert.Contains(t, engine.quizzes, quiz.ID)
+
+	require.NoError(t, engine.CancelRun(second.ID))
+	require.NoError(t, engine.EvictRun(second.ID))
+
+	assert.NotContains(t, engine.quizzes, quiz.ID)
+	assert.Empty(t, engine.activeQuizzesRun)
+	assert.Empty(t, engine.runIDToQuestions)
+}
+
+func TestArchiveAndRestoreRun(t *testing.T) {
+	engine := NewEngine()
+	run := finishedRun(t, engine, []int{0, 1})
+
+	before, err := engine.GetParticipantReport(run.ID, 2)
+	require.NoError(t, err)
+
+	data, err := engine.ArchiveRun(run.ID)
+	require.NoError(t, err)
+	require.NoError(t, engine.EvictRun(run.ID))
+
+	_, err = engine.GetParticipantReport(run.ID, 2)
+	assert.Error(t, err)
+
+	quiz, err := engine.RestoreRun(data)
+	require.NoError(t, err)
+	assert.Equal(t, "Regrade Quiz", quiz.Title)
+
+	after, err := engine.GetParticipantReport(run.ID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, before.Entry.Score, after.Entry.Score)
+	assert.Equal(t, before.Items[0].Answer.AnswerIdx, after.Items[0].Answer.AnswerIdx)
+
+	// восстановленный запуск можно перепроверить
+	_, err = engine.RegradeQuestion(run.ID, 0, RegradeKey{AcceptAny: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int{1: 2, 2: 2}, scores(t, engine, run.ID))
+
+	// повторное восстановление не откатывает перепроверку
+	_, err = engine.RestoreRun(data)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int{1: 2, 2: 2}, scores(t, engine, run.ID))
+}
+
+func TestArchiveRun_NotFinished(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Archive",
+		"settings": {"time_per_question": 5},
+		"questions": [{"text": "Q1", "options": ["A", "B"], "correct": 0}]
+	}`))
+	require.NoError(t, err)
+
+	run, err := engine.StartRun(context.Background(), quiz)
+	require.NoError(t, err)
+
+	_, err = engine.ArchiveRun(run.ID)
+	assert.ErrorIs(t, err, ErrNotFinished)
+}
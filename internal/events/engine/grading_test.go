@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadQuiz_OpenQuestion(t *testing.T) {
+	engine := NewEngine()
+
+	data := []byte(`{
+		"title": "Open Quiz",
+		"settings": {"time_per_question": 5},
+		"questions": [{"type": "text", "text": "Explain goroutines", "points": 4}]
+	}`)
+
+	quiz, err := engine.LoadQuiz(data)
+	require.NoError(t, err)
+	assert.True(t, quiz.Questions[0].IsOpen())
+
+	_, err = engine.LoadQuiz([]byte(`{
+		"title": "Bad Type",
+		"settings": {"time_per_question": 5},
+		"questions": [{"type": "essay", "text": "Q"}]
+	}`))
+	assert.Error(t, err)
+}
+
+func TestOpenQuestion_ManualGrading(t *testing.T) {
+	engine := NewEngine()
+
+	data := []byte(`{
+		"title": "Open Quiz",
+		"settings": {"time_per_question": 5},
+		"questions": [
+			{"text": "Q1", "options": ["A", "B"], "correct": 0},
+			{"type": "text", "text": "Explain goroutines", "points": 4}
+		]
+	}`)
+
+	quiz, err := engine.LoadQuiz(data)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 1}))
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 2}))
+
+	events, err := engine.StartQuiz(ctx, run.ID)
+	require.NoError(t, err)
+
+	defer drainEvents(events)
+
+	<-events
+
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, 0))
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 2, 0, 0))
+
+	<-events
+
+	// на открытый вопрос нельзя ответить буквой, а на закрытый — текстом
+	assert.ErrorIs(t, engine.SubmitAnswer(ctx, run.ID, 1, 1, 0), ErrOpenQuestion)
+	assert.ErrorIs(t, engine.SubmitTextAnswer(ctx, run.ID, 1, "   "), ErrEmptyTextAnswer)
+
+	require.NoError(t, engine.SubmitTextAnswer(ctx, run.ID, 1, "lightweight threads"))
+	require.NoError(t, engine.SubmitTextAnswer(ctx, run.ID, 2, "no idea"))
+
+	event := <-events
+	assert.Equal(t, EventTypeFinished, event.Type)
+
+	gotRun, err := engine.GetRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, RunStatusGrading, gotRun.Status)
+
+	results, err := engine.GetResults(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, results.Pending)
+
+	pending, err := engine.GetPendingAnswers(run.ID)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, int64(1), pending[0].ParticipantID)
+	assert.Equal(t, "lightweight threads", pending[0].Text)
+	assert.Equal(t, 4, pending[0].MaxPoints)
+
+	_, err = engine.GradeAnswer(run.ID, 1, 1, 5, "")
+	assert.ErrorIs(t, err, ErrInvalidPoints)
+
+	remaining, err := engine.GradeAnswer(run.ID, 1, 1, 4, "Отлично")
+	require.NoError(t, err)
+	assert.Equal(t, 1, remaining)
+
+	_, err = engine.GradeAnswer(run.ID, 1, 1, 4, "")
+	assert.ErrorIs(t, err, ErrNoPendingAnswer)
+
+	remaining, err = engine.GradeAnswer(run.ID, 2, 1, 2, "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, RunStatusFinished, gotRun.Status)
+
+	results, err = engine.GetResults(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, results.Pending)
+	require.Len(t, results.Leaderboard, 2)
+	assert.Equal(t, int64(1), results.Leaderboard[0].Participant.TelegramID)
+	assert.Equal(t, 5, results.Leaderboard[0].Score)
+	assert.Equal(t, 2, results.Leaderboard[0].CorrectCount)
+	assert.Equal(t, 3, results.Leaderboard[1].Score)
+	assert.Equal(t, 1, results.Leaderboard[1].CorrectCount)
+}
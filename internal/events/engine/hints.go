@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+)
+
+// UseHint выдаёт участнику следующую подсказку к вопросу questionIdx.
+// Вопрос должен быть текущим, а участник — ещё не ответившим на него.
+// Стоимость подсказки вычитается из баллов за вопрос при подсчёте.
+func (e *Engine) UseHint(
+	ctx context.Context,
+	runID string,
+	participantID int64,
+	questionIdx int,
+) (*Hint, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	activeQuizRun, ok := e.activeQuizzesRun[runID]
+	if !ok || activeQuizRun.Status != RunStatusRunning {
+		return nil, fmt.Errorf("quiz with runID: %s not running", runID)
+	}
+
+	if e.runIDToQuestionNumber[runID] != questionIdx {
+		return nil, ErrStaleQuestion
+	}
+
+	if _, ok = activeQuizRun.Participants[participantID]; !ok {
+		return nil, fmt.Errorf("no such participant with id %d", participantID)
+	}
+
+	for _, answer := range activeQuizRun.Answers[participantID] {
+		if answer.QuestionIdx == questionIdx {
+			return nil, ErrAlreadyAnswered
+		}
+	}
+
+	question := &e.runIDToQuestions[runID][questionIdx]
+
+	used := activeQuizRun.HintsUsed[participantID][questionIdx]
+	if used >= len(question.Hints) {
+		return nil, ErrNoMoreHints
+	}
+
+	if activeQuizRun.HintsUsed[participantID] == nil {
+		activeQuizRun.HintsUsed[participantID] = make(map[int]int)
+	}
+
+	activeQuizRun.HintsUsed[participantID][questionIdx]++
+
+	hint := question.Hints[used]
+
+	return &hint, nil
+}
+
+// hintedPoints возвращает баллы за вопрос за вычетом стоимости взятых участником подсказок.
+// Результат не может быть отрицательным. Вызывается под мьютексом.
+func hintedPoints(
+	activeQuizRun *QuizRun,
+	question *Question,
+	participantID int64,
+	questionIdx int,
+	points int,
+) int {
+	used := activeQuizRun.HintsUsed[participantID][questionIdx]
+	for _, hint := range question.Hints[:min(used, len(question.Hints))] {
+		points -= hint.Cost
+	}
+
+	return max(points, 0)
+}
@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseHint_DeductsPoints(t *testing.T) {
+	engine := NewEngine()
+
+	data := []byte(`{
+		"title": "Hints Quiz",
+		"settings": {"time_per_question": 5},
+		"questions": [
+			{
+				"text": "Q1",
+				"options": ["A", "B"],
+				"correct": 0,
+				"points": 5,
+				"hints": [{"text": "Not B", "cost": 2}, {"text": "Really not B", "cost": 4}]
+			},
+			{"text": "Q2", "options": ["A", "B"], "correct": 1}
+		]
+	}`)
+
+	quiz, err := engine.LoadQuiz(data)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 1}))
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 2}))
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 3}))
+
+	events, err := engine.StartQuiz(ctx, run.ID)
+	require.NoError(t, err)
+
+	defer drainEvents(events)
+
+	<-events
+
+	_, err = engine.UseHint(ctx, run.ID, 1, 1)
+	assert.ErrorIs(t, err, ErrStaleQuestion)
+
+	hint, err := engine.UseHint(ctx, run.ID, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Not B", hint.Text)
+
+	for range 2 {
+		_, err = engine.UseHint(ctx, run.ID, 2, 0)
+		require.NoError(t, err)
+	}
+
+	_, err = engine.UseHint(ctx, run.ID, 2, 0)
+	assert.ErrorIs(t, err, ErrNoMoreHints)
+
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, 0))
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 2, 0, 0))
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 3, 0, 0))
+
+	_, err = engine.UseHint(ctx, run.ID, 3, 0)
+	assert.Error(t, err)
+
+	<-events
+
+	_, err = engine.UseHint(ctx, run.ID, 3, 1)
+	assert.ErrorIs(t, err, ErrNoMoreHints)
+
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 1, 0))
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 2, 1, 0))
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 3, 1, 0))
+
+	<-events
+
+	results, err := engine.GetResults(run.ID)
+	require.NoError(t, err)
+
+	byID := make(map[int64]LeaderboardEntry)
+	for _, entry := range results.Leaderboard {
+		byID[entry.Participant.TelegramID] = entry
+	}
+
+	assert.Equal(t, 5, byID[3].Score)
+	assert.Equal(t, 3, byID[1].Score)
+	assert.Equal(t, 0, byID[2].Score) // 5 - 2 - 4 < 0
+	assert.Equal(t, 1, byID[2].CorrectCount)
+	assert.Equal(t, map[int]int{0: 2}, byID[2].HintsUsed)
+	assert.Empty(t, byID[3].HintsUsed)
+
+	csvData, err := engine.ExportCSV(run.ID)
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(strings.NewReader(string(csvData))).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, "Hints", records[0][len(records[0])-1])
+	assert.Contains(t, string(csvData), "Q1:2")
+}
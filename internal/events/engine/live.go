@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+)
+
+// GetLiveSnapshot возвращает текущий вопрос, оставшееся время, число ответивших, распределение ответов
+// по вариантам и topN лучших участников. Рассчитан на вызов раз в несколько секунд: берёт только
+// блокировку на чтение и проходит по ответам один раз. Для незапущенного или завершённого запуска
+// возвращает ErrNotRunning.
+func (e *Engine) GetLiveSnapshot(runID string, topN int) (*LiveSnapshot, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	activeQuizRun, ok := e.activeQuizzesRun[runID]
+	if !ok {
+		return nil, fmt.Errorf("quiz with runID: %s is not found", runID)
+	}
+
+	if activeQuizRun.Status != RunStatusRunning {
+		return nil, ErrNotRunning
+	}
+
+	questions := e.runIDToQuestions[runID]
+
+	snapshot := &LiveSnapshot{
+		QuestionIdx:    -1,
+		QuestionsCount: len(questions),
+		Participants:   len(activeQuizRun.Participants),
+	}
+
+	questionIdx := e.runIDToQuestionNumber[runID]
+
+	startedAt, started := e.startTimeOfQuestion[runID][questionIdx]
+	if started {
+		question := questions[questionIdx]
+		question.Options = append([]string(nil), question.Options...)
+
+		limit := time.Duration(questionTime(e.quizzes[activeQuizRun.QuizID], &question)) * time.Second
+
+		snapshot.QuestionIdx = questionIdx
+		snapshot.Question = question
+		snapshot.TimeLeft = max(limit-time.Since(startedAt), 0)
+
+		if !question.IsOpen() {
+			snapshot.Distribution = make([]int, len(question.Options))
+		}
+	}
+
+	leaderboard := make([]LeaderboardEntry, 0, len(activeQuizRun.Participants))
+
+	for participantID, participant := range activeQuizRun.Participants {
+		entry := LeaderboardEntry{Participant: participant}
+
+		for _, answer := range activeQuizRun.Answers[participantID] {
+			entry.Score += answer.Points
+			entry.TotalTime += answer.ResponseTime
+
+			if answer.IsCorrect {
+				entry.CorrectCount++
+			}
+
+			if !started || answer.QuestionIdx != questionIdx {
+				continue
+			}
+
+			snapshot.Answered++
+
+			if answer.AnswerIdx >= 0 && answer.AnswerIdx < len(snapshot.Distribution) {
+				snapshot.Distribution[answer.AnswerIdx]++
+			}
+		}
+
+		leaderboard = append(leaderboard, entry)
+	}
+
+	rankLeaderboard(leaderboard)
+	snapshot.Top = leaderboard[:min(max(topN, 0), len(leaderboard))]
+
+	return snapshot, nil
+}
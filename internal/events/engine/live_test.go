@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLiveSnapshot(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Live",
+		"settings": {"time_per_question": 30},
+		"questions": [
+			{"text": "Q1", "options": ["A", "B", "C"], "correct": 1, "points": 2},
+			{"text": "Q2", "options": ["A", "B"], "correct": 0}
+		]
+	}`))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+
+	for id := int64(1); id <= 3; id++ {
+		require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: id}))
+	}
+
+	_, err = engine.GetLiveSnapshot(run.ID, 5)
+	assert.ErrorIs(t, err, ErrNotRunning)
+
+	events, err := engine.StartQuiz(ctx, run.ID)
+	require.NoError(t, err)
+
+	event := <-events
+	require.Equal(t, EventTypeQuestion, event.Type)
+
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, 1))
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 2, 0, 2))
+
+	snapshot, err := engine.GetLiveSnapshot(run.ID, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, snapshot.QuestionIdx)
+	assert.Equal(t, 2, snapshot.QuestionsCount)
+	assert.Equal(t, "Q1", snapshot.Question.Text)
+	assert.Equal(t, 3, snapshot.Participants)
+	assert.Equal(t, 2, snapshot.Answered)
+	assert.Equal(t, []int{0, 1, 1}, snapshot.Distribution)
+	assert.Greater(t, snapshot.TimeLeft, 25*time.Second)
+	assert.LessOrEqual(t, snapshot.TimeLeft, 30*time.Second)
+
+	require.Len(t, snapshot.Top, 2)
+	assert.Equal(t, int64(1), snapshot.Top[0].Participant.TelegramID)
+	assert.Equal(t, 2, snapshot.Top[0].Score)
+	assert.Equal(t, 1, snapshot.Top[0].Rank)
+
+	// изменение копии вопроса не затрагивает запуск
+	snapshot.Question.Options[0] = "changed"
+	snapshot, err = engine.GetLiveSnapshot(run.ID, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "A", snapshot.Question.Options[0])
+	assert.Len(t, snapshot.Top, 3)
+
+	require.NoError(t, engine.CancelRun(run.ID))
+
+	_, err = engine.GetLiveSnapshot(run.ID, 5)
+	assert.ErrorIs(t, err, ErrNotRunning)
+}
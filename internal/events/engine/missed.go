@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+)
+
+// GetRunQuestions возвращает копию вопросов запуска в том виде, в котором их видели участники
+// (с учётом перемешивания вариантов).
+func (e *Engine) GetRunQuestions(runID string) ([]Question, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	questions, ok := e.runIDToQuestions[runID]
+	if !ok {
+		return nil, fmt.Errorf("quiz with runID: %s is not found", runID)
+	}
+
+	return copyQuestions(questions), nil
+}
+
+// GetMissedQuestions возвращает для каждого участника номера вопросов с выбором ответа,
+// на которые он ответил неверно или не ответил совсем. Открытые и аннулированные вопросы не учитываются.
+func (e *Engine) GetMissedQuestions(runID string) (map[int64][]int, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	activeQuizRun, ok := e.activeQuizzesRun[runID]
+	if !ok {
+		return nil, fmt.Errorf("quiz with runID: %s is not found", runID)
+	}
+
+	if activeQuizRun.Status != RunStatusFinished && activeQuizRun.Status != RunStatusGrading {
+		return nil, ErrNotFinished
+	}
+
+	questions := e.runIDToQuestions[runID]
+
+	voided := voidedQuestions(activeQuizRun)
+
+	missed := make(map[int64][]int, len(activeQuizRun.Participants))
+
+	for participantID := range activeQuizRun.Participants {
+		answers := activeQuizRun.Answers[participantID]
+
+		for i := range questions {
+			if questions[i].IsOpen() || voided[i] {
+				continue
+			}
+
+			correct := slices.ContainsFunc(answers, func(answer Answer) bool {
+				return answer.QuestionIdx == i && answer.IsCorrect
+			})
+			if !correct {
+				missed[participantID] = append(missed[participantID], i)
+			}
+		}
+	}
+
+	return missed, nil
+}
+
+// voidedQuestions возвращает вопросы, аннулированные последней перепроверкой.
+func voidedQuestions(activeQuizRun *QuizRun) map[int]bool {
+	voided := make(map[int]bool)
+	for _, regrade := range activeQuizRun.Regrades {
+		voided[regrade.QuestionIdx] = regrade.Key.Void
+	}
+
+	return voided
+}
@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMissedQuestions(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Missed",
+		"settings": {"time_per_question": 5},
+		"questions": [
+			{"text": "Q1", "options": ["A", "B"], "correct": 0},
+			{"type": "text", "text": "Q2"},
+			{"text": "Q3", "options": ["A", "B"], "correct": 1}
+		]
+	}`))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 1}))
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 2}))
+
+	events, err := engine.StartQuiz(ctx, run.ID)
+	require.NoError(t, err)
+
+	defer drainEvents(events)
+
+	<-events
+
+	_, err = engine.GetMissedQuestions(run.ID)
+	assert.ErrorIs(t, err, ErrNotFinished)
+
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, 0))
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 2, 0, 1))
+
+	<-events
+
+	require.NoError(t, engine.SubmitTextAnswer(ctx, run.ID, 1, "answer"))
+	require.NoError(t, engine.SubmitTextAnswer(ctx, run.ID, 2, "answer"))
+
+	<-events
+
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 2, 0))
+
+	<-events
+
+	missed, err := engine.GetMissedQuestions(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64][]int{1: {2}, 2: {0, 2}}, missed)
+
+	questions, err := engine.GetRunQuestions(run.ID)
+	require.NoError(t, err)
+	require.Len(t, questions, 3)
+
+	questions[0].Options[0] = "changed"
+
+	questions, err = engine.GetRunQuestions(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "A", questions[0].Options[0])
+}
@@ -0,0 +1,159 @@
+package engine
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PracticeSession — одиночное повторное прохождение квиза с мгновенной обратной связью.
+// Тренировка не связана с запусками: её ответы не попадают в таблицу лидеров и CSV.
+type PracticeSession struct {
+	ID            string
+	Quiz          *Quiz
+	ParticipantID int64
+	Timed         bool // учитывать время на вопрос: поздний ответ не приносит баллов
+	Questions     []Question
+	Answers       []Answer
+	StartedAt     time.Time
+	FinishedAt    time.Time
+
+	current int
+	shownAt time.Time
+}
+
+// PracticeFeedback — обратная связь на ответ в тренировке.
+type PracticeFeedback struct {
+	Question  *Question
+	IsCorrect bool
+	Late      bool // ответ пришёл после окончания времени на вопрос
+	Points    int
+	Finished  bool // это был последний вопрос
+}
+
+// PracticeResult — итог тренировки.
+type PracticeResult struct {
+	Score        int
+	MaxScore     int
+	CorrectCount int
+	Total        int
+	Duration     time.Duration
+}
+
+// Ошибки тренировки.
+var (
+	ErrPracticeFinished = errors.New("practice session is finished")
+)
+
+// NewPracticeSession создаёт тренировку по квизу для участника participantID.
+func NewPracticeSession(quiz *Quiz, participantID int64, timed bool, now time.Time) *PracticeSession {
+	return &PracticeSession{
+		ID:            uuid.NewString(),
+		Quiz:          quiz,
+		ParticipantID: participantID,
+		Timed:         timed,
+		Questions:     copyQuestions(quiz.Questions),
+		Answers:       make([]Answer, 0, len(quiz.Questions)),
+		StartedAt:     now,
+		shownAt:       now,
+	}
+}
+
+// CurrentQuestion возвращает номер и текущий вопрос тренировки.
+// Возвращает false, если вопросы закончились.
+func (s *PracticeSession) CurrentQuestion() (int, *Question, bool) {
+	if s.current >= len(s.Questions) {
+		return -1, nil, false
+	}
+
+	return s.current, &s.Questions[s.current], true
+}
+
+// QuestionTime возвращает время на текущий вопрос в секундах.
+func (s *PracticeSession) QuestionTime() int {
+	return questionTime(s.Quiz, &s.Questions[min(s.current, len(s.Questions)-1)])
+}
+
+// ShowQuestion отмечает момент показа текущего вопроса (для режима с таймером).
+func (s *PracticeSession) ShowQuestion(now time.Time) {
+	s.shownAt = now
+}
+
+// Answer принимает ответ на текущий вопрос: букву варианта или текст для открытого вопроса.
+// Открытые ответы в тренировке не оцениваются, участник получает только пояснение.
+func (s *PracticeSession) Answer(input string, now time.Time) (*PracticeFeedback, error) {
+	questionIdx, question, ok := s.CurrentQuestion()
+	if !ok {
+		return nil, ErrPracticeFinished
+	}
+
+	answer := Answer{
+		QuestionIdx:  questionIdx,
+		AnswerIdx:    -1,
+		AnsweredAt:   now,
+		ResponseTime: now.Sub(s.shownAt),
+	}
+
+	if question.IsOpen() {
+		answer.Text = strings.TrimSpace(input)
+		if answer.Text == "" {
+			return nil, ErrEmptyTextAnswer
+		}
+	} else {
+		answerIdx, ok := LetterToIndex(strings.ToUpper(strings.TrimSpace(input)))
+		if !ok || answerIdx >= len(question.Options) {
+			return nil, ErrConvertLetterToIndex
+		}
+
+		answer.AnswerIdx = answerIdx
+		answer.IsCorrect = question.IsCorrectOption(answerIdx)
+	}
+
+	late := s.Timed && answer.ResponseTime > time.Duration(s.QuestionTime())*time.Second
+	if answer.IsCorrect && !late {
+		answer.Points = questionPoints(question)
+	}
+
+	s.Answers = append(s.Answers, answer)
+	s.current++
+
+	feedback := &PracticeFeedback{
+		Question:  question,
+		IsCorrect: answer.IsCorrect,
+		Late:      late,
+		Points:    answer.Points,
+		Finished:  s.current >= len(s.Questions),
+	}
+
+	if feedback.Finished {
+		s.FinishedAt = now
+	}
+
+	return feedback, nil
+}
+
+// Result подсчитывает итог тренировки. Открытые вопросы не входят в максимум баллов.
+func (s *PracticeSession) Result() PracticeResult {
+	result := PracticeResult{
+		Total:    len(s.Questions),
+		Duration: s.FinishedAt.Sub(s.StartedAt),
+	}
+
+	for i := range s.Questions {
+		if !s.Questions[i].IsOpen() {
+			result.MaxScore += questionPoints(&s.Questions[i])
+		}
+	}
+
+	for _, answer := range s.Answers {
+		result.Score += answer.Points
+
+		if answer.IsCorrect {
+			result.CorrectCount++
+		}
+	}
+
+	return result
+}
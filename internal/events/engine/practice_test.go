@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func practiceQuiz(t *testing.T) *Quiz {
+	t.Helper()
+
+	quiz, err := NewEngine().LoadQuiz([]byte(`{
+		"title": "Practice",
+		"settings": {"time_per_question": 10},
+		"questions": [
+			{"text": "Q1", "options": ["A", "B"], "correct": 1, "explanation": "B is right", "points": 2},
+			{"type": "text", "text": "Explain"},
+			{"text": "Q3", "options": ["A", "B", "C"], "correct": 0, "time": 5}
+		]
+	}`))
+	require.NoError(t, err)
+
+	return quiz
+}
+
+func TestPracticeSession_Untimed(t *testing.T) {
+	now := time.Now()
+	session := NewPracticeSession(practiceQuiz(t), 1, false, now)
+
+	idx, question, ok := session.CurrentQuestion()
+	require.True(t, ok)
+	assert.Equal(t, 0, idx)
+	assert.Equal(t, "Q1", question.Text)
+
+	_, err := session.Answer("Z", now)
+	assert.ErrorIs(t, err, ErrConvertLetterToIndex)
+
+	feedback, err := session.Answer(" b ", now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, feedback.IsCorrect)
+	assert.False(t, feedback.Late)
+	assert.Equal(t, 2, feedback.Points)
+	assert.Equal(t, "B is right", feedback.Question.Explanation)
+
+	_, err = session.Answer("", now)
+	assert.ErrorIs(t, err, ErrEmptyTextAnswer)
+
+	feedback, err = session.Answer("goroutines are cheap", now)
+	require.NoError(t, err)
+	assert.False(t, feedback.IsCorrect)
+	assert.False(t, feedback.Finished)
+
+	feedback, err = session.Answer("B", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, feedback.IsCorrect)
+	assert.True(t, feedback.Finished)
+
+	_, _, ok = session.CurrentQuestion()
+	assert.False(t, ok)
+
+	_, err = session.Answer("A", now)
+	assert.ErrorIs(t, err, ErrPracticeFinished)
+
+	result := session.Result()
+	assert.Equal(t, 2, result.Score)
+	assert.Equal(t, 3, result.MaxScore)
+	assert.Equal(t, 1, result.CorrectCount)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, time.Minute, result.Duration)
+}
+
+func TestPracticeSession_TimedLateAnswer(t *testing.T) {
+	now := time.Now()
+	session := NewPracticeSession(practiceQuiz(t), 1, true, now)
+
+	feedback, err := session.Answer("B", now.Add(11*time.Second))
+	require.NoError(t, err)
+	assert.True(t, feedback.IsCorrect)
+	assert.True(t, feedback.Late)
+	assert.Equal(t, 0, feedback.Points)
+
+	session.ShowQuestion(now)
+	_, err = session.Answer("text", now)
+	require.NoError(t, err)
+
+	session.ShowQuestion(now)
+	assert.Equal(t, 5, session.QuestionTime())
+
+	feedback, err = session.Answer("A", now.Add(4*time.Second))
+	require.NoError(t, err)
+	assert.False(t, feedback.Late)
+	assert.Equal(t, 1, feedback.Points)
+}
@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"fmt"
+)
+
+// GetQuestionStats возвращает по каждому вопросу запуска, сколько участников ответили и сколько — правильно.
+// Открытый ответ считается правильным, если за него выставлен максимум баллов.
+func (e *Engine) GetQuestionStats(runID string) ([]QuestionStat, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	activeQuizRun, ok := e.activeQuizzesRun[runID]
+	if !ok {
+		return nil, fmt.Errorf("quiz with runID: %s is not found", runID)
+	}
+
+	if activeQuizRun.Status != RunStatusFinished && activeQuizRun.Status != RunStatusGrading {
+		return nil, ErrNotFinished
+	}
+
+	questions := e.runIDToQuestions[runID]
+	voided := voidedQuestions(activeQuizRun)
+
+	stats := make([]QuestionStat, len(questions))
+	for i := range questions {
+		stats[i] = QuestionStat{
+			QuestionIdx:  i,
+			Text:         questions[i].Text,
+			Participants: len(activeQuizRun.Participants),
+			Voided:       voided[i],
+		}
+	}
+
+	for _, answers := range activeQuizRun.Answers {
+		for _, answer := range answers {
+			if answer.QuestionIdx < 0 || answer.QuestionIdx >= len(stats) {
+				continue
+			}
+
+			stats[answer.QuestionIdx].Answered++
+
+			if answer.IsCorrect {
+				stats[answer.QuestionIdx].Correct++
+			}
+		}
+	}
+
+	return stats, nil
+}
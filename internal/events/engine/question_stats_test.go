@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetQuestionStats(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Stats",
+		"settings": {"time_per_question": 5},
+		"questions": [
+			{"text": "Q1", "options": ["A", "B"], "correct": 0},
+			{"text": "Q2", "options": ["A", "B"], "correct": 1}
+		]
+	}`))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 1}))
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 2}))
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 3}))
+
+	events, err := engine.StartQuiz(ctx, run.ID)
+	require.NoError(t, err)
+
+	defer drainEvents(events)
+
+	<-events
+
+	_, err = engine.GetQuestionStats(run.ID)
+	assert.ErrorIs(t, err, ErrNotFinished)
+
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, 0))
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 2, 0, 0))
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 3, 0, 1))
+
+	<-events
+
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 1, 0))
+
+	<-events
+
+	stats, err := engine.GetQuestionStats(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []QuestionStat{
+		{QuestionIdx: 0, Text: "Q1", Participants: 3, Answered: 3, Correct: 2},
+		{QuestionIdx: 1, Text: "Q2", Participants: 3, Answered: 1, Correct: 0},
+	}, stats)
+}
@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadQuiz_Registration(t *testing.T) {
+	engine := NewEngine()
+
+	_, err := engine.LoadQuiz([]byte(`{
+		"title": "Reg",
+		"settings": {"time_per_question": 5, "registration": ["name", "name"]},
+		"questions": [{"text": "Q1", "options": ["A", "B"], "correct": 0}]
+	}`))
+	assert.Error(t, err)
+
+	_, err = engine.LoadQuiz([]byte(`{
+		"title": "Reg",
+		"settings": {"time_per_question": 5, "registration": [""]},
+		"questions": [{"text": "Q1", "options": ["A", "B"], "correct": 0}]
+	}`))
+	assert.Error(t, err)
+}
+
+func TestRegistration_JoinAndExport(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Reg",
+		"settings": {"time_per_question": 5, "registration": ["name", "group"]},
+		"questions": [{"text": "Q1", "options": ["A", "B"], "correct": 0}]
+	}`))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+
+	err = engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 1, RegData: map[string]string{"name": "Иванов Иван"}})
+	require.ErrorIs(t, err, ErrRegistrationRequired)
+	assert.Equal(t, 0, engine.GetParticipantCount(run.ID))
+
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{
+		TelegramID: 1,
+		RegData:    map[string]string{"name": "Иванов Иван", "group": "ИВТБ101"},
+	}))
+
+	events, err := engine.StartQuiz(ctx, run.ID)
+	require.NoError(t, err)
+
+	<-events
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, 0))
+
+	for event := range events {
+		if event.Type == EventTypeFinished {
+			break
+		}
+	}
+
+	data, err := engine.ExportCSV(run.ID)
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	header := records[0]
+	assert.Equal(t, []string{"Reg: name", "Reg: group"}, header[len(header)-2:])
+	assert.Equal(t, []string{"Иванов Иван", "ИВТБ101"}, records[1][len(records[1])-2:])
+}
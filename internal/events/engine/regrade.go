@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// RegradeQuestion пересчитывает баллы за вопрос завершённого запуска по новому ключу:
+// новым правильным вариантам, "засчитать любой ответ" или аннулированию вопроса.
+// Варианты в ключе нумеруются в порядке файла квиза, даже если участникам их показали перемешанными.
+// Исходные ответы и результаты сохраняются в QuizRun.Regrades.
+// Возвращает изменения результатов участников, у которых поменялись баллы или место.
+func (e *Engine) RegradeQuestion(runID string, questionIdx int, key RegradeKey) ([]ScoreChange, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	activeQuizRun, ok := e.activeQuizzesRun[runID]
+	if !ok {
+		return nil, fmt.Errorf("quiz with runID: %s is not found", runID)
+	}
+
+	if activeQuizRun.Status != RunStatusFinished {
+		return nil, ErrNotFinished
+	}
+
+	questions := e.runIDToQuestions[runID]
+	if questionIdx < 0 || questionIdx >= len(questions) {
+		return nil, ErrInvalidQuestionIndex
+	}
+
+	question := &questions[questionIdx]
+	if question.IsOpen() {
+		return nil, ErrOpenQuestion
+	}
+
+	if err := validateRegradeKey(key, len(question.Options)); err != nil {
+		return nil, err
+	}
+
+	// в аудит попадает ключ в порядке файла, как его ввёл преподаватель
+	correct := key.Correct
+	if quiz, ok := e.quizzes[activeQuizRun.QuizID]; ok && len(correct) > 0 {
+		correct = toRunOrder(correct, quiz.Questions[questionIdx].Options, question.Options)
+	}
+
+	before := e.buildResults(activeQuizRun)
+	answersBefore := copyAnswers(activeQuizRun.Answers)
+
+	for participantID, answers := range activeQuizRun.Answers {
+		for i := range answers {
+			if answers[i].QuestionIdx != questionIdx {
+				continue
+			}
+
+			switch {
+			case key.Void:
+				answers[i].IsCorrect = false
+			case key.AcceptAny:
+				answers[i].IsCorrect = true
+			default:
+				answers[i].IsCorrect = slices.Contains(correct, answers[i].AnswerIdx)
+			}
+
+			answers[i].Points = 0
+			if answers[i].IsCorrect {
+				answers[i].Points = hintedPoints(
+					activeQuizRun,
+					question,
+					participantID,
+					questionIdx,
+					questionPoints(question),
+				)
+			}
+		}
+
+		activeQuizRun.Answers[participantID] = answers
+	}
+
+	if len(correct) > 0 {
+		setCorrect(question, correct)
+
+		// новый ключ действует и в тренировках, и в следующих запусках этого квиза
+		if quiz, ok := e.quizzes[activeQuizRun.QuizID]; ok {
+			setCorrect(&quiz.Questions[questionIdx], key.Correct)
+		}
+	}
+
+	activeQuizRun.Regrades = append(activeQuizRun.Regrades, Regrade{
+		QuestionIdx: questionIdx,
+		Key:         key,
+		At:          time.Now(),
+		Answers:     answersBefore,
+		Results:     before,
+	})
+
+	after := e.buildResults(activeQuizRun)
+
+	return diffResults(before, after), nil
+}
+
+// validateRegradeKey проверяет, что в ключе задан ровно один способ перепроверки.
+func validateRegradeKey(key RegradeKey, optionsCnt int) error {
+	modes := 0
+
+	if key.Void {
+		modes++
+	}
+
+	if key.AcceptAny {
+		modes++
+	}
+
+	if len(key.Correct) > 0 {
+		modes++
+	}
+
+	if modes != 1 {
+		return ErrInvalidRegradeKey
+	}
+
+	for _, idx := range key.Correct {
+		if idx < 0 || idx >= optionsCnt {
+			return ErrInvalidAnswerIndex
+		}
+	}
+
+	return nil
+}
+
+// setCorrect записывает в вопрос правильные варианты: первый становится Correct, остальные — Accepted.
+func setCorrect(question *Question, correct []int) {
+	question.Correct = correct[0]
+	question.Accepted = nil
+
+	if len(correct) > 1 {
+		question.Accepted = slices.Clone(correct[1:])
+	}
+}
+
+// toRunOrder переводит номера вариантов из порядка файла квиза в порядок, в котором варианты
+// показывались в запуске. Одинаковые варианты сопоставляются по очереди.
+func toRunOrder(fileIdx []int, fileOptions, runOptions []string) []int {
+	used := make([]bool, len(runOptions))
+	runIdx := make([]int, 0, len(fileIdx))
+
+	for _, idx := range fileIdx {
+		for j, option := range runOptions {
+			if !used[j] && option == fileOptions[idx] {
+				used[j] = true
+				runIdx = append(runIdx, j)
+
+				break
+			}
+		}
+	}
+
+	return runIdx
+}
+
+// copyAnswers возвращает глубокую копию ответов участников.
+func copyAnswers(answers map[int64][]Answer) map[int64][]Answer {
+	copied := make(map[int64][]Answer, len(answers))
+	for participantID, participantAnswers := range answers {
+		copied[participantID] = append([]Answer(nil), participantAnswers...)
+	}
+
+	return copied
+}
+
+// diffResults возвращает участников, у которых изменились баллы или место.
+func diffResults(before, after *QuizResults) []ScoreChange {
+	beforeByID := make(map[int64]LeaderboardEntry, len(before.Leaderboard))
+	for _, entry := range before.Leaderboard {
+		beforeByID[entry.Participant.TelegramID] = entry
+	}
+
+	var changes []ScoreChange
+
+	for _, entry := range after.Leaderboard {
+		old := beforeByID[entry.Participant.TelegramID]
+		if old.Score == entry.Score && old.Rank == entry.Rank {
+			continue
+		}
+
+		changes = append(changes, ScoreChange{
+			Participant: entry.Participant,
+			OldScore:    old.Score,
+			NewScore:    entry.Score,
+			OldRank:     old.Rank,
+			NewRank:     entry.Rank,
+		})
+	}
+
+	return changes
+}
@@ -0,0 +1,194 @@
+package engine
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// finishedRun проводит квиз из одного вопроса, где участник i выбирает вариант answers[i].
+func finishedRun(t *testing.T, engine *Engine, answers []int) *QuizRun {
+	t.Helper()
+
+	data := []byte(`{
+		"title": "Regrade Quiz",
+		"settings": {"time_per_question": 5},
+		"questions": [{"text": "Q1", "options": ["A", "B", "C"], "correct": 0, "points": 2}]
+	}`)
+
+	quiz, err := engine.LoadQuiz(data)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+
+	for i := range answers {
+		require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: int64(i + 1)}))
+	}
+
+	events, err := engine.StartQuiz(ctx, run.ID)
+	require.NoError(t, err)
+
+	defer drainEvents(events)
+
+	<-events
+
+	_, err = engine.RegradeQuestion(run.ID, 0, RegradeKey{AcceptAny: true})
+	assert.ErrorIs(t, err, ErrNotFinished)
+
+	for i, answerIdx := range answers {
+		require.NoError(t, engine.SubmitAnswer(ctx, run.ID, int64(i+1), 0, answerIdx))
+	}
+
+	<-events
+
+	return run
+}
+
+func scores(t *testing.T, engine *Engine, runID string) map[int64]int {
+	t.Helper()
+
+	results, err := engine.GetResults(runID)
+	require.NoError(t, err)
+
+	byID := make(map[int64]int)
+	for _, entry := range results.Leaderboard {
+		byID[entry.Participant.TelegramID] = entry.Score
+	}
+
+	return byID
+}
+
+func TestRegradeQuestion_NewKey(t *testing.T) {
+	engine := NewEngine()
+	run := finishedRun(t, engine, []int{0, 1, 2})
+
+	assert.Equal(t, map[int64]int{1: 2, 2: 0, 3: 0}, scores(t, engine, run.ID))
+
+	changes, err := engine.RegradeQuestion(run.ID, 0, RegradeKey{Correct: []int{0, 1}})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, int64(2), changes[0].Participant.TelegramID)
+	assert.Equal(t, 0, changes[0].OldScore)
+	assert.Equal(t, 2, changes[0].NewScore)
+
+	assert.Equal(t, map[int64]int{1: 2, 2: 2, 3: 0}, scores(t, engine, run.ID))
+
+	require.Len(t, run.Regrades, 1)
+	assert.Equal(t, 0, run.Regrades[0].QuestionIdx)
+	assert.False(t, run.Regrades[0].Answers[2][0].IsCorrect)
+	assert.NotNil(t, run.Regrades[0].Results)
+}
+
+func TestRegradeQuestion_KeepsEveryCorrectOption(t *testing.T) {
+	engine := NewEngine()
+	run := finishedRun(t, engine, []int{0})
+
+	_, err := engine.RegradeQuestion(run.ID, 0, RegradeKey{Correct: []int{2, 1}})
+	require.NoError(t, err)
+
+	question := engine.runIDToQuestions[run.ID][0]
+	assert.Equal(t, []int{2, 1}, question.CorrectOptions())
+
+	quiz := engine.quizzes[run.QuizID]
+	assert.Equal(t, []int{2, 1}, quiz.Questions[0].CorrectOptions())
+
+	// тренировка по квизу засчитывает оба варианта нового ключа
+	for _, letter := range []string{"B", "C"} {
+		session := NewPracticeSession(quiz, 1, false, time.Now())
+		session.ShowQuestion(time.Now())
+
+		feedback, err := session.Answer(letter, time.Now())
+		require.NoError(t, err)
+		assert.True(t, feedback.IsCorrect, letter)
+	}
+}
+
+func TestRegradeQuestion_AcceptAnyAndVoid(t *testing.T) {
+	engine := NewEngine()
+	run := finishedRun(t, engine, []int{0, 1})
+
+	_, err := engine.RegradeQuestion(run.ID, 0, RegradeKey{AcceptAny: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int{1: 2, 2: 2}, scores(t, engine, run.ID))
+
+	_, err = engine.RegradeQuestion(run.ID, 0, RegradeKey{Void: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int{1: 0, 2: 0}, scores(t, engine, run.ID))
+
+	assert.Len(t, run.Regrades, 2)
+}
+
+func TestRegradeQuestion_InvalidKey(t *testing.T) {
+	engine := NewEngine()
+	run := finishedRun(t, engine, []int{0})
+
+	_, err := engine.RegradeQuestion(run.ID, 0, RegradeKey{})
+	assert.ErrorIs(t, err, ErrInvalidRegradeKey)
+
+	_, err = engine.RegradeQuestion(run.ID, 0, RegradeKey{Void: true, AcceptAny: true})
+	assert.ErrorIs(t, err, ErrInvalidRegradeKey)
+
+	_, err = engine.RegradeQuestion(run.ID, 0, RegradeKey{Correct: []int{7}})
+	assert.ErrorIs(t, err, ErrInvalidAnswerIndex)
+
+	_, err = engine.RegradeQuestion(run.ID, 3, RegradeKey{Void: true})
+	assert.ErrorIs(t, err, ErrInvalidQuestionIndex)
+}
+
+func TestRegradeQuestion_ShuffledAnswers(t *testing.T) {
+	engine := NewEngine()
+
+	data := []byte(`{
+		"title": "Shuffled Regrade Quiz",
+		"settings": {"time_per_question": 5, "shuffle_answers": true},
+		"questions": [{"text": "Q1", "options": ["A", "B", "C", "D", "E", "F"], "correct": 0, "points": 2}]
+	}`)
+
+	quiz, err := engine.LoadQuiz(data)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 1}))
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 2}))
+
+	events, err := engine.StartQuiz(ctx, run.ID)
+	require.NoError(t, err)
+
+	defer drainEvents(events)
+
+	event := <-events
+	require.NoError(t, engine.ShuffleAnswers(&event))
+
+	shown := append([]string(nil), event.Question.Options...)
+
+	// участник 1 выбирает "B", участник 2 — "A", правильный по файлу
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, slices.Index(shown, "B")))
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 2, 0, slices.Index(shown, "A")))
+
+	<-events
+
+	assert.Equal(t, map[int64]int{1: 0, 2: 2}, scores(t, engine, run.ID))
+
+	// буква B в ключе — второй вариант файла, где бы он ни оказался после перемешивания
+	_, err = engine.RegradeQuestion(run.ID, 0, RegradeKey{Correct: []int{1}})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int{1: 2, 2: 0}, scores(t, engine, run.ID))
+
+	report, err := engine.GetParticipantReport(run.ID, 1)
+	require.NoError(t, err)
+
+	question := report.Items[0].Question
+	assert.Equal(t, "B", question.Options[question.Correct])
+	assert.Equal(t, []int{1}, run.Regrades[0].Key.Correct)
+}
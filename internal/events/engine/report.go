@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"fmt"
+)
+
+// GetParticipantReport возвращает подробный отчёт участника: выбранный и правильный вариант,
+// баллы и время ответа по каждому вопросу запуска.
+func (e *Engine) GetParticipantReport(runID string, participantID int64) (*ParticipantReport, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	activeQuizRun, ok := e.activeQuizzesRun[runID]
+	if !ok {
+		return nil, fmt.Errorf("quiz with runID: %s is not found", runID)
+	}
+
+	if activeQuizRun.Status != RunStatusFinished && activeQuizRun.Status != RunStatusGrading {
+		return nil, ErrNotFinished
+	}
+
+	if _, ok = activeQuizRun.Participants[participantID]; !ok {
+		return nil, ErrNotParticipant
+	}
+
+	results := e.buildResults(activeQuizRun)
+
+	report := &ParticipantReport{
+		RunID:     runID,
+		QuizTitle: results.QuizTitle,
+	}
+
+	for _, entry := range results.Leaderboard {
+		if entry.Participant.TelegramID == participantID {
+			report.Entry = entry
+			break
+		}
+	}
+
+	questions := e.runIDToQuestions[runID]
+	answers := activeQuizRun.Answers[participantID]
+	voided := voidedQuestions(activeQuizRun)
+
+	for i := range questions {
+		item := ReportItem{
+			QuestionIdx: i,
+			Question:    questions[i],
+			MaxPoints:   questionPoints(&questions[i]),
+		}
+		item.Question.Options = append([]string(nil), questions[i].Options...)
+
+		if voided[i] {
+			item.MaxPoints = 0
+		}
+
+		for j := range answers {
+			if answers[j].QuestionIdx == i {
+				answer := answers[j]
+				item.Answer = &answer
+
+				break
+			}
+		}
+
+		report.MaxScore += item.MaxPoints
+		report.Items = append(report.Items, item)
+	}
+
+	return report, nil
+}
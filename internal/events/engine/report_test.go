@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetParticipantReport(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Report",
+		"settings": {"time_per_question": 1},
+		"questions": [
+			{"text": "Q1", "options": ["A", "B"], "correct": 0, "points": 3, "explanation": "A"},
+			{"text": "Q2", "options": ["A", "B"], "correct": 1}
+		]
+	}`))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 1}))
+
+	events, err := engine.StartQuiz(ctx, run.ID)
+	require.NoError(t, err)
+
+	defer drainEvents(events)
+
+	<-events
+
+	_, err = engine.GetParticipantReport(run.ID, 1)
+	assert.ErrorIs(t, err, ErrNotFinished)
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, 0))
+
+	<-events
+	<-events
+
+	report, err := engine.GetParticipantReport(run.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Report", report.QuizTitle)
+	assert.Equal(t, 3, report.Entry.Score)
+	assert.Equal(t, 4, report.MaxScore)
+	require.Len(t, report.Items, 2)
+
+	first := report.Items[0]
+	require.NotNil(t, first.Answer)
+	assert.True(t, first.Answer.IsCorrect)
+	assert.Equal(t, 3, first.Answer.Points)
+	assert.GreaterOrEqual(t, first.Answer.ResponseTime, 50*time.Millisecond)
+	assert.Less(t, first.Answer.ResponseTime, time.Second)
+	assert.Equal(t, "A", first.Question.Explanation)
+
+	assert.Nil(t, report.Items[1].Answer)
+	assert.Equal(t, 1, report.Items[1].MaxPoints)
+
+	_, err = engine.GetParticipantReport(run.ID, 2)
+	assert.ErrorIs(t, err, ErrNotParticipant)
+}
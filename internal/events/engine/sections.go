@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// GetSectionStandings возвращает таблицу лидеров по разделу section:
+// участники упорядочены по баллам за раздел, при равенстве — по общему месту.
+func (e *Engine) GetSectionStandings(runID string, section string) ([]LeaderboardEntry, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	activeQuizRun, ok := e.activeQuizzesRun[runID]
+	if !ok || activeQuizRun.Status == RunStatusLobby {
+		return nil, fmt.Errorf("quiz with runID: %s is not started", runID)
+	}
+
+	results := e.buildResults(activeQuizRun)
+	if !slices.Contains(results.Sections, section) {
+		return nil, ErrUnknownSection
+	}
+
+	standings := results.Leaderboard
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].SectionScores[section] > standings[j].SectionScores[section]
+	})
+
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+
+	return standings, nil
+}
+
+// ContinueRun завершает перерыв между разделами.
+func (e *Engine) ContinueRun(runID string) error {
+	e.mu.RLock()
+	continueChan, ok := e.runIDToContinue[runID]
+	e.mu.RUnlock()
+
+	if !ok {
+		return ErrNoIntermission
+	}
+
+	select {
+	case continueChan <- struct{}{}:
+	default: // сигнал уже отправлен
+	}
+
+	return nil
+}
+
+// openIntermission открывает перерыв между разделами: с этого момента ContinueRun
+// принимает сигнал преподавателя.
+func (e *Engine) openIntermission(runID string) chan struct{} {
+	continueChan := make(chan struct{}, 1)
+
+	e.mu.Lock()
+	e.runIDToContinue[runID] = continueChan
+	e.mu.Unlock()
+
+	return continueChan
+}
+
+// waitIntermission ждёт конца перерыва между разделами: сигнала преподавателя
+// или истечения intermission секунд (если intermission > 0).
+// Возвращает false, если квиз нужно прервать.
+func (e *Engine) waitIntermission(
+	ctx context.Context,
+	runID string,
+	continueChan chan struct{},
+	intermission int,
+) bool {
+	e.mu.RLock()
+	quizErrChan := e.quizErrChan[runID]
+	e.mu.RUnlock()
+
+	defer func() {
+		e.mu.Lock()
+		delete(e.runIDToContinue, runID)
+		e.mu.Unlock()
+	}()
+
+	var timeout <-chan time.Time
+
+	if intermission > 0 {
+		timer := time.NewTimer(time.Duration(intermission) * time.Second)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	select {
+	case <-continueChan:
+		return true
+	case <-timeout:
+		return true
+	case <-quizErrChan:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
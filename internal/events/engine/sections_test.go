@@ -0,0 +1,164 @@
+package engine
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadQuiz_Sections(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Sections",
+		"settings": {"time_per_question": 5},
+		"sections": [
+			{"title": "Basics", "questions": [{"text": "Q1", "options": ["A", "B"], "correct": 0}]},
+			{"title": "Channels", "questions": [
+				{"text": "Q2", "options": ["A", "B"], "correct": 0},
+				{"text": "Q3", "options": ["A", "B"], "correct": 1}
+			]}
+		]
+	}`))
+	require.NoError(t, err)
+	require.Len(t, quiz.Questions, 3)
+	assert.Equal(t, "Basics", quiz.Questions[0].Section)
+	assert.Equal(t, "Channels", quiz.Questions[2].Section)
+
+	_, err = engine.LoadQuiz([]byte(`{
+		"title": "Both",
+		"settings": {"time_per_question": 5},
+		"questions": [{"text": "Q0", "options": ["A", "B"], "correct": 0}],
+		"sections": [{"title": "S", "questions": [{"text": "Q1", "options": ["A", "B"], "correct": 0}]}]
+	}`))
+	assert.Error(t, err)
+
+	_, err = engine.LoadQuiz([]byte(`{
+		"title": "Untitled",
+		"settings": {"time_per_question": 5},
+		"sections": [{"questions": [{"text": "Q1", "options": ["A", "B"], "correct": 0}]}]
+	}`))
+	assert.Error(t, err)
+}
+
+func TestQuizFlow_SectionsWithIntermission(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Rounds",
+		"settings": {"time_per_question": 5},
+		"sections": [
+			{"title": "Round 1", "questions": [{"text": "Q1", "options": ["A", "B"], "correct": 0}]},
+			{"title": "Round 2", "questions": [{"text": "Q2", "options": ["A", "B"], "correct": 1, "points": 3}]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 1}))
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 2}))
+
+	events, err := engine.StartQuiz(ctx, run.ID)
+	require.NoError(t, err)
+
+	defer drainEvents(events)
+
+	assert.ErrorIs(t, engine.ContinueRun(run.ID), ErrNoIntermission)
+
+	<-events
+
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, 0))
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 2, 0, 1))
+
+	event := <-events
+	require.Equal(t, EventTypeSectionFinished, event.Type)
+	assert.Equal(t, "Round 1", event.Section)
+	assert.False(t, event.LastSection)
+
+	standings, err := engine.GetSectionStandings(run.ID, "Round 1")
+	require.NoError(t, err)
+	require.Len(t, standings, 2)
+	assert.Equal(t, int64(1), standings[0].Participant.TelegramID)
+	assert.Equal(t, 1, standings[0].SectionScores["Round 1"])
+
+	_, err = engine.GetSectionStandings(run.ID, "Round 3")
+	assert.ErrorIs(t, err, ErrUnknownSection)
+
+	require.NoError(t, engine.ContinueRun(run.ID))
+
+	event = <-events
+	require.Equal(t, EventTypeQuestion, event.Type)
+	assert.Equal(t, 1, event.QuestionIdx)
+
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 1, 0))
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 2, 1, 1))
+
+	event = <-events
+	require.Equal(t, EventTypeSectionFinished, event.Type)
+	assert.True(t, event.LastSection)
+
+	event = <-events
+	require.Equal(t, EventTypeFinished, event.Type)
+
+	results, err := engine.GetResults(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Round 1", "Round 2"}, results.Sections)
+	assert.Equal(t, int64(2), results.Leaderboard[0].Participant.TelegramID)
+	assert.Equal(t, map[string]int{"Round 1": 0, "Round 2": 3}, results.Leaderboard[0].SectionScores)
+
+	csvData, err := engine.ExportCSV(run.ID)
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(strings.NewReader(string(csvData))).ReadAll()
+	require.NoError(t, err)
+
+	header := records[0]
+	assert.Equal(t, []string{"Section: Round 1", "Section: Round 2"}, header[len(header)-2:])
+	assert.Equal(t, []string{"0", "3"}, records[1][len(header)-2:])
+}
+
+func TestQuizFlow_IntermissionTimeout(t *testing.T) {
+	engine := NewEngine()
+
+	quiz, err := engine.LoadQuiz([]byte(`{
+		"title": "Rounds",
+		"settings": {"time_per_question": 5, "intermission": 1},
+		"sections": [
+			{"title": "Round 1", "questions": [{"text": "Q1", "options": ["A", "B"], "correct": 0}]},
+			{"title": "Round 2", "questions": [{"text": "Q2", "options": ["A", "B"], "correct": 1}]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	run, err := engine.StartRun(ctx, quiz)
+	require.NoError(t, err)
+	require.NoError(t, engine.JoinRun(ctx, run.ID, &Participant{TelegramID: 1}))
+
+	events, err := engine.StartQuiz(ctx, run.ID)
+	require.NoError(t, err)
+
+	defer drainEvents(events)
+
+	<-events
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 0, 0))
+
+	event := <-events
+	require.Equal(t, EventTypeSectionFinished, event.Type)
+
+	// преподаватель не нажал "Продолжить" — следующий раздел начнётся по таймеру
+	event = <-events
+	assert.Equal(t, EventTypeQuestion, event.Type)
+	assert.Equal(t, 1, event.QuestionIdx)
+
+	require.NoError(t, engine.SubmitAnswer(ctx, run.ID, 1, 1, 1))
+}
@@ -3,6 +3,7 @@ package engine
 import (
 	"context"
 	"errors"
+	"slices"
 	"time"
 )
 
@@ -13,9 +14,16 @@ type Quiz struct {
 	Title     string
 	Settings  Settings
 	Questions []Question
+	Sections  []Section // если заданы, вопросы собираются из разделов по порядку
 	CreatedAt time.Time
 }
 
+// Section — тематический раздел (раунд) квиза.
+type Section struct {
+	Title     string     `json:"title"`
+	Questions []Question `json:"questions"`
+}
+
 // Settings содержит настройки квиза.
 type Settings struct {
 	TimePerQuestion  int      `json:"time_per_question"`
@@ -23,17 +31,73 @@ type Settings struct {
 	ShuffleAnswers   bool     `json:"shuffle_answers"`
 	MaxParticipants  int      `json:"max_participants"`
 	Registration     []string `json:"registration"`
+	Intermission     int      `json:"intermission"` // перерыв между разделами в секундах, 0 — ждать преподавателя
+	AnswerMode       string   `json:"answer_mode"`  // способ ответа: AnswerModeText (по умолчанию), AnswerModeButtons или AnswerModePoll
 }
 
+// Способы ответа на вопросы.
+const (
+	AnswerModeText    = "text"    // участник отправляет букву варианта сообщением
+	AnswerModeButtons = "buttons" // варианты ответа — inline-кнопки под вопросом
+	AnswerModePoll    = "poll"    // вопрос с вариантами отправляется опросом-викториной Telegram
+)
+
 // Question представляет вопрос квиза.
 type Question struct {
-	Text        string   `json:"text"`
-	Options     []string `json:"options"`
-	Correct     int      `json:"correct"`
-	Explanation string   `json:"explanation"`
-	Points      int      `json:"points"`
-	Time        int      `json:"time"`
-	Shuffle     *bool    `json:"shuffle"`
+	Type        QuestionType `json:"type"`
+	Text        string       `json:"text"`
+	Options     []string     `json:"options"`
+	Correct     int          `json:"correct"`
+	Accepted    []int        `json:"accepted,omitempty"` // ещё варианты, которые засчитываются как правильные
+	Explanation string       `json:"explanation"`
+	Points      int          `json:"points"`
+	Time        int          `json:"time"`
+	Shuffle     *bool        `json:"shuffle"`
+	Hints       []Hint       `json:"hints"`
+	Tags        []string     `json:"tags"`    // темы вопроса для статистики студентов
+	Section     string       `json:"section"` // название раздела, заполняется при загрузке
+}
+
+// Hint — подсказка к вопросу. Её стоимость вычитается из баллов за вопрос.
+type Hint struct {
+	Text string `json:"text"`
+	Cost int    `json:"cost"`
+}
+
+// QuestionType — тип вопроса.
+type QuestionType string
+
+const (
+	QuestionTypeChoice QuestionType = "choice" // выбор варианта (по умолчанию)
+	QuestionTypeText   QuestionType = "text"   // открытый ответ, проверяется преподавателем
+)
+
+// IsOpen сообщает, является ли вопрос открытым (ответ текстом).
+func (q *Question) IsOpen() bool {
+	return q.Type == QuestionTypeText
+}
+
+// IsCorrectOption сообщает, засчитывается ли вариант answerIdx как правильный.
+func (q *Question) IsCorrectOption(answerIdx int) bool {
+	return answerIdx == q.Correct || slices.Contains(q.Accepted, answerIdx)
+}
+
+// CorrectOptions возвращает все правильные варианты: Correct, затем Accepted.
+func (q *Question) CorrectOptions() []int {
+	return append([]int{q.Correct}, q.Accepted...)
+}
+
+// Topics возвращает темы вопроса: теги, а если их нет — название раздела.
+func (q *Question) Topics() []string {
+	if len(q.Tags) > 0 {
+		return q.Tags
+	}
+
+	if q.Section != "" {
+		return []string{q.Section}
+	}
+
+	return nil
 }
 
 // QuizRun представляет запуск квиза.
@@ -45,15 +109,20 @@ type QuizRun struct { //nolint:revive
 	Answers      map[int64][]Answer
 	StartedAt    time.Time
 	FinishedAt   time.Time
+	Regrades     []Regrade // история перепроверок, исходные результаты хранятся для аудита
+	// HintsUsed — количество взятых подсказок: участник -> номер вопроса -> количество
+	HintsUsed map[int64]map[int]int
 }
 
 // RunStatus — статус запуска квиза.
 type RunStatus string
 
 const (
-	RunStatusLobby    RunStatus = "lobby"
-	RunStatusRunning  RunStatus = "running"
-	RunStatusFinished RunStatus = "finished"
+	RunStatusLobby     RunStatus = "lobby"
+	RunStatusRunning   RunStatus = "running"
+	RunStatusGrading   RunStatus = "grading" // вопросы закончились, есть непроверенные открытые ответы
+	RunStatusFinished  RunStatus = "finished"
+	RunStatusCancelled RunStatus = "cancelled" // запуск отменён преподавателем
 )
 
 // Participant представляет участника квиза.
@@ -62,34 +131,117 @@ type Participant struct {
 	Username   string
 	FirstName  string
 	LastName   string
-	RegData    map[string]string
+	RegData    map[string]string // значения полей Settings.Registration
 	JoinedAt   time.Time
 }
 
 // Answer представляет ответ участника на вопрос.
 type Answer struct {
+	QuestionIdx  int
+	AnswerIdx    int
+	IsCorrect    bool
+	Points       int
+	AnsweredAt   time.Time
+	ResponseTime time.Duration // время от показа вопроса до ответа
+	Text         string        // текст ответа на открытый вопрос
+	Pending      bool          // открытый ответ ещё не проверен преподавателем
+	Comment      string        // комментарий преподавателя к открытому ответу
+}
+
+// PendingAnswer — открытый ответ, ожидающий ручной проверки.
+type PendingAnswer struct {
+	ParticipantID int64
+	QuestionIdx   int
+	Question      *Question
+	Text          string
+	MaxPoints     int
+}
+
+// RegradeKey — новый ключ вопроса при перепроверке.
+// Должен быть задан ровно один способ: Correct, AcceptAny или Void.
+type RegradeKey struct {
+	Correct   []int // индексы вариантов в порядке файла квиза, которые засчитываются как правильные
+	AcceptAny bool  // засчитать любой ответ
+	Void      bool  // аннулировать вопрос: баллы за него не получает никто
+}
+
+// Regrade — запись о перепроверке вопроса в завершённом запуске.
+type Regrade struct {
 	QuestionIdx int
-	AnswerIdx   int
-	IsCorrect   bool
-	Points      int
-	AnsweredAt  time.Time
+	Key         RegradeKey
+	At          time.Time
+	Answers     map[int64][]Answer // ответы до перепроверки
+	Results     *QuizResults       // результаты до перепроверки
+}
+
+// ScoreChange — изменение результата участника после перепроверки.
+type ScoreChange struct {
+	Participant *Participant
+	OldScore    int
+	NewScore    int
+	OldRank     int
+	NewRank     int
 }
 
 // QuizResults содержит результаты квиза.
 type QuizResults struct { //nolint:revive
-	RunID       string
-	QuizTitle   string
-	Leaderboard []LeaderboardEntry
-	TotalTime   time.Duration
+	RunID        string
+	QuizTitle    string
+	Leaderboard  []LeaderboardEntry
+	TotalTime    time.Duration
+	Pending      int      // количество открытых ответов, ожидающих проверки
+	Sections     []string // названия разделов в порядке прохождения
+	Registration []string // поля регистрации из настроек квиза
 }
 
 // LeaderboardEntry — запись в таблице лидеров.
 type LeaderboardEntry struct {
-	Participant  *Participant
-	Score        int
-	CorrectCount int
-	TotalTime    time.Duration
-	Rank         int
+	Participant   *Participant
+	Score         int
+	CorrectCount  int
+	TotalTime     time.Duration
+	Rank          int
+	HintsUsed     map[int]int    // номер вопроса -> количество взятых подсказок
+	SectionScores map[string]int // название раздела -> баллы за раздел
+}
+
+// QuestionStat — ответы участников запуска на один вопрос.
+type QuestionStat struct {
+	QuestionIdx  int
+	Text         string
+	Participants int
+	Answered     int
+	Correct      int
+	Voided       bool // вопрос аннулирован перепроверкой
+}
+
+// LiveSnapshot — состояние идущего запуска для панели преподавателя.
+type LiveSnapshot struct {
+	QuestionIdx    int // -1, пока первый вопрос не показан
+	QuestionsCount int
+	Question       Question // копия текущего вопроса
+	TimeLeft       time.Duration
+	Participants   int
+	Answered       int                // сколько участников ответили на текущий вопрос
+	Distribution   []int              // сколько раз выбран каждый вариант, для открытого вопроса пусто
+	Top            []LeaderboardEntry // лучшие участники по текущим баллам
+}
+
+// ParticipantReport — подробный отчёт участника: ответ на каждый вопрос квиза.
+type ParticipantReport struct {
+	RunID     string
+	QuizTitle string
+	Entry     LeaderboardEntry
+	MaxScore  int
+	Items     []ReportItem
+}
+
+// ReportItem — строка отчёта по одному вопросу.
+type ReportItem struct {
+	QuestionIdx int
+	Question    Question
+	Answer      *Answer // nil, если участник не ответил
+	MaxPoints   int
 }
 
 // QuizEngine определяет основной интерфейс для работы с квизами.
@@ -115,6 +267,7 @@ type QuizEngine interface { //nolint:revive
 
 	// SubmitAnswer регистрирует ответ участника по индексу (0-based).
 	SubmitAnswer(
+		ctx context.Context,
 		runID string,
 		participantID int64,
 		questionIdx int,
@@ -124,11 +277,41 @@ type QuizEngine interface { //nolint:revive
 	// SubmitAnswerByLetter регистрирует ответ участника по букве (A, B, C, D, E, F).
 	// Это основной способ ответа — участник пишет букву в чат.
 	SubmitAnswerByLetter(
+		ctx context.Context,
 		runID string,
 		participantID int64,
 		letter string,
 	) error
 
+	// SubmitTextAnswer регистрирует ответ участника на текущий открытый вопрос.
+	SubmitTextAnswer(
+		ctx context.Context,
+		runID string,
+		participantID int64,
+		text string,
+	) error
+
+	// UseHint выдаёт участнику следующую подсказку к текущему вопросу questionIdx.
+	UseHint(
+		ctx context.Context,
+		runID string,
+		participantID int64,
+		questionIdx int,
+	) (*Hint, error)
+
+	// GetPendingAnswers возвращает открытые ответы, ожидающие проверки.
+	GetPendingAnswers(runID string) ([]PendingAnswer, error)
+
+	// GradeAnswer выставляет баллы за открытый ответ.
+	// Возвращает количество ответов, которые ещё ожидают проверки.
+	GradeAnswer(
+		runID string,
+		participantID int64,
+		questionIdx int,
+		points int,
+		comment string,
+	) (int, error)
+
 	// GetCurrentQuestion возвращает текущий номер вопроса для участника.
 	// Возвращает -1 если квиз не запущен или завершён.
 	GetCurrentQuestion(runID string) int
@@ -136,11 +319,51 @@ type QuizEngine interface { //nolint:revive
 	// GetResults возвращает результаты завершённого квиза.
 	GetResults(runID string) (*QuizResults, error)
 
+	// GetSectionStandings возвращает таблицу лидеров по разделу section.
+	// Доступна во время квиза, как только раздел пройден.
+	GetSectionStandings(runID string, section string) ([]LeaderboardEntry, error)
+
+	// ContinueRun завершает перерыв между разделами и запускает следующий раздел.
+	ContinueRun(runID string) error
+
+	// RegradeQuestion пересчитывает баллы за вопрос завершённого запуска по новому ключу.
+	// Возвращает изменения результатов затронутых участников.
+	RegradeQuestion(runID string, questionIdx int, key RegradeKey) ([]ScoreChange, error)
+
 	// ExportCSV экспортирует результаты в формате CSV.
 	ExportCSV(runID string) ([]byte, error)
 
 	// GetRun возвращает запуск по ID.
 	GetRun(runID string) (*QuizRun, error)
+
+	// GetParticipantReport возвращает подробный отчёт участника по завершённому запуску.
+	GetParticipantReport(runID string, participantID int64) (*ParticipantReport, error)
+
+	// GetRunQuestions возвращает копию вопросов запуска с учётом перемешивания вариантов.
+	GetRunQuestions(runID string) ([]Question, error)
+
+	// GetMissedQuestions возвращает номера вопросов, на которые участник ответил неверно или не ответил.
+	// Доступно после завершения квиза.
+	GetMissedQuestions(runID string) (map[int64][]int, error)
+
+	// GetQuestionStats возвращает количество ответивших и ответивших правильно по каждому вопросу.
+	// Доступно после завершения квиза.
+	GetQuestionStats(runID string) ([]QuestionStat, error)
+
+	// CancelRun отменяет запуск в лобби или во время квиза и останавливает рассылку вопросов.
+	CancelRun(runID string) error
+
+	// EvictRun удаляет из памяти завершённый или отменённый запуск.
+	EvictRun(runID string) error
+
+	// ArchiveRun возвращает завершённый запуск в виде, который можно сохранить и вернуть в память через RestoreRun.
+	ArchiveRun(runID string) ([]byte, error)
+
+	// RestoreRun возвращает в память запуск, сохранённый ArchiveRun, и возвращает его квиз.
+	RestoreRun(data []byte) (*Quiz, error)
+
+	// GetLiveSnapshot возвращает состояние идущего запуска и topN лучших участников.
+	GetLiveSnapshot(runID string, topN int) (*LiveSnapshot, error)
 }
 
 // QuizEvent представляет событие квиза.
@@ -149,6 +372,8 @@ type QuizEvent struct { //nolint:revive
 	QuestionIdx int
 	Question    *Question
 	TimeLeft    time.Duration
+	Section     string // название раздела для EventTypeSectionFinished
+	LastSection bool   // после раздела вопросов больше нет, перерыва не будет
 }
 
 // EventType — тип события квиза.
@@ -158,6 +383,8 @@ const (
 	EventTypeQuestion EventType = "question"
 	EventTypeTimeUp   EventType = "time_up"
 	EventTypeFinished EventType = "finished"
+	// EventTypeSectionFinished — раздел пройден, начинается перерыв.
+	EventTypeSectionFinished EventType = "section_finished"
 )
 
 // MaxCountOfEvents - лимит событий в квизе.
@@ -165,16 +392,34 @@ const MaxCountOfEvents int64 = 1000
 
 // Ошибки при работе с квизом.
 var (
-	ErrNilQuiz = errors.New("quiz object is nil")
-	ErrNilParticipant = errors.New("participant object is nil")
-	ErrNoRunLobby = errors.New("lobby of current events does not launched")
-	ErrLobbyFull = errors.New("lobby has reached maximum capacity")
-	ErrRepeatedJoin = errors.New("participant already joined")
-	ErrNoRunningStatus = errors.New(`cannot start events, it is not in status "lobby"`)
-	ErrNoQuestionType = errors.New("event type must be a question type")
+	ErrNilQuiz              = errors.New("quiz object is nil")
+	ErrNilParticipant       = errors.New("participant object is nil")
+	ErrNoRunLobby           = errors.New("lobby of current events does not launched")
+	ErrLobbyFull            = errors.New("lobby has reached maximum capacity")
+	ErrRepeatedJoin         = errors.New("participant already joined")
+	ErrNoRunningStatus      = errors.New(`cannot start events, it is not in status "lobby"`)
+	ErrNoQuestionType       = errors.New("event type must be a question type")
 	ErrInvalidQuestionIndex = errors.New("invalid index of question")
-	ErrInvalidAnswerIndex = errors.New("invalid index of answer")
+	ErrInvalidAnswerIndex   = errors.New("invalid index of answer")
 	ErrConvertLetterToIndex = errors.New("cannot convert letter to index, invalid input")
+	ErrOpenQuestion         = errors.New("question expects a text answer")
+	ErrNotOpenQuestion      = errors.New("question does not accept text answers")
+	ErrEmptyTextAnswer      = errors.New("text answer is empty")
+	ErrNoPendingAnswer      = errors.New("no answer awaiting grading")
+	ErrInvalidPoints        = errors.New("points are out of range")
+	ErrInvalidRegradeKey    = errors.New("regrade key must set exactly one of correct, accept any or void")
+	ErrNotFinished          = errors.New("run is not finished")
+	ErrStaleQuestion        = errors.New("question is no longer active")
+	ErrAlreadyAnswered      = errors.New("participant already answered the question")
+	ErrNoMoreHints          = errors.New("no more hints for the question")
+	ErrNoIntermission       = errors.New("run is not on intermission")
+	ErrNotCancellable       = errors.New("only a run in lobby or in progress can be cancelled")
+	ErrRunCancelled         = errors.New("run is cancelled")
+	ErrRunActive            = errors.New("run is still active")
+	ErrUnknownSection       = errors.New("unknown section")
+	ErrNotParticipant       = errors.New("user did not take part in the run")
+	ErrNotRunning           = errors.New("run is not in progress")
+	ErrRegistrationRequired = errors.New("participant has not filled registration field")
 )
 
 // AnswerLetters — допустимые буквы для ответов (A-F для до 6 вариантов).
@@ -1,6 +1,54 @@
 package engine
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+)
+
+// Ограничения Telegram на опрос-викторину.
+const (
+	maxPollQuestionLen = 300
+	maxPollOptions     = 10
+	maxPollOptionLen   = 100
+)
+
+// flattenSections собирает вопросы квиза из разделов, проставляя каждому вопросу название раздела.
+func flattenSections(quiz *Quiz) error {
+	if len(quiz.Sections) == 0 {
+		return nil
+	}
+
+	if len(quiz.Questions) != 0 {
+		return fmt.Errorf("use either questions or sections, not both")
+	}
+
+	titles := make(map[string]struct{}, len(quiz.Sections))
+
+	for i, section := range quiz.Sections {
+		if section.Title == "" {
+			return fmt.Errorf("missing title of %d section", i)
+		}
+
+		if _, ok := titles[section.Title]; ok {
+			return fmt.Errorf("duplicate title of %d section", i)
+		}
+
+		titles[section.Title] = struct{}{}
+
+		if len(section.Questions) == 0 {
+			return fmt.Errorf("need at least one question in %d section", i)
+		}
+
+		for _, question := range section.Questions {
+			question.Section = section.Title
+			quiz.Questions = append(quiz.Questions, question)
+		}
+	}
+
+	return nil
+}
 
 // isCorrectQuiz проверяет на корректность структуру квиза
 func isCorrectQuiz(quiz *Quiz) error {
@@ -12,6 +60,26 @@ func isCorrectQuiz(quiz *Quiz) error {
 		return fmt.Errorf("missing field time_per_question")
 	}
 
+	if quiz.Settings.Intermission < 0 {
+		return fmt.Errorf("intermission must not be negative")
+	}
+
+	for i, field := range quiz.Settings.Registration {
+		if field == "" {
+			return fmt.Errorf("empty name of %d registration field", i)
+		}
+
+		if slices.Contains(quiz.Settings.Registration[:i], field) {
+			return fmt.Errorf("duplicate registration field %q", field)
+		}
+	}
+
+	switch quiz.Settings.AnswerMode {
+	case "", AnswerModeText, AnswerModeButtons, AnswerModePoll:
+	default:
+		return fmt.Errorf("unknown answer_mode %q", quiz.Settings.AnswerMode)
+	}
+
 	if quiz.Questions == nil {
 		return fmt.Errorf("missing field questions")
 	}
@@ -25,6 +93,28 @@ func isCorrectQuiz(quiz *Quiz) error {
 			return fmt.Errorf("missing field text of %d question", i)
 		}
 
+		if question.Points < 0 {
+			return fmt.Errorf("points must not be negative in %d question", i)
+		}
+
+		for j, hint := range question.Hints {
+			if hint.Text == "" {
+				return fmt.Errorf("missing text of %d hint in %d question", j, i)
+			}
+
+			if hint.Cost < 0 {
+				return fmt.Errorf("cost of %d hint in %d question must not be negative", j, i)
+			}
+		}
+
+		switch question.Type {
+		case "", QuestionTypeChoice:
+		case QuestionTypeText:
+			continue // у открытого вопроса нет вариантов ответа
+		default:
+			return fmt.Errorf("unknown type %q of %d question", question.Type, i)
+		}
+
 		if question.Options == nil {
 			return fmt.Errorf("missing field options of %d question", i)
 		}
@@ -40,6 +130,41 @@ func isCorrectQuiz(quiz *Quiz) error {
 		if question.Correct >= len(question.Options) {
 			return fmt.Errorf("index of correct answer in %d question is out of range", i)
 		}
+
+		for _, idx := range question.Accepted {
+			if idx < 0 || idx >= len(question.Options) {
+				return fmt.Errorf("index of accepted answer in %d question is out of range", i)
+			}
+		}
+
+		if quiz.Settings.AnswerMode == AnswerModePoll {
+			if err := isCorrectPollQuestion(&question); err != nil {
+				return fmt.Errorf("%w in %d question", err, i)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isCorrectPollQuestion проверяет, что вопрос с вариантами можно отправить опросом Telegram.
+func isCorrectPollQuestion(question *Question) error {
+	if utf8.RuneCountInString(question.Text) > maxPollQuestionLen {
+		return fmt.Errorf("text is longer than %d characters for poll", maxPollQuestionLen)
+	}
+
+	if len(question.Options) > maxPollOptions {
+		return fmt.Errorf("more than %d options for poll", maxPollOptions)
+	}
+
+	if len(question.Accepted) > 0 {
+		return errors.New("poll accepts only one correct option")
+	}
+
+	for j, option := range question.Options {
+		if utf8.RuneCountInString(option) > maxPollOptionLen {
+			return fmt.Errorf("%d option is longer than %d characters for poll", j, maxPollOptionLen)
+		}
 	}
 
 	return nil
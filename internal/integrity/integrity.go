@@ -0,0 +1,316 @@
+// Package integrity ищет в ответах завершённого запуска признаки списывания: пары участников с почти
+// одинаковыми ответами и временем ответа, ответы быстрее, чем можно прочитать вопрос, и группы участников,
+// вошедших в запуск в одну и ту же секунду. Анализ только подсвечивает подозрительное для преподавателя
+// и никогда не меняет баллы.
+package integrity
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+)
+
+// Config — пороги анализа.
+type Config struct {
+	MinCommonAnswers int           // минимум общих вопросов с выбором, на которые ответили оба участника пары
+	Similarity       float64       // доля совпавших ответов и доля синхронных ответов, начиная с которой пара подозрительна
+	TimingTolerance  time.Duration // ответы пары считаются синхронными, если разница во времени не больше
+	MinSharedWrong   int           // минимум одинаковых неверных ответов: совпадение верных ответов само по себе не улика
+
+	MinReadTime    time.Duration // время, без которого нельзя ответить даже на самый короткий вопрос
+	ReadingSpeed   float64       // скорость чтения текста вопроса и вариантов, символов в секунду
+	MinFastAnswers int           // сколько слишком быстрых ответов нужно, чтобы участник попал в отчёт
+
+	JoinWindow   time.Duration // участники, вошедшие в пределах этого окна, считаются вошедшими одновременно
+	MinJoinGroup int           // минимальный размер группы одновременно вошедших
+}
+
+// DefaultConfig возвращает пороги по умолчанию. Они подобраны так, чтобы не подсвечивать
+// сильных студентов, которые просто быстро и верно отвечают.
+func DefaultConfig() Config {
+	return Config{
+		MinCommonAnswers: 5,
+		Similarity:       0.9,
+		TimingTolerance:  2 * time.Second,
+		MinSharedWrong:   1,
+		MinReadTime:      time.Second,
+		ReadingSpeed:     40,
+		MinFastAnswers:   2,
+		JoinWindow:       time.Second,
+		MinJoinGroup:     3,
+	}
+}
+
+// ConfigFromEnv возвращает DefaultConfig, переопределённый переменными окружения:
+// INTEGRITY_MIN_COMMON_ANSWERS, INTEGRITY_SIMILARITY, INTEGRITY_TIMING_TOLERANCE, INTEGRITY_MIN_SHARED_WRONG,
+// INTEGRITY_MIN_READ_TIME, INTEGRITY_READING_SPEED, INTEGRITY_MIN_FAST_ANSWERS, INTEGRITY_JOIN_WINDOW
+// и INTEGRITY_MIN_JOIN_GROUP (длительности в формате time.ParseDuration).
+func ConfigFromEnv(lookup func(string) (string, bool)) (Config, error) {
+	cfg := DefaultConfig()
+
+	floats := map[string]*float64{
+		"INTEGRITY_SIMILARITY":    &cfg.Similarity,
+		"INTEGRITY_READING_SPEED": &cfg.ReadingSpeed,
+	}
+	ints := map[string]*int{
+		"INTEGRITY_MIN_COMMON_ANSWERS": &cfg.MinCommonAnswers,
+		"INTEGRITY_MIN_SHARED_WRONG":   &cfg.MinSharedWrong,
+		"INTEGRITY_MIN_FAST_ANSWERS":   &cfg.MinFastAnswers,
+		"INTEGRITY_MIN_JOIN_GROUP":     &cfg.MinJoinGroup,
+	}
+	durations := map[string]*time.Duration{
+		"INTEGRITY_TIMING_TOLERANCE": &cfg.TimingTolerance,
+		"INTEGRITY_MIN_READ_TIME":    &cfg.MinReadTime,
+		"INTEGRITY_JOIN_WINDOW":      &cfg.JoinWindow,
+	}
+
+	for name, value := range floats {
+		if raw, ok := lookup(name); ok {
+			parsed, err := strconv.ParseFloat(raw, 64)
+			if err != nil || parsed <= 0 {
+				return Config{}, fmt.Errorf("invalid %s: %q", name, raw)
+			}
+
+			*value = parsed
+		}
+	}
+
+	if cfg.Similarity > 1 {
+		return Config{}, fmt.Errorf("invalid INTEGRITY_SIMILARITY: %v is greater than 1", cfg.Similarity)
+	}
+
+	for name, value := range ints {
+		if raw, ok := lookup(name); ok {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 0 {
+				return Config{}, fmt.Errorf("invalid %s: %q", name, raw)
+			}
+
+			*value = parsed
+		}
+	}
+
+	for name, value := range durations {
+		if raw, ok := lookup(name); ok {
+			parsed, err := time.ParseDuration(raw)
+			if err != nil || parsed < 0 {
+				return Config{}, fmt.Errorf("invalid %s: %q", name, raw)
+			}
+
+			*value = parsed
+		}
+	}
+
+	return cfg, nil
+}
+
+// SimilarPair — пара участников с почти одинаковыми ответами.
+type SimilarPair struct {
+	First, Second *engine.Participant
+	Common        int // вопросов, на которые ответили оба
+	Same          int // из них с одинаковым ответом
+	SameWrong     int // из них с одинаковым неверным ответом
+	Synced        int // из одинаковых ответов — отправленных почти одновременно
+}
+
+// FastAnswer — ответ, отправленный быстрее, чем можно прочитать вопрос.
+type FastAnswer struct {
+	QuestionIdx  int
+	ResponseTime time.Duration
+	MinTime      time.Duration // минимальное правдоподобное время для этого вопроса
+}
+
+// FastAnswerer — участник со слишком быстрыми ответами.
+type FastAnswerer struct {
+	Participant *engine.Participant
+	Answers     []FastAnswer
+}
+
+// JoinBurst — группа участников, вошедших в запуск почти одновременно.
+type JoinBurst struct {
+	At           time.Time
+	Participants []*engine.Participant
+}
+
+// Report — результат анализа запуска.
+type Report struct {
+	Pairs      []SimilarPair
+	Fast       []FastAnswerer
+	JoinBursts []JoinBurst
+}
+
+// Empty сообщает, что ничего подозрительного не найдено.
+func (r *Report) Empty() bool {
+	return len(r.Pairs) == 0 && len(r.Fast) == 0 && len(r.JoinBursts) == 0
+}
+
+// Analyze анализирует ответы запуска. Вопросы передаются в том порядке, в котором их видели участники.
+func Analyze(run *engine.QuizRun, questions []engine.Question, cfg Config) *Report {
+	participants := sortedParticipants(run)
+
+	return &Report{
+		Pairs:      similarPairs(run, questions, participants, cfg),
+		Fast:       fastAnswerers(run, questions, participants, cfg),
+		JoinBursts: joinBursts(participants, cfg),
+	}
+}
+
+// sortedParticipants возвращает участников по времени входа, чтобы отчёт не зависел от порядка обхода map.
+func sortedParticipants(run *engine.QuizRun) []*engine.Participant {
+	participants := make([]*engine.Participant, 0, len(run.Participants))
+	for _, participant := range run.Participants {
+		participants = append(participants, participant)
+	}
+
+	slices.SortFunc(participants, func(a, b *engine.Participant) int {
+		if c := a.JoinedAt.Compare(b.JoinedAt); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.TelegramID, b.TelegramID)
+	})
+
+	return participants
+}
+
+// similarPairs ищет пары с почти одинаковыми ответами на вопросы с выбором.
+func similarPairs(
+	run *engine.QuizRun,
+	questions []engine.Question,
+	participants []*engine.Participant,
+	cfg Config,
+) []SimilarPair {
+	choices := make(map[int64]map[int]engine.Answer, len(participants))
+
+	for _, participant := range participants {
+		byQuestion := make(map[int]engine.Answer)
+
+		for _, answer := range run.Answers[participant.TelegramID] {
+			if answer.QuestionIdx >= len(questions) || questions[answer.QuestionIdx].IsOpen() || answer.AnswerIdx < 0 {
+				continue
+			}
+
+			byQuestion[answer.QuestionIdx] = answer
+		}
+
+		choices[participant.TelegramID] = byQuestion
+	}
+
+	var pairs []SimilarPair
+
+	for i, first := range participants {
+		for _, second := range participants[i+1:] {
+			pair := SimilarPair{First: first, Second: second}
+
+			for questionIdx, a := range choices[first.TelegramID] {
+				b, ok := choices[second.TelegramID][questionIdx]
+				if !ok {
+					continue
+				}
+
+				pair.Common++
+
+				if a.AnswerIdx != b.AnswerIdx {
+					continue
+				}
+
+				pair.Same++
+
+				if !a.IsCorrect {
+					pair.SameWrong++
+				}
+
+				if a.AnsweredAt.Sub(b.AnsweredAt).Abs() <= cfg.TimingTolerance {
+					pair.Synced++
+				}
+			}
+
+			if pair.Common == 0 || pair.Common < cfg.MinCommonAnswers || pair.SameWrong < cfg.MinSharedWrong {
+				continue
+			}
+
+			common := float64(pair.Common)
+			if float64(pair.Same)/common >= cfg.Similarity && float64(pair.Synced)/common >= cfg.Similarity {
+				pairs = append(pairs, pair)
+			}
+		}
+	}
+
+	return pairs
+}
+
+// fastAnswerers ищет участников, которые несколько раз ответили быстрее, чем можно прочитать вопрос.
+func fastAnswerers(
+	run *engine.QuizRun,
+	questions []engine.Question,
+	participants []*engine.Participant,
+	cfg Config,
+) []FastAnswerer {
+	var result []FastAnswerer
+
+	for _, participant := range participants {
+		var fast []FastAnswer
+
+		for _, answer := range run.Answers[participant.TelegramID] {
+			if answer.QuestionIdx >= len(questions) {
+				continue
+			}
+
+			minTime := MinReadTime(&questions[answer.QuestionIdx], cfg)
+			if answer.ResponseTime < minTime {
+				fast = append(fast, FastAnswer{
+					QuestionIdx:  answer.QuestionIdx,
+					ResponseTime: answer.ResponseTime,
+					MinTime:      minTime,
+				})
+			}
+		}
+
+		if len(fast) > 0 && len(fast) >= cfg.MinFastAnswers {
+			slices.SortFunc(fast, func(a, b FastAnswer) int { return a.QuestionIdx - b.QuestionIdx })
+			result = append(result, FastAnswerer{Participant: participant, Answers: fast})
+		}
+	}
+
+	return result
+}
+
+// MinReadTime возвращает минимальное правдоподобное время ответа на вопрос:
+// базовое время плюс время на чтение текста вопроса и всех вариантов.
+func MinReadTime(question *engine.Question, cfg Config) time.Duration {
+	chars := utf8.RuneCountInString(question.Text)
+	for _, option := range question.Options {
+		chars += utf8.RuneCountInString(option)
+	}
+
+	return cfg.MinReadTime + time.Duration(float64(chars)/cfg.ReadingSpeed*float64(time.Second))
+}
+
+// joinBursts ищет группы участников, вошедших в пределах JoinWindow от первого в группе.
+// Участники отсортированы по времени входа.
+func joinBursts(participants []*engine.Participant, cfg Config) []JoinBurst {
+	var bursts []JoinBurst
+
+	for start := 0; start < len(participants); {
+		end := start + 1
+		for end < len(participants) && participants[end].JoinedAt.Sub(participants[start].JoinedAt) <= cfg.JoinWindow {
+			end++
+		}
+
+		if size := end - start; size > 1 && size >= cfg.MinJoinGroup {
+			bursts = append(bursts, JoinBurst{
+				At:           participants[start].JoinedAt,
+				Participants: slices.Clone(participants[start:end]),
+			})
+		}
+
+		start = end
+	}
+
+	return bursts
+}
@@ -0,0 +1,186 @@
+package integrity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+)
+
+var start = time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC)
+
+// choiceQuestions возвращает n вопросов с выбором, правильный вариант — A.
+func choiceQuestions(n int) []engine.Question {
+	questions := make([]engine.Question, n)
+	for i := range questions {
+		questions[i] = engine.Question{Text: "Вопрос", Options: []string{"да", "нет"}, Correct: 0}
+	}
+
+	return questions
+}
+
+// answers строит ответы участника: answerIdx[i] на i-й вопрос через offset после начала вопроса.
+// Вопросы идут раз в минуту.
+func answers(answerIdx []int, offset time.Duration) []engine.Answer {
+	result := make([]engine.Answer, len(answerIdx))
+	for i, idx := range answerIdx {
+		result[i] = engine.Answer{
+			QuestionIdx:  i,
+			AnswerIdx:    idx,
+			IsCorrect:    idx == 0,
+			AnsweredAt:   start.Add(time.Duration(i)*time.Minute + offset),
+			ResponseTime: offset,
+		}
+	}
+
+	return result
+}
+
+func newRun(participants ...*engine.Participant) *engine.QuizRun {
+	run := &engine.QuizRun{
+		Participants: make(map[int64]*engine.Participant),
+		Answers:      make(map[int64][]engine.Answer),
+	}
+
+	for _, participant := range participants {
+		run.Participants[participant.TelegramID] = participant
+	}
+
+	return run
+}
+
+func TestAnalyze_SimilarPairs(t *testing.T) {
+	alice := &engine.Participant{TelegramID: 1, JoinedAt: start}
+	bob := &engine.Participant{TelegramID: 2, JoinedAt: start.Add(10 * time.Second)}
+	carol := &engine.Participant{TelegramID: 3, JoinedAt: start.Add(20 * time.Second)}
+	dave := &engine.Participant{TelegramID: 4, JoinedAt: start.Add(30 * time.Second)}
+
+	run := newRun(alice, bob, carol, dave)
+	// Алиса и Боб ошиблись одинаково и отвечали синхронно
+	run.Answers[1] = answers([]int{0, 1, 0, 1, 0}, 10*time.Second)
+	run.Answers[2] = answers([]int{0, 1, 0, 1, 0}, 11*time.Second)
+	// Кэрол ответила так же, но в своём темпе
+	run.Answers[3] = answers([]int{0, 1, 0, 1, 0}, 30*time.Second)
+	// Дэйв ответил на всё верно синхронно с Кэрол — совпадение верных ответов не улика
+	run.Answers[4] = answers([]int{0, 0, 0, 0, 0}, 30*time.Second)
+
+	report := Analyze(run, choiceQuestions(5), DefaultConfig())
+
+	require.Len(t, report.Pairs, 1)
+	pair := report.Pairs[0]
+	assert.Equal(t, alice, pair.First)
+	assert.Equal(t, bob, pair.Second)
+	assert.Equal(t, 5, pair.Common)
+	assert.Equal(t, 5, pair.Same)
+	assert.Equal(t, 2, pair.SameWrong)
+	assert.Equal(t, 5, pair.Synced)
+}
+
+func TestAnalyze_SimilarPairsSkipsShortOverlap(t *testing.T) {
+	run := newRun(
+		&engine.Participant{TelegramID: 1, JoinedAt: start},
+		&engine.Participant{TelegramID: 2, JoinedAt: start.Add(time.Minute)},
+	)
+	run.Answers[1] = answers([]int{1, 1, 1}, 10*time.Second)
+	run.Answers[2] = answers([]int{1, 1, 1}, 10*time.Second)
+
+	report := Analyze(run, choiceQuestions(3), DefaultConfig())
+
+	assert.Empty(t, report.Pairs)
+	assert.True(t, report.Empty())
+}
+
+func TestAnalyze_FastAnswers(t *testing.T) {
+	questions := []engine.Question{
+		{Text: "Короткий", Options: []string{"да", "нет"}},
+		{Text: strings.Repeat("а", 200), Options: []string{"да", "нет"}},
+		{Text: strings.Repeat("а", 200), Options: []string{"да", "нет"}},
+	}
+
+	run := newRun(
+		&engine.Participant{TelegramID: 1, JoinedAt: start},
+		&engine.Participant{TelegramID: 2, JoinedAt: start.Add(time.Minute)},
+	)
+	run.Answers[1] = []engine.Answer{
+		{QuestionIdx: 0, ResponseTime: 1500 * time.Millisecond},
+		{QuestionIdx: 1, ResponseTime: 2 * time.Second},
+		{QuestionIdx: 2, ResponseTime: 3 * time.Second},
+	}
+	// один быстрый ответ — случайность, а не повод для отчёта
+	run.Answers[2] = []engine.Answer{
+		{QuestionIdx: 1, ResponseTime: 2 * time.Second},
+		{QuestionIdx: 2, ResponseTime: 10 * time.Second},
+	}
+
+	report := Analyze(run, questions, DefaultConfig())
+
+	require.Len(t, report.Fast, 1)
+	assert.Equal(t, int64(1), report.Fast[0].Participant.TelegramID)
+	require.Len(t, report.Fast[0].Answers, 2)
+	assert.Equal(t, 1, report.Fast[0].Answers[0].QuestionIdx)
+	assert.Equal(t, 2, report.Fast[0].Answers[1].QuestionIdx)
+}
+
+func TestMinReadTime(t *testing.T) {
+	cfg := DefaultConfig()
+	question := &engine.Question{Text: "Столица Франции?", Options: []string{"Париж", "Лион"}}
+
+	// 16 символов вопроса и 9 символов вариантов при 40 символах в секунду
+	assert.Equal(t, time.Second+625*time.Millisecond, MinReadTime(question, cfg))
+}
+
+func TestAnalyze_JoinBursts(t *testing.T) {
+	run := newRun(
+		&engine.Participant{TelegramID: 1, JoinedAt: start},
+		&engine.Participant{TelegramID: 2, JoinedAt: start.Add(300 * time.Millisecond)},
+		&engine.Participant{TelegramID: 3, JoinedAt: start.Add(900 * time.Millisecond)},
+		&engine.Participant{TelegramID: 4, JoinedAt: start.Add(1500 * time.Millisecond)},
+		&engine.Participant{TelegramID: 5, JoinedAt: start.Add(time.Minute)},
+		&engine.Participant{TelegramID: 6, JoinedAt: start.Add(time.Minute + 100*time.Millisecond)},
+	)
+
+	report := Analyze(run, nil, DefaultConfig())
+
+	require.Len(t, report.JoinBursts, 1)
+	assert.Equal(t, start, report.JoinBursts[0].At)
+	require.Len(t, report.JoinBursts[0].Participants, 3)
+	assert.Equal(t, int64(3), report.JoinBursts[0].Participants[2].TelegramID)
+
+	cfg := DefaultConfig()
+	cfg.MinJoinGroup = 2
+
+	assert.Len(t, Analyze(run, nil, cfg).JoinBursts, 2)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	env := map[string]string{
+		"INTEGRITY_SIMILARITY":       "0.8",
+		"INTEGRITY_MIN_SHARED_WRONG": "0",
+		"INTEGRITY_JOIN_WINDOW":      "500ms",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+
+		return value, ok
+	}
+
+	cfg, err := ConfigFromEnv(lookup)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.8, cfg.Similarity, 1e-9)
+	assert.Equal(t, 0, cfg.MinSharedWrong)
+	assert.Equal(t, 500*time.Millisecond, cfg.JoinWindow)
+	assert.Equal(t, DefaultConfig().MinCommonAnswers, cfg.MinCommonAnswers)
+
+	env["INTEGRITY_SIMILARITY"] = "1.5"
+	_, err = ConfigFromEnv(lookup)
+	require.Error(t, err)
+
+	env["INTEGRITY_SIMILARITY"] = "0.8"
+	env["INTEGRITY_MIN_READ_TIME"] = "soon"
+	_, err = ConfigFromEnv(lookup)
+	require.Error(t, err)
+}
@@ -0,0 +1,276 @@
+// Package ratelimit защищает бота от флуда: корзины токенов ограничивают частоту обновлений от каждого
+// пользователя и из каждого чата, а тех, кто раз за разом упирается в лимит, лимитер временно глушит.
+// Время берётся из переданных часов, поэтому лимитер проверяется в тестах без реального ожидания.
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Clock возвращает текущее время.
+type Clock func() time.Time
+
+// Limit — параметры корзины токенов: Rate токенов в секунду, не больше Burst подряд.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Config — настройки лимитера.
+type Config struct {
+	User Limit // обновления от одного пользователя
+	Chat Limit // сообщения в одном чате, важно для групп на сотни участников
+	// Command — команды одного пользователя сверх общего лимита: команды ходят в БД и отвечают длинными сообщениями
+	Command Limit
+
+	Strikes      int           // сколько отброшенных обновлений за StrikeWindow приводят к муту
+	StrikeWindow time.Duration // окно, в котором считаются отброшенные обновления
+	Mute         time.Duration // длительность мута
+}
+
+// Decision — решение по одному обновлению.
+type Decision int
+
+// Решения лимитера.
+const (
+	Allow Decision = iota // обновление можно обработать
+	Drop                  // лимит превышен, обновление отбрасывается молча
+	Warn                  // пользователь только что заглушён: обновление отбрасывается, нужно одно предупреждение
+)
+
+// idleTTL — через сколько без обновлений состояние пользователя или чата удаляется при Cleanup.
+const idleTTL = 10 * time.Minute
+
+// DefaultConfig возвращает настройки по умолчанию: студент может быстро отправить несколько ответов подряд,
+// но не больше одного сообщения в секунду в среднем.
+func DefaultConfig() Config {
+	return Config{
+		User:         Limit{Rate: 1, Burst: 8},
+		Chat:         Limit{Rate: 10, Burst: 50},
+		Command:      Limit{Rate: 1.0 / 3, Burst: 20},
+		Strikes:      5,
+		StrikeWindow: time.Minute,
+		Mute:         5 * time.Minute,
+	}
+}
+
+// ConfigFromEnv возвращает DefaultConfig, переопределённый переменными окружения:
+// RATE_LIMIT_USER_RATE, RATE_LIMIT_USER_BURST, RATE_LIMIT_CHAT_RATE, RATE_LIMIT_CHAT_BURST,
+// RATE_LIMIT_COMMAND_RATE, RATE_LIMIT_COMMAND_BURST, RATE_LIMIT_STRIKES, RATE_LIMIT_STRIKE_WINDOW и RATE_LIMIT_MUTE (длительности в формате time.ParseDuration).
+func ConfigFromEnv(lookup func(string) (string, bool)) (Config, error) {
+	cfg := DefaultConfig()
+
+	floats := map[string]*float64{
+		"RATE_LIMIT_USER_RATE":    &cfg.User.Rate,
+		"RATE_LIMIT_CHAT_RATE":    &cfg.Chat.Rate,
+		"RATE_LIMIT_COMMAND_RATE": &cfg.Command.Rate,
+	}
+	ints := map[string]*int{
+		"RATE_LIMIT_USER_BURST":    &cfg.User.Burst,
+		"RATE_LIMIT_CHAT_BURST":    &cfg.Chat.Burst,
+		"RATE_LIMIT_COMMAND_BURST": &cfg.Command.Burst,
+		"RATE_LIMIT_STRIKES":       &cfg.Strikes,
+	}
+	durations := map[string]*time.Duration{
+		"RATE_LIMIT_STRIKE_WINDOW": &cfg.StrikeWindow,
+		"RATE_LIMIT_MUTE":          &cfg.Mute,
+	}
+
+	for name, value := range floats {
+		if raw, ok := lookup(name); ok {
+			parsed, err := strconv.ParseFloat(raw, 64)
+			if err != nil || parsed <= 0 {
+				return Config{}, fmt.Errorf("invalid %s: %q", name, raw)
+			}
+
+			*value = parsed
+		}
+	}
+
+	for name, value := range ints {
+		if raw, ok := lookup(name); ok {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed <= 0 {
+				return Config{}, fmt.Errorf("invalid %s: %q", name, raw)
+			}
+
+			*value = parsed
+		}
+	}
+
+	for name, value := range durations {
+		if raw, ok := lookup(name); ok {
+			parsed, err := time.ParseDuration(raw)
+			if err != nil || parsed <= 0 {
+				return Config{}, fmt.Errorf("invalid %s: %q", name, raw)
+			}
+
+			*value = parsed
+		}
+	}
+
+	return cfg, nil
+}
+
+// Limiter ограничивает частоту обновлений. Безопасен для использования из нескольких горутин.
+type Limiter struct {
+	cfg   Config
+	now   Clock
+	users map[int64]*userState
+	chats map[int64]*bucket
+	// commands — корзины команд пользователей
+	commands map[int64]*bucket
+	mu       sync.Mutex
+}
+
+// userState — корзина пользователя и его нарушения.
+type userState struct {
+	bucket      bucket
+	strikes     int
+	firstStrike time.Time
+	mutedUntil  time.Time
+}
+
+// bucket — корзина токенов.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// New создаёт лимитер. Если now равен nil, используется time.Now.
+func New(cfg Config, now Clock) *Limiter {
+	if now == nil {
+		now = time.Now
+	}
+
+	return &Limiter{
+		cfg:      cfg,
+		now:      now,
+		users:    make(map[int64]*userState),
+		chats:    make(map[int64]*bucket),
+		commands: make(map[int64]*bucket),
+	}
+}
+
+// Allow решает, обрабатывать ли обновление пользователя userID из чата chatID.
+// chatID равен 0 для обновлений без чата (ответы на опросы) и для нажатий кнопок: они не порождают
+// сообщений в чате, поэтому ограничиваются только лимитом пользователя.
+// Обновление, отброшенное лимитом чата, не считается нарушением пользователя.
+func (l *Limiter) Allow(userID, chatID int64) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	user, ok := l.users[userID]
+	if !ok {
+		user = &userState{bucket: bucket{tokens: float64(l.cfg.User.Burst), last: now}}
+		l.users[userID] = user
+	}
+
+	if now.Before(user.mutedUntil) {
+		return Drop
+	}
+
+	if !user.bucket.take(now, l.cfg.User) {
+		return l.strike(user, now)
+	}
+
+	if chatID == 0 {
+		return Allow
+	}
+
+	chat, ok := l.chats[chatID]
+	if !ok {
+		chat = &bucket{tokens: float64(l.cfg.Chat.Burst), last: now}
+		l.chats[chatID] = chat
+	}
+
+	if !chat.take(now, l.cfg.Chat) {
+		return Drop
+	}
+
+	return Allow
+}
+
+// AllowCommand решает, выполнять ли команду пользователя userID. Команда сначала проходит Allow
+// как обычное обновление, поэтому отклонённая команда не считается нарушением и не глушит пользователя.
+func (l *Limiter) AllowCommand(userID int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	commands, ok := l.commands[userID]
+	if !ok {
+		commands = &bucket{tokens: float64(l.cfg.Command.Burst), last: now}
+		l.commands[userID] = commands
+	}
+
+	return commands.take(now, l.cfg.Command)
+}
+
+// Cleanup удаляет состояние пользователей и чатов, от которых давно не было обновлений.
+func (l *Limiter) Cleanup() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	for userID, user := range l.users {
+		if now.Sub(user.bucket.last) > idleTTL && !now.Before(user.mutedUntil) {
+			delete(l.users, userID)
+		}
+	}
+
+	for chatID, chat := range l.chats {
+		if now.Sub(chat.last) > idleTTL {
+			delete(l.chats, chatID)
+		}
+	}
+
+	for userID, commands := range l.commands {
+		if now.Sub(commands.last) > idleTTL {
+			delete(l.commands, userID)
+		}
+	}
+}
+
+// strike учитывает отброшенное обновление и глушит пользователя, если нарушений набралось cfg.Strikes.
+func (l *Limiter) strike(user *userState, now time.Time) Decision {
+	if user.strikes == 0 || now.Sub(user.firstStrike) > l.cfg.StrikeWindow {
+		user.strikes = 0
+		user.firstStrike = now
+	}
+
+	user.strikes++
+
+	if user.strikes < l.cfg.Strikes {
+		return Drop
+	}
+
+	user.strikes = 0
+	user.mutedUntil = now.Add(l.cfg.Mute)
+
+	return Warn
+}
+
+// take пополняет корзину за прошедшее время и забирает один токен, если он есть.
+func (b *bucket) take(now time.Time, limit Limit) bool {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.tokens+elapsed.Seconds()*limit.Rate, float64(limit.Burst))
+	}
+
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
@@ -0,0 +1,186 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock — часы, которые двигаются только вручную.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func testConfig() Config {
+	return Config{
+		User:         Limit{Rate: 1, Burst: 3},
+		Chat:         Limit{Rate: 2, Burst: 4},
+		Command:      Limit{Rate: 0.5, Burst: 2},
+		Strikes:      3,
+		StrikeWindow: time.Minute,
+		Mute:         5 * time.Minute,
+	}
+}
+
+func TestAllow_UserBucket(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC)}
+	limiter := New(testConfig(), clock.Now)
+
+	for range 3 {
+		assert.Equal(t, Allow, limiter.Allow(1, 1))
+	}
+
+	assert.Equal(t, Drop, limiter.Allow(1, 1))
+
+	// другой пользователь не страдает от чужого флуда
+	assert.Equal(t, Allow, limiter.Allow(2, 2))
+
+	// за секунду восстанавливается один токен
+	clock.Advance(time.Second)
+	assert.Equal(t, Allow, limiter.Allow(1, 1))
+	assert.Equal(t, Drop, limiter.Allow(1, 1))
+
+	// корзина не наполняется больше Burst
+	clock.Advance(time.Hour)
+
+	for range 3 {
+		assert.Equal(t, Allow, limiter.Allow(1, 1))
+	}
+
+	assert.Equal(t, Drop, limiter.Allow(1, 1))
+}
+
+func TestAllow_Mute(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC)}
+	limiter := New(testConfig(), clock.Now)
+
+	for range 3 {
+		require.Equal(t, Allow, limiter.Allow(1, 1))
+	}
+
+	assert.Equal(t, Drop, limiter.Allow(1, 1))
+	assert.Equal(t, Drop, limiter.Allow(1, 1))
+
+	// предупреждение приходит один раз, дальше обновления отбрасываются молча
+	assert.Equal(t, Warn, limiter.Allow(1, 1))
+	assert.Equal(t, Drop, limiter.Allow(1, 1))
+
+	// токены восстановились, но мут ещё действует
+	clock.Advance(time.Minute)
+	assert.Equal(t, Drop, limiter.Allow(1, 1))
+
+	clock.Advance(5 * time.Minute)
+	assert.Equal(t, Allow, limiter.Allow(1, 1))
+}
+
+func TestAllow_StrikeWindow(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC)}
+	cfg := testConfig()
+	cfg.User = Limit{Rate: 0.001, Burst: 1}
+	limiter := New(cfg, clock.Now)
+
+	require.Equal(t, Allow, limiter.Allow(1, 0))
+
+	// редкие нарушения, разнесённые дальше окна, к муту не приводят
+	for range 5 {
+		assert.Equal(t, Drop, limiter.Allow(1, 0))
+		clock.Advance(31 * time.Second)
+		assert.Equal(t, Drop, limiter.Allow(1, 0))
+		clock.Advance(31 * time.Second)
+	}
+}
+
+func TestAllow_ChatBucket(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC)}
+	limiter := New(testConfig(), clock.Now)
+
+	const groupID = -100
+
+	for userID := int64(1); userID <= 4; userID++ {
+		assert.Equal(t, Allow, limiter.Allow(userID, groupID))
+	}
+
+	assert.Equal(t, Drop, limiter.Allow(5, groupID))
+
+	// кнопки и опросы не ограничиваются лимитом чата
+	assert.Equal(t, Allow, limiter.Allow(5, 0))
+
+	// лимит чата не считается нарушением пользователя
+	for range 10 {
+		limiter.Allow(6, groupID)
+		clock.Advance(time.Second)
+	}
+
+	assert.Equal(t, Allow, limiter.Allow(6, 0))
+}
+
+func TestAllowCommand(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC)}
+	limiter := New(testConfig(), clock.Now)
+
+	assert.True(t, limiter.AllowCommand(1))
+	assert.True(t, limiter.AllowCommand(1))
+	assert.False(t, limiter.AllowCommand(1))
+
+	// лимит команд отдельный для каждого пользователя и не глушит
+	assert.True(t, limiter.AllowCommand(2))
+	assert.Equal(t, Allow, limiter.Allow(1, 1))
+
+	clock.Advance(2 * time.Second)
+	assert.True(t, limiter.AllowCommand(1))
+	assert.False(t, limiter.AllowCommand(1))
+}
+
+func TestCleanup(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC)}
+	limiter := New(testConfig(), clock.Now)
+
+	limiter.Allow(1, 1)
+	limiter.Allow(2, 2)
+	limiter.AllowCommand(1)
+
+	clock.Advance(time.Hour)
+	limiter.Allow(2, 2)
+	limiter.Cleanup()
+
+	assert.NotContains(t, limiter.users, int64(1))
+	assert.NotContains(t, limiter.chats, int64(1))
+	assert.NotContains(t, limiter.commands, int64(1))
+	assert.Contains(t, limiter.users, int64(2))
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	env := map[string]string{
+		"RATE_LIMIT_USER_RATE":     "0.5",
+		"RATE_LIMIT_CHAT_BURST":    "100",
+		"RATE_LIMIT_MUTE":          "10m",
+		"RATE_LIMIT_COMMAND_BURST": "30",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+
+		return value, ok
+	}
+
+	cfg, err := ConfigFromEnv(lookup)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, cfg.User.Rate, 1e-9)
+	assert.Equal(t, DefaultConfig().User.Burst, cfg.User.Burst)
+	assert.Equal(t, 100, cfg.Chat.Burst)
+	assert.Equal(t, 10*time.Minute, cfg.Mute)
+	assert.Equal(t, 30, cfg.Command.Burst)
+
+	env["RATE_LIMIT_STRIKES"] = "-1"
+	_, err = ConfigFromEnv(lookup)
+	assert.Error(t, err)
+}
@@ -0,0 +1,169 @@
+// Package review реализует интервальное повторение вопросов, на которых ошибся студент (алгоритм SM-2).
+package review
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+)
+
+const (
+	DefaultEaseFactor = 2.5
+	MinEaseFactor     = 1.3
+
+	day = 24 * time.Hour
+)
+
+// Оценки качества ответа по шкале SM-2 (0-5). Ответ не менее QualityPass считается вспомненным.
+const (
+	QualityWrong   = 1
+	QualityPass    = 3
+	QualityCorrect = 4
+)
+
+// Card — карточка повторения: вопрос, на котором ошибся студент, и состояние SM-2.
+type Card struct {
+	StudentID   int64
+	OwnerID     int64 // преподаватель, которому принадлежит квиз
+	QuizID      string
+	QuizTitle   string
+	QuestionIdx int
+	Question    engine.Question
+	EaseFactor  float64
+	Interval    int // интервал до следующего повторения в днях
+	Repetitions int // количество успешных повторений подряд
+	Lapses      int // сколько раз студент ошибся в вопросе
+	DueAt       time.Time
+}
+
+// NewCard создаёт карточку для вопроса, на котором студент ошибся в квизе.
+// Первое повторение назначается на следующий день.
+func NewCard(studentID int64, quiz *engine.Quiz, questionIdx int, question engine.Question, now time.Time) *Card {
+	return &Card{
+		StudentID:   studentID,
+		OwnerID:     quiz.OwnerID,
+		QuizID:      quiz.ID,
+		QuizTitle:   quiz.Title,
+		QuestionIdx: questionIdx,
+		Question:    question,
+		EaseFactor:  DefaultEaseFactor,
+		Interval:    1,
+		Lapses:      1,
+		DueAt:       now.Add(day),
+	}
+}
+
+// Review обновляет состояние карточки по оценке ответа quality (0-5) и назначает следующее повторение.
+func (c *Card) Review(quality int, now time.Time) {
+	quality = min(max(quality, 0), 5)
+
+	if quality >= QualityPass {
+		switch c.Repetitions {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.EaseFactor))
+		}
+
+		c.Repetitions++
+	} else {
+		c.Repetitions = 0
+		c.Interval = 1
+		c.Lapses++
+	}
+
+	diff := float64(5 - quality)
+	c.EaseFactor = max(MinEaseFactor, c.EaseFactor+0.1-diff*(0.08+diff*0.02))
+	c.DueAt = now.Add(time.Duration(c.Interval) * day)
+}
+
+// Answer проверяет ответ на вопрос карточки и обновляет её расписание.
+// Возвращает false, если индекс ответа вне вариантов вопроса.
+func (c *Card) Answer(answerIdx int, now time.Time) (correct bool, ok bool) {
+	if answerIdx < 0 || answerIdx >= len(c.Question.Options) {
+		return false, false
+	}
+
+	correct = c.Question.IsCorrectOption(answerIdx)
+	if correct {
+		c.Review(QualityCorrect, now)
+	} else {
+		c.Review(QualityWrong, now)
+	}
+
+	return correct, true
+}
+
+// CardsFromRun создаёт карточки по пропущенным вопросам запуска.
+// quizID — постоянный ID квиза: ID движка меняется при каждой загрузке, и карточки повторных
+// запусков одного квиза иначе не совпали бы. missed — результат engine.QuizEngine.GetMissedQuestions,
+// questions — вопросы запуска.
+func CardsFromRun(
+	quiz *engine.Quiz,
+	quizID string,
+	questions []engine.Question,
+	missed map[int64][]int,
+	now time.Time,
+) []*Card {
+	var cards []*Card
+
+	for studentID, questionIdxs := range missed {
+		for _, questionIdx := range questionIdxs {
+			card := NewCard(studentID, quiz, questionIdx, questions[questionIdx], now)
+			card.QuizID = quizID
+
+			cards = append(cards, card)
+		}
+	}
+
+	return cards
+}
+
+// ToModel преобразует карточку в модель для хранилища.
+func (c *Card) ToModel() (*models.ReviewCardModel, error) {
+	question, err := json.Marshal(c.Question)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ReviewCardModel{
+		TelegramID:  c.StudentID,
+		OwnerID:     c.OwnerID,
+		QuizID:      c.QuizID,
+		QuizTitle:   c.QuizTitle,
+		QuestionIdx: c.QuestionIdx,
+		Question:    question,
+		EaseFactor:  c.EaseFactor,
+		Interval:    c.Interval,
+		Repetitions: c.Repetitions,
+		Lapses:      c.Lapses,
+		DueAt:       c.DueAt,
+	}, nil
+}
+
+// FromModel восстанавливает карточку из модели хранилища.
+func FromModel(model *models.ReviewCardModel) (*Card, error) {
+	card := &Card{
+		StudentID:   model.TelegramID,
+		OwnerID:     model.OwnerID,
+		QuizID:      model.QuizID,
+		QuizTitle:   model.QuizTitle,
+		QuestionIdx: model.QuestionIdx,
+		EaseFactor:  model.EaseFactor,
+		Interval:    model.Interval,
+		Repetitions: model.Repetitions,
+		Lapses:      model.Lapses,
+		DueAt:       model.DueAt,
+	}
+
+	if err := json.Unmarshal(model.Question, &card.Question); err != nil {
+		return nil, err
+	}
+
+	return card, nil
+}
@@ -0,0 +1,100 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/letsssgooo/quizBot/internal/events/engine"
+)
+
+func testCard(now time.Time) *Card {
+	quiz := &engine.Quiz{ID: "quiz", OwnerID: 10, Title: "Go"}
+	question := engine.Question{Text: "Q", Options: []string{"A", "B"}, Correct: 1}
+
+	return NewCard(1, quiz, 3, question, now)
+}
+
+func TestNewCard(t *testing.T) {
+	now := time.Now()
+	card := testCard(now)
+
+	assert.Equal(t, int64(10), card.OwnerID)
+	assert.Equal(t, DefaultEaseFactor, card.EaseFactor)
+	assert.Equal(t, 1, card.Lapses)
+	assert.Equal(t, now.Add(day), card.DueAt)
+}
+
+func TestReview_SM2Intervals(t *testing.T) {
+	now := time.Now()
+	card := testCard(now)
+
+	card.Review(QualityCorrect, now)
+	assert.Equal(t, 1, card.Interval)
+	assert.Equal(t, 1, card.Repetitions)
+
+	card.Review(QualityCorrect, now)
+	assert.Equal(t, 6, card.Interval)
+
+	card.Review(QualityCorrect, now)
+	assert.Equal(t, 15, card.Interval) // 6 * 2.5
+	assert.Equal(t, now.Add(15*day), card.DueAt)
+	assert.InDelta(t, DefaultEaseFactor, card.EaseFactor, 1e-9)
+
+	card.Review(QualityWrong, now)
+	assert.Equal(t, 1, card.Interval)
+	assert.Equal(t, 0, card.Repetitions)
+	assert.Equal(t, 2, card.Lapses)
+	assert.InDelta(t, 1.96, card.EaseFactor, 1e-9)
+
+	for range 10 {
+		card.Review(0, now)
+	}
+
+	assert.Equal(t, MinEaseFactor, card.EaseFactor)
+}
+
+func TestCard_Answer(t *testing.T) {
+	now := time.Now()
+	card := testCard(now)
+
+	_, ok := card.Answer(5, now)
+	assert.False(t, ok)
+
+	correct, ok := card.Answer(1, now)
+	require.True(t, ok)
+	assert.True(t, correct)
+	assert.Equal(t, 1, card.Repetitions)
+
+	correct, ok = card.Answer(0, now)
+	require.True(t, ok)
+	assert.False(t, correct)
+	assert.Equal(t, 0, card.Repetitions)
+}
+
+func TestCardsFromRun(t *testing.T) {
+	quiz := &engine.Quiz{ID: "quiz", OwnerID: 10, Title: "Go"}
+	questions := []engine.Question{{Text: "Q1"}, {Text: "Q2"}}
+
+	cards := CardsFromRun(quiz, "saved", questions, map[int64][]int{1: {1}, 2: {0, 1}}, time.Now())
+	require.Len(t, cards, 3)
+
+	for _, card := range cards {
+		assert.Equal(t, questions[card.QuestionIdx].Text, card.Question.Text)
+		assert.Equal(t, "saved", card.QuizID)
+	}
+}
+
+func TestModelRoundTrip(t *testing.T) {
+	card := testCard(time.Now())
+	card.Review(QualityCorrect, time.Now())
+
+	model, err := card.ToModel()
+	require.NoError(t, err)
+
+	restored, err := FromModel(model)
+	require.NoError(t, err)
+	assert.Equal(t, card, restored)
+}
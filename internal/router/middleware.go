@@ -0,0 +1,88 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+)
+
+// RoleFunc возвращает роль пользователя или пустую строку, если роли нет.
+type RoleFunc func(ctx context.Context, userID int64) (string, error)
+
+// Logging логирует каждую команду, время её обработки и ошибку.
+func Logging(logger *slog.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) error {
+			start := time.Now()
+			err := next(ctx, req)
+
+			logger.Debug(
+				"command handled",
+				"command", req.Command.Name,
+				"user", req.Message.From.ID,
+				"chat", req.Message.Chat.ID,
+				"duration", time.Since(start),
+				"error", err,
+			)
+
+			return err
+		}
+	}
+}
+
+// Recover превращает панику в обработчике в ошибку ErrPanic, чтобы одна команда не роняла бота.
+func Recover(logger *slog.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Error("panic in command handler", "command", req.Command.Name, "panic", r, "stack", string(debug.Stack()))
+
+					err = fmt.Errorf("%w: %v", ErrPanic, r)
+				}
+			}()
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// RequireRole проверяет, что у пользователя есть роль, которой доступна команда.
+// Роль запрашивается только для команд с непустым Command.Role и сохраняется в Request.Role.
+func RequireRole(roleOf RoleFunc) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) error {
+			if req.Command.Role == "" {
+				return next(ctx, req)
+			}
+
+			role, err := roleOf(ctx, req.Message.From.ID)
+			if err != nil {
+				return err
+			}
+
+			if role != req.Command.Role {
+				return fmt.Errorf("%w: %s needs %s", ErrForbidden, req.Command.Name, req.Command.Role)
+			}
+
+			req.Role = role
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// RateLimit ограничивает частоту команд: команда, которую allow не пропускает, отклоняется с ErrRateLimited.
+func RateLimit(allow func(userID int64) bool) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) error {
+			if !allow(req.Message.From.ID) {
+				return fmt.Errorf("%w: user %d", ErrRateLimited, req.Message.From.ID)
+			}
+
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// Package router реализует маршрутизацию команд бота: реестр команд с метаданными,
+// цепочку middleware и генерацию справки и меню команд Telegram из реестра.
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/letsssgooo/quizBot/internal/client"
+)
+
+// Типы чатов, в которых разрешена команда.
+const (
+	ChatPrivate = "private"
+	ChatGroup   = "group" // группы и супергруппы
+)
+
+// Ошибки маршрутизации.
+var (
+	ErrNotCommand       = errors.New("message is not a command")
+	ErrUnknownCommand   = errors.New("unknown command")
+	ErrChatNotAllowed   = errors.New("command is not allowed in this chat")
+	ErrForbidden        = errors.New("command requires another role")
+	ErrRateLimited      = errors.New("too many commands")
+	ErrPanic            = errors.New("panic in command handler")
+	ErrDuplicateCommand = errors.New("command is already registered")
+	ErrInvalidName      = errors.New("command name must start with /")
+	ErrNilHandler       = errors.New("command handler is nil")
+)
+
+// HandlerFunc обрабатывает команду.
+type HandlerFunc func(ctx context.Context, req *Request) error
+
+// Middleware оборачивает обработчик команды.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Command — команда бота и её метаданные.
+type Command struct {
+	Name        string   // имя команды со слешем, например "/report"
+	Description string   // описание для /help и меню команд
+	Role        string   // роль, которой доступна команда; пустая строка — всем
+	ChatTypes   []string // где разрешена команда; пустой список — только в личном чате
+	Hidden      bool     // не показывать в /help и меню
+	Handler     HandlerFunc
+}
+
+// Request — входящая команда.
+type Request struct {
+	Message *client.Message
+	Command *Command
+	Fields  []string // слова сообщения; первое — имя команды без @username бота
+	Role    string   // роль пользователя, если её определил RequireRole
+}
+
+// Args возвращает аргументы команды.
+func (r *Request) Args() []string {
+	return r.Fields[1:]
+}
+
+// Router хранит зарегистрированные команды и middleware.
+type Router struct {
+	botUsername string
+	commands    map[string]*Command
+	order       []*Command
+	middlewares []Middleware
+}
+
+// New создаёт роутер. botUsername нужен, чтобы распознавать команды вида /help@<botUsername> в группах.
+func New(botUsername string) *Router {
+	return &Router{
+		botUsername: botUsername,
+		commands:    make(map[string]*Command),
+	}
+}
+
+// Use добавляет middleware. Первый добавленный middleware выполняется первым.
+func (r *Router) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// Register регистрирует команду.
+func (r *Router) Register(cmd Command) error {
+	if !strings.HasPrefix(cmd.Name, "/") {
+		return fmt.Errorf("%w: %q", ErrInvalidName, cmd.Name)
+	}
+
+	if cmd.Handler == nil {
+		return fmt.Errorf("%w: %s", ErrNilHandler, cmd.Name)
+	}
+
+	if _, ok := r.commands[cmd.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateCommand, cmd.Name)
+	}
+
+	r.commands[cmd.Name] = &cmd
+	r.order = append(r.order, &cmd)
+
+	return nil
+}
+
+// Lookup возвращает зарегистрированную команду по имени.
+func (r *Router) Lookup(name string) (*Command, bool) {
+	cmd, ok := r.commands[name]
+
+	return cmd, ok
+}
+
+// Parse разбирает текст сообщения на слова и нормализует имя команды.
+// Возвращает false, если сообщение не является командой или адресовано другому боту.
+func (r *Router) Parse(text string) ([]string, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return nil, false
+	}
+
+	name, username, found := strings.Cut(fields[0], "@")
+	if found && !strings.EqualFold(username, r.botUsername) {
+		return nil, false
+	}
+
+	fields[0] = name
+
+	return fields, true
+}
+
+// Handle находит команду для сообщения и выполняет её через цепочку middleware.
+func (r *Router) Handle(ctx context.Context, message *client.Message) error {
+	fields, ok := r.Parse(message.Text)
+	if !ok {
+		return ErrNotCommand
+	}
+
+	cmd, ok := r.commands[fields[0]]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownCommand, fields[0])
+	}
+
+	if !cmd.AllowedIn(chatType(message.Chat)) {
+		return fmt.Errorf("%w: %s", ErrChatNotAllowed, cmd.Name)
+	}
+
+	handler := cmd.Handler
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+
+	return handler(ctx, &Request{Message: message, Command: cmd, Fields: fields})
+}
+
+// AllowedIn сообщает, разрешена ли команда в чате типа chatType.
+func (c *Command) AllowedIn(chatType string) bool {
+	if len(c.ChatTypes) == 0 {
+		return chatType == ChatPrivate
+	}
+
+	return slices.Contains(c.ChatTypes, chatType)
+}
+
+// Commands возвращает видимые команды, доступные роли role в чате типа chatType, в порядке регистрации.
+// Пустая роль означает пользователя без роли: ему видны только общие команды.
+func (r *Router) Commands(role string, chatType string) []*Command {
+	var commands []*Command
+
+	for _, cmd := range r.order {
+		if cmd.Hidden || !cmd.AllowedIn(chatType) {
+			continue
+		}
+
+		if cmd.Role != "" && cmd.Role != role {
+			continue
+		}
+
+		commands = append(commands, cmd)
+	}
+
+	return commands
+}
+
+// Help формирует список команд для /help.
+func (r *Router) Help(role string, chatType string) string {
+	var str strings.Builder
+
+	for _, cmd := range r.Commands(role, chatType) {
+		str.WriteString(fmt.Sprintf("%s — %s\n", cmd.Name, cmd.Description))
+	}
+
+	return strings.TrimSuffix(str.String(), "\n")
+}
+
+// BotCommands формирует меню команд Telegram для роли role в чате типа chatType.
+func (r *Router) BotCommands(role string, chatType string) []client.BotCommand {
+	commands := r.Commands(role, chatType)
+	botCommands := make([]client.BotCommand, 0, len(commands))
+
+	for _, cmd := range commands {
+		botCommands = append(botCommands, client.BotCommand{
+			Command:     strings.TrimPrefix(cmd.Name, "/"),
+			Description: cmd.Description,
+		})
+	}
+
+	return botCommands
+}
+
+// chatType приводит тип чата Telegram к ChatPrivate или ChatGroup.
+func chatType(chat *client.Chat) string {
+	if chat != nil && (chat.Type == "group" || chat.Type == "supergroup") {
+		return ChatGroup
+	}
+
+	return ChatPrivate
+}
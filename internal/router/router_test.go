@@ -0,0 +1,190 @@
+package router
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/letsssgooo/quizBot/internal/client"
+)
+
+func testMessage(text string, chatType string) *client.Message {
+	return &client.Message{
+		From: &client.User{ID: 1},
+		Chat: &client.Chat{ID: 100, Type: chatType},
+		Text: text,
+	}
+}
+
+func testRouter(t *testing.T, handled *[]string) *Router {
+	t.Helper()
+
+	r := New("quiz_bot")
+
+	record := func(_ context.Context, req *Request) error {
+		*handled = append(*handled, req.Command.Name)
+
+		return nil
+	}
+
+	require.NoError(t, r.Register(Command{Name: "/start", Description: "start", Handler: record}))
+	require.NoError(t, r.Register(Command{
+		Name:        "/help",
+		Description: "help",
+		ChatTypes:   []string{ChatPrivate, ChatGroup},
+		Handler:     record,
+	}))
+	require.NoError(t, r.Register(Command{Name: "/regrade", Description: "regrade", Role: "lecturer", Handler: record}))
+	require.NoError(t, r.Register(Command{Name: "/secret", Hidden: true, Handler: record}))
+
+	return r
+}
+
+func TestRegister_Invalid(t *testing.T) {
+	r := New("quiz_bot")
+	handler := func(context.Context, *Request) error { return nil }
+
+	assert.ErrorIs(t, r.Register(Command{Name: "start", Handler: handler}), ErrInvalidName)
+	assert.ErrorIs(t, r.Register(Command{Name: "/start"}), ErrNilHandler)
+
+	require.NoError(t, r.Register(Command{Name: "/start", Handler: handler}))
+	assert.ErrorIs(t, r.Register(Command{Name: "/start", Handler: handler}), ErrDuplicateCommand)
+}
+
+func TestHandle_Dispatch(t *testing.T) {
+	var handled []string
+
+	r := testRouter(t, &handled)
+	ctx := context.Background()
+
+	require.NoError(t, r.Handle(ctx, testMessage("/start join_123", "private")))
+	require.NoError(t, r.Handle(ctx, testMessage("/help@quiz_bot", "supergroup")))
+
+	assert.Equal(t, []string{"/start", "/help"}, handled)
+
+	assert.ErrorIs(t, r.Handle(ctx, testMessage("/unknown", "private")), ErrUnknownCommand)
+	assert.ErrorIs(t, r.Handle(ctx, testMessage("hello", "private")), ErrNotCommand)
+	assert.ErrorIs(t, r.Handle(ctx, testMessage("/help@other_bot", "group")), ErrNotCommand)
+	assert.ErrorIs(t, r.Handle(ctx, testMessage("/start", "group")), ErrChatNotAllowed)
+}
+
+func TestHandle_Args(t *testing.T) {
+	r := New("quiz_bot")
+
+	var args []string
+
+	require.NoError(t, r.Register(Command{Name: "/report", Handler: func(_ context.Context, req *Request) error {
+		args = req.Args()
+
+		return nil
+	}}))
+
+	require.NoError(t, r.Handle(context.Background(), testMessage("/report  run-1 ", "private")))
+	assert.Equal(t, []string{"run-1"}, args)
+}
+
+func TestMiddleware_Order(t *testing.T) {
+	var calls []string
+
+	trace := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(ctx context.Context, req *Request) error {
+				calls = append(calls, name)
+
+				return next(ctx, req)
+			}
+		}
+	}
+
+	r := New("quiz_bot")
+	r.Use(trace("first"), trace("second"))
+
+	require.NoError(t, r.Register(Command{Name: "/start", Handler: func(context.Context, *Request) error {
+		calls = append(calls, "handler")
+
+		return nil
+	}}))
+
+	require.NoError(t, r.Handle(context.Background(), testMessage("/start", "private")))
+	assert.Equal(t, []string{"first", "second", "handler"}, calls)
+}
+
+func TestRequireRole(t *testing.T) {
+	var handled []string
+
+	role := ""
+	lookups := 0
+
+	r := testRouter(t, &handled)
+	r.Use(RequireRole(func(context.Context, int64) (string, error) {
+		lookups++
+
+		return role, nil
+	}))
+
+	ctx := context.Background()
+
+	// роль запрашивается только для команд, которым она нужна
+	require.NoError(t, r.Handle(ctx, testMessage("/help", "private")))
+	assert.Equal(t, 0, lookups)
+
+	assert.ErrorIs(t, r.Handle(ctx, testMessage("/regrade", "private")), ErrForbidden)
+
+	role = "lecturer"
+	require.NoError(t, r.Handle(ctx, testMessage("/regrade", "private")))
+
+	assert.Equal(t, 2, lookups)
+	assert.Equal(t, []string{"/help", "/regrade"}, handled)
+}
+
+func TestRecover(t *testing.T) {
+	r := New("quiz_bot")
+	r.Use(Recover(slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	require.NoError(t, r.Register(Command{Name: "/boom", Handler: func(context.Context, *Request) error {
+		panic("boom")
+	}}))
+
+	err := r.Handle(context.Background(), testMessage("/boom", "private"))
+	assert.ErrorIs(t, err, ErrPanic)
+}
+
+func TestRateLimit(t *testing.T) {
+	calls := make(map[int64]int)
+	allow := func(userID int64) bool {
+		calls[userID]++
+
+		return calls[userID] <= 1
+	}
+
+	r := New("quiz_bot")
+	r.Use(RateLimit(allow))
+
+	require.NoError(t, r.Register(Command{Name: "/start", Handler: func(context.Context, *Request) error {
+		return nil
+	}}))
+
+	require.NoError(t, r.Handle(context.Background(), testMessage("/start", "private")))
+
+	err := r.Handle(context.Background(), testMessage("/start", "private"))
+	assert.ErrorIs(t, err, ErrRateLimited)
+}
+
+func TestHelpAndBotCommands(t *testing.T) {
+	var handled []string
+
+	r := testRouter(t, &handled)
+
+	assert.Equal(t, "/start — start\n/help — help", r.Help("", ChatPrivate))
+	assert.Equal(t, "/start — start\n/help — help\n/regrade — regrade", r.Help("lecturer", ChatPrivate))
+	assert.Equal(t, "/help — help", r.Help("", ChatGroup))
+
+	assert.Equal(t, []client.BotCommand{
+		{Command: "start", Description: "start"},
+		{Command: "help", Description: "help"},
+	}, r.BotCommands("student", ChatPrivate))
+}
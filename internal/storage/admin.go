@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+// ErrUserNotBanned возвращается, если пользователь не заблокирован.
+var ErrUserNotBanned = errors.New("user is not banned")
+
+// AdminStorage хранит данные для администрирования бота: списки пользователей, блокировки и общую статистику.
+type AdminStorage interface {
+	// ListUsersByRole возвращает не более limit пользователей с ролью role, начиная с новых.
+	// Пустая role — пользователи, ещё не выбравшие роль.
+	ListUsersByRole(ctx context.Context, role string, limit int) ([]*models.UserModel, error)
+
+	// BanUser блокирует пользователя или обновляет причину блокировки.
+	BanUser(ctx context.Context, ban *models.BanModel) error
+
+	// UnbanUser снимает блокировку. Возвращает ErrUserNotBanned, если пользователь не заблокирован.
+	UnbanUser(ctx context.Context, telegramID int64) error
+
+	// ListBans возвращает всех заблокированных пользователей вместе с их username и ФИО.
+	ListBans(ctx context.Context) ([]*models.BanModel, error)
+
+	// GetBotStats возвращает общую статистику бота.
+	GetBotStats(ctx context.Context) (*models.BotStatsModel, error)
+}
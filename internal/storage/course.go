@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+var (
+	// ErrCourseNotFound возвращается, если курса нет.
+	ErrCourseNotFound = errors.New("course not found")
+	// ErrCourseAlreadyExists возвращается, если у преподавателя уже есть курс с таким названием.
+	ErrCourseAlreadyExists = errors.New("course already exists")
+)
+
+// CourseStorage хранит курсы преподавателей, записанных на них студентов и опубликованные в них квизы.
+type CourseStorage interface {
+	// CreateCourse сохраняет курс и заполняет его ID.
+	// Возвращает ErrCourseAlreadyExists, если у преподавателя уже есть курс с таким названием.
+	CreateCourse(ctx context.Context, course *models.CourseModel) error
+
+	// GetCourse возвращает курс по ID. Возвращает ErrCourseNotFound, если курса нет.
+	GetCourse(ctx context.Context, id int64) (*models.CourseModel, error)
+
+	// GetCourseByInvite возвращает курс по коду ссылки для записи. Возвращает ErrCourseNotFound, если курса нет.
+	GetCourseByInvite(ctx context.Context, code string) (*models.CourseModel, error)
+
+	// ListCourses возвращает курсы преподавателя, начиная с новых, с количеством студентов и квизов.
+	ListCourses(ctx context.Context, ownerID int64) ([]*models.CourseModel, error)
+
+	// ListEnrolledCourses возвращает курсы, на которые записан студент, с количеством квизов.
+	ListEnrolledCourses(ctx context.Context, telegramID int64) ([]*models.CourseModel, error)
+
+	// Enroll записывает студента на курс. Возвращает false, если он уже записан.
+	Enroll(ctx context.Context, courseID int64, telegramID int64, now time.Time) (bool, error)
+
+	// EnrollGroup записывает на курс всех студентов учебной группы. Возвращает число новых записей.
+	EnrollGroup(ctx context.Context, courseID int64, group string, now time.Time) (int, error)
+
+	// ListCourseStudents возвращает студентов курса, упорядоченных по группе и ФИО.
+	// CreatedAt заполняется датой записи на курс.
+	ListCourseStudents(ctx context.Context, courseID int64) ([]*models.UserModel, error)
+
+	// PublishQuiz публикует квиз в курсе. Возвращает false, если квиз уже опубликован в нём.
+	PublishQuiz(ctx context.Context, courseID int64, quizID string, now time.Time) (bool, error)
+
+	// ListCourseQuizzes возвращает квизы курса в порядке публикации. Файлы квизов не загружаются.
+	ListCourseQuizzes(ctx context.Context, courseID int64) ([]*models.QuizModel, error)
+
+	// QuizCourseAccess сообщает, опубликован ли квиз хотя бы в одном курсе
+	// и записан ли пользователь хотя бы на один из этих курсов.
+	QuizCourseAccess(ctx context.Context, quizID string, telegramID int64) (published, enrolled bool, err error)
+
+	// ListCourseResults возвращает лучший балл и число попыток каждого студента курса
+	// по каждому квизу курса, который он проходил.
+	ListCourseResults(ctx context.Context, courseID int64) ([]*models.CourseResultModel, error)
+}
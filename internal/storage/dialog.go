@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+// DialogStorage хранит состояния многошаговых диалогов, чтобы они переживали перезапуск бота.
+type DialogStorage interface {
+	// SaveDialog сохраняет состояние диалога пользователя, заменяя предыдущее.
+	SaveDialog(ctx context.Context, dialog *models.DialogModel) error
+
+	// DeleteDialog удаляет состояние диалога пользователя.
+	DeleteDialog(ctx context.Context, telegramID int64) error
+
+	// ListDialogs возвращает диалоги, которые не истекли к now.
+	ListDialogs(ctx context.Context, now time.Time) ([]*models.DialogModel, error)
+}
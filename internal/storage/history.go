@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+// ErrRunNotFound возвращается, если сохранённого запуска нет.
+var ErrRunNotFound = errors.New("run not found")
+
+// HistoryStorage хранит итоги завершённых запусков для истории и сравнения.
+type HistoryStorage interface {
+	// SaveRunResults сохраняет итоги запуска. Итоги, сохранённые раньше (например, до перепроверки), заменяются.
+	SaveRunResults(ctx context.Context, results *models.RunResultsModel) error
+
+	// GetRun возвращает запуск по ID. Возвращает ErrRunNotFound, если запуска нет.
+	GetRun(ctx context.Context, runID string) (*models.RunModel, error)
+
+	// GetRunResults возвращает итоги запуска. Для незавершённого запуска списки пустые.
+	GetRunResults(ctx context.Context, runID string) (*models.RunResultsModel, error)
+
+	// SaveRunArchive сохраняет состояние запуска, выгружаемого из памяти. Возвращает ErrRunNotFound,
+	// если запуск не сохранён.
+	SaveRunArchive(ctx context.Context, archive *models.RunArchiveModel) error
+
+	// GetRunArchive возвращает сохранённое состояние запуска. Возвращает ErrRunNotFound,
+	// если запуска нет или он не выгружался.
+	GetRunArchive(ctx context.Context, runID string) (*models.RunArchiveModel, error)
+
+	// ListArchivedRuns возвращает ID не более limit выгруженных запусков, в которых участвовал пользователь,
+	// начиная с новых.
+	ListArchivedRuns(ctx context.Context, telegramID int64, limit int) ([]string, error)
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+// ErrLecturerCodeNotFound возвращается, если кода подтверждения нет, он уже использован или истёк.
+var ErrLecturerCodeNotFound = errors.New("lecturer code not found")
+
+// LecturerStorage хранит подтверждённых преподавателей и одноразовые коды для подтверждения роли.
+type LecturerStorage interface {
+	// IsLecturerApproved сообщает, подтверждена ли пользователю роль преподавателя.
+	IsLecturerApproved(ctx context.Context, telegramID int64) (bool, error)
+
+	// ApproveLecturer запоминает, что пользователю подтверждена роль преподавателя.
+	ApproveLecturer(ctx context.Context, telegramID int64, approvedBy int64) error
+
+	// RevokeLecturer отзывает подтверждение роли преподавателя.
+	RevokeLecturer(ctx context.Context, telegramID int64) error
+
+	// SaveLecturerCode сохраняет код подтверждения.
+	SaveLecturerCode(ctx context.Context, code *models.LecturerCodeModel) error
+
+	// TakeLecturerCode возвращает код подтверждения и удаляет его: код одноразовый.
+	// Возвращает ErrLecturerCodeNotFound, если кода нет или он истёк к моменту now.
+	TakeLecturerCode(ctx context.Context, code string, now time.Time) (*models.LecturerCodeModel, error)
+}
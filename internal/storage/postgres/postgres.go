@@ -2,7 +2,10 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/letsssgooo/quizBot/internal/domain/models"
 	"github.com/letsssgooo/quizBot/internal/storage"
@@ -115,3 +118,1581 @@ func (s *Storage) EditQuiz(
 	_, err := s.pool.Exec(ctx, query, quizInfo.File, quizInfo.Name)
 	return err
 }
+
+// SavePracticeResult сохраняет результат тренировки студента в БД
+func (s *Storage) SavePracticeResult(ctx context.Context, result *models.PracticeModel) error {
+	query := `
+	INSERT INTO practice_results (
+		id, telegram_id, quiz_id, quiz_title, timed, score, max_score,
+		correct_count, questions_count, started_at, finished_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := s.pool.Exec(
+		ctx,
+		query,
+		result.ID,
+		result.TelegramID,
+		result.QuizID,
+		result.QuizTitle,
+		result.Timed,
+		result.Score,
+		result.MaxScore,
+		result.CorrectCount,
+		result.QuestionsCount,
+		result.StartedAt,
+		result.FinishedAt,
+	)
+
+	return err
+}
+
+// AddReviewCards добавляет карточки повторения. Повторная ошибка в том же вопросе
+// сбрасывает повторения карточки и увеличивает счетчик ошибок.
+func (s *Storage) AddReviewCards(ctx context.Context, cards []*models.ReviewCardModel) error {
+	query := `
+	INSERT INTO review_cards (
+		telegram_id, owner_id, quiz_id, quiz_title, question_idx, question,
+		ease_factor, interval_days, repetitions, lapses, due_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	ON CONFLICT (telegram_id, quiz_id, question_idx) DO UPDATE SET
+		question = EXCLUDED.question,
+		interval_days = EXCLUDED.interval_days,
+		repetitions = 0,
+		lapses = review_cards.lapses + 1,
+		due_at = EXCLUDED.due_at
+	`
+
+	batch := &pgx.Batch{}
+	for _, card := range cards {
+		batch.Queue(
+			query,
+			card.TelegramID,
+			card.OwnerID,
+			card.QuizID,
+			card.QuizTitle,
+			card.QuestionIdx,
+			card.Question,
+			card.EaseFactor,
+			card.Interval,
+			card.Repetitions,
+			card.Lapses,
+			card.DueAt,
+		)
+	}
+
+	return s.pool.SendBatch(ctx, batch).Close()
+}
+
+// GetDueReviewCards возвращает карточки студента, которые пора повторить
+func (s *Storage) GetDueReviewCards(
+	ctx context.Context,
+	telegramID int64,
+	now time.Time,
+	limit int,
+) ([]*models.ReviewCardModel, error) {
+	query := `
+	SELECT telegram_id, owner_id, quiz_id, quiz_title, question_idx, question,
+		ease_factor, interval_days, repetitions, lapses, due_at
+	FROM review_cards
+	WHERE telegram_id = $1 AND due_at <= $2
+	ORDER BY due_at
+	LIMIT $3
+	`
+
+	rows, err := s.pool.Query(ctx, query, telegramID, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cards []*models.ReviewCardModel
+
+	for rows.Next() {
+		card := &models.ReviewCardModel{}
+
+		err = rows.Scan(
+			&card.TelegramID,
+			&card.OwnerID,
+			&card.QuizID,
+			&card.QuizTitle,
+			&card.QuestionIdx,
+			&card.Question,
+			&card.EaseFactor,
+			&card.Interval,
+			&card.Repetitions,
+			&card.Lapses,
+			&card.DueAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		cards = append(cards, card)
+	}
+
+	return cards, rows.Err()
+}
+
+// UpdateReviewCard сохраняет состояние карточки после повторения
+func (s *Storage) UpdateReviewCard(ctx context.Context, card *models.ReviewCardModel) error {
+	query := `
+	UPDATE review_cards
+	SET ease_factor = $1, interval_days = $2, repetitions = $3, lapses = $4, due_at = $5
+	WHERE telegram_id = $6 AND quiz_id = $7 AND question_idx = $8
+	`
+
+	_, err := s.pool.Exec(
+		ctx,
+		query,
+		card.EaseFactor,
+		card.Interval,
+		card.Repetitions,
+		card.Lapses,
+		card.DueAt,
+		card.TelegramID,
+		card.QuizID,
+		card.QuestionIdx,
+	)
+
+	return err
+}
+
+// CountDueReviewCards возвращает количество карточек к повторению по студентам
+func (s *Storage) CountDueReviewCards(ctx context.Context, now time.Time) (map[int64]int, error) {
+	query := `
+	SELECT telegram_id, COUNT(*) FROM review_cards WHERE due_at <= $1 GROUP BY telegram_id
+	`
+
+	rows, err := s.pool.Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int)
+
+	for rows.Next() {
+		var (
+			telegramID int64
+			cnt        int
+		)
+
+		if err = rows.Scan(&telegramID, &cnt); err != nil {
+			return nil, err
+		}
+
+		counts[telegramID] = cnt
+	}
+
+	return counts, rows.Err()
+}
+
+// GetForgottenQuestions возвращает вопросы преподавателя с наибольшим числом ошибок студентов
+func (s *Storage) GetForgottenQuestions(
+	ctx context.Context,
+	ownerID int64,
+	limit int,
+) ([]*models.ForgottenQuestionModel, error) {
+	query := `
+	SELECT quiz_title, question_idx, MAX(question->>'text'), COUNT(*), SUM(lapses)
+	FROM review_cards
+	WHERE owner_id = $1
+	GROUP BY quiz_id, quiz_title, question_idx
+	ORDER BY SUM(lapses) DESC, COUNT(*) DESC
+	LIMIT $2
+	`
+
+	rows, err := s.pool.Query(ctx, query, ownerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var questions []*models.ForgottenQuestionModel
+
+	for rows.Next() {
+		question := &models.ForgottenQuestionModel{}
+
+		err = rows.Scan(
+			&question.QuizTitle,
+			&question.QuestionIdx,
+			&question.QuestionText,
+			&question.Students,
+			&question.Lapses,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		questions = append(questions, question)
+	}
+
+	return questions, rows.Err()
+}
+
+// SaveDialog сохраняет состояние диалога пользователя
+func (s *Storage) SaveDialog(ctx context.Context, dialog *models.DialogModel) error {
+	query := `
+	INSERT INTO dialogs (telegram_id, state, data, expires_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (telegram_id) DO UPDATE
+	SET state = EXCLUDED.state, data = EXCLUDED.data,
+		expires_at = EXCLUDED.expires_at, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := s.pool.Exec(
+		ctx,
+		query,
+		dialog.TelegramID,
+		dialog.State,
+		dialog.Data,
+		dialog.ExpiresAt,
+		dialog.UpdatedAt,
+	)
+
+	return err
+}
+
+// DeleteDialog удаляет состояние диалога пользователя
+func (s *Storage) DeleteDialog(ctx context.Context, telegramID int64) error {
+	query := `DELETE FROM dialogs WHERE telegram_id = $1`
+
+	_, err := s.pool.Exec(ctx, query, telegramID)
+
+	return err
+}
+
+// ListDialogs возвращает диалоги, которые не истекли к now
+func (s *Storage) ListDialogs(ctx context.Context, now time.Time) ([]*models.DialogModel, error) {
+	query := `
+	SELECT telegram_id, state, data, expires_at, updated_at FROM dialogs WHERE expires_at > $1
+	`
+
+	rows, err := s.pool.Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dialogs []*models.DialogModel
+
+	for rows.Next() {
+		dialog := &models.DialogModel{}
+
+		err = rows.Scan(&dialog.TelegramID, &dialog.State, &dialog.Data, &dialog.ExpiresAt, &dialog.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		dialogs = append(dialogs, dialog)
+	}
+
+	return dialogs, rows.Err()
+}
+
+// SaveQuiz сохраняет загруженный преподавателем квиз
+func (s *Storage) SaveQuiz(ctx context.Context, quiz *models.QuizModel) error {
+	query := `
+	INSERT INTO quizzes (id, owner_id, title, file, questions_count, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := s.pool.Exec(
+		ctx,
+		query,
+		quiz.ID,
+		quiz.OwnerID,
+		quiz.Title,
+		quiz.File,
+		quiz.QuestionsCount,
+		quiz.CreatedAt,
+	)
+
+	return err
+}
+
+// GetQuiz возвращает сохранённый квиз. Возвращает storage.ErrQuizNotFound, если квиза нет.
+func (s *Storage) GetQuiz(ctx context.Context, id string) (*models.QuizModel, error) {
+	query := `
+	SELECT id, owner_id, title, file, questions_count, created_at FROM quizzes WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	quiz := &models.QuizModel{}
+
+	err := s.pool.QueryRow(ctx, query, id).Scan(
+		&quiz.ID,
+		&quiz.OwnerID,
+		&quiz.Title,
+		&quiz.File,
+		&quiz.QuestionsCount,
+		&quiz.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrQuizNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return quiz, nil
+}
+
+// ListQuizzes возвращает квизы преподавателя с количеством их запусков. Файлы квизов не загружаются.
+func (s *Storage) ListQuizzes(ctx context.Context, ownerID int64, limit int) ([]*models.QuizModel, error) {
+	query := `
+	SELECT q.id, q.owner_id, q.title, q.questions_count, q.created_at,
+		(SELECT COUNT(*) FROM quiz_runs r WHERE r.quiz_id = q.id)
+	FROM quizzes q
+	WHERE q.owner_id = $1 AND q.deleted_at IS NULL
+	ORDER BY q.created_at DESC
+	LIMIT $2
+	`
+
+	rows, err := s.pool.Query(ctx, query, ownerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quizzes []*models.QuizModel
+
+	for rows.Next() {
+		quiz := &models.QuizModel{}
+
+		err = rows.Scan(
+			&quiz.ID,
+			&quiz.OwnerID,
+			&quiz.Title,
+			&quiz.QuestionsCount,
+			&quiz.CreatedAt,
+			&quiz.RunsCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		quizzes = append(quizzes, quiz)
+	}
+
+	return quizzes, rows.Err()
+}
+
+// DeleteQuiz помечает квиз удалённым. Запуски и их итоги остаются
+func (s *Storage) DeleteQuiz(ctx context.Context, id string) error {
+	query := `UPDATE quizzes SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+
+	_, err := s.pool.Exec(ctx, query, id)
+
+	return err
+}
+
+// SaveRun сохраняет запуск квиза или обновляет его статус и результаты
+func (s *Storage) SaveRun(ctx context.Context, run *models.RunModel) error {
+	query := `
+	INSERT INTO quiz_runs (id, quiz_id, owner_id, status, participants_count, started_at, finished_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (id) DO UPDATE
+	SET status = EXCLUDED.status, participants_count = EXCLUDED.participants_count,
+		finished_at = EXCLUDED.finished_at
+	`
+
+	_, err := s.pool.Exec(
+		ctx,
+		query,
+		run.ID,
+		run.QuizID,
+		run.OwnerID,
+		run.Status,
+		run.ParticipantsCount,
+		run.StartedAt,
+		run.FinishedAt,
+	)
+
+	return err
+}
+
+// ListRuns возвращает запуски квиза, начиная с новых
+func (s *Storage) ListRuns(ctx context.Context, quizID string, limit int) ([]*models.RunModel, error) {
+	query := `
+	SELECT id, quiz_id, owner_id, status, participants_count, started_at, finished_at
+	FROM quiz_runs
+	WHERE quiz_id = $1
+	ORDER BY started_at DESC
+	LIMIT $2
+	`
+
+	rows, err := s.pool.Query(ctx, query, quizID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*models.RunModel
+
+	for rows.Next() {
+		run := &models.RunModel{}
+
+		err = rows.Scan(
+			&run.ID,
+			&run.QuizID,
+			&run.OwnerID,
+			&run.Status,
+			&run.ParticipantsCount,
+			&run.StartedAt,
+			&run.FinishedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// SaveRunResults сохраняет итоги запуска, заменяя сохранённые ранее
+func (s *Storage) SaveRunResults(ctx context.Context, results *models.RunResultsModel) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // после Commit откат ничего не делает
+
+	batch := &pgx.Batch{}
+	batch.Queue(`UPDATE quiz_runs SET csv = $1 WHERE id = $2`, results.CSV, results.RunID)
+	batch.Queue(`DELETE FROM run_results WHERE run_id = $1`, results.RunID)
+	batch.Queue(`DELETE FROM run_questions WHERE run_id = $1`, results.RunID)
+	batch.Queue(`DELETE FROM run_answers WHERE run_id = $1`, results.RunID)
+
+	for _, participant := range results.Participants {
+		batch.Queue(
+			`
+			INSERT INTO run_results (run_id, telegram_id, name, score, rank, correct_count, answered_count, max_score)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			`,
+			results.RunID,
+			participant.TelegramID,
+			participant.Name,
+			participant.Score,
+			participant.Rank,
+			participant.CorrectCount,
+			participant.AnsweredCount,
+			participant.MaxScore,
+		)
+	}
+
+	for _, question := range results.Questions {
+		batch.Queue(
+			`
+			INSERT INTO run_questions (run_id, question_idx, question_text, answered, correct, voided)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			`,
+			results.RunID,
+			question.QuestionIdx,
+			question.QuestionText,
+			question.Answered,
+			question.Correct,
+			question.Voided,
+		)
+	}
+
+	for _, answer := range results.Answers {
+		topics := answer.Topics
+		if topics == nil {
+			topics = []string{}
+		}
+
+		batch.Queue(
+			`
+			INSERT INTO run_answers (run_id, telegram_id, question_idx, topics, answered, correct)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			`,
+			results.RunID,
+			answer.TelegramID,
+			answer.QuestionIdx,
+			topics,
+			answer.Answered,
+			answer.Correct,
+		)
+	}
+
+	if err = tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetRun возвращает сохранённый запуск. Возвращает storage.ErrRunNotFound, если запуска нет.
+func (s *Storage) GetRun(ctx context.Context, runID string) (*models.RunModel, error) {
+	query := `
+	SELECT id, quiz_id, owner_id, status, participants_count, started_at, finished_at
+	FROM quiz_runs WHERE id = $1
+	`
+
+	run := &models.RunModel{}
+
+	err := s.pool.QueryRow(ctx, query, runID).Scan(
+		&run.ID,
+		&run.QuizID,
+		&run.OwnerID,
+		&run.Status,
+		&run.ParticipantsCount,
+		&run.StartedAt,
+		&run.FinishedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrRunNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// GetRunResults возвращает итоги запуска: CSV, результаты участников по местам и ответы по вопросам
+func (s *Storage) GetRunResults(ctx context.Context, runID string) (*models.RunResultsModel, error) {
+	results := &models.RunResultsModel{RunID: runID}
+
+	err := s.pool.QueryRow(ctx, `SELECT csv FROM quiz_runs WHERE id = $1`, runID).Scan(&results.CSV)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrRunNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT telegram_id, name, score, rank, correct_count, answered_count, max_score
+	FROM run_results WHERE run_id = $1 ORDER BY rank, name
+	`
+
+	rows, err := s.pool.Query(ctx, query, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		participant := &models.RunParticipantModel{}
+
+		err = rows.Scan(
+			&participant.TelegramID,
+			&participant.Name,
+			&participant.Score,
+			&participant.Rank,
+			&participant.CorrectCount,
+			&participant.AnsweredCount,
+			&participant.MaxScore,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		results.Participants = append(results.Participants, participant)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = `
+	SELECT question_idx, question_text, answered, correct, voided
+	FROM run_questions WHERE run_id = $1 ORDER BY question_idx
+	`
+
+	rows, err = s.pool.Query(ctx, query, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		question := &models.RunQuestionModel{}
+
+		err = rows.Scan(
+			&question.QuestionIdx,
+			&question.QuestionText,
+			&question.Answered,
+			&question.Correct,
+			&question.Voided,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		results.Questions = append(results.Questions, question)
+	}
+
+	return results, rows.Err()
+}
+
+// SaveRunArchive сохраняет состояние выгружаемого из памяти запуска. Возвращает storage.ErrRunNotFound,
+// если запуск не сохранён
+func (s *Storage) SaveRunArchive(ctx context.Context, archive *models.RunArchiveModel) error {
+	query := `UPDATE quiz_runs SET archive = $1, starter_id = $2 WHERE id = $3`
+
+	cmdTag, err := s.pool.Exec(ctx, query, archive.Data, archive.StarterID, archive.RunID)
+	if err != nil {
+		return err
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return storage.ErrRunNotFound
+	}
+
+	return nil
+}
+
+// GetRunArchive возвращает состояние выгруженного запуска. Возвращает storage.ErrRunNotFound,
+// если запуска нет или он не выгружался
+func (s *Storage) GetRunArchive(ctx context.Context, runID string) (*models.RunArchiveModel, error) {
+	query := `
+	SELECT id, quiz_id, starter_id, archive FROM quiz_runs WHERE id = $1 AND archive IS NOT NULL
+	`
+
+	archive := &models.RunArchiveModel{}
+
+	err := s.pool.QueryRow(ctx, query, runID).Scan(&archive.RunID, &archive.QuizID, &archive.StarterID, &archive.Data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrRunNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// ListArchivedRuns возвращает ID выгруженных запусков, в которых участвовал пользователь, начиная с новых
+func (s *Storage) ListArchivedRuns(ctx context.Context, telegramID int64, limit int) ([]string, error) {
+	query := `
+	SELECT r.id
+	FROM quiz_runs r
+	JOIN run_results rr ON rr.run_id = r.id
+	WHERE rr.telegram_id = $1 AND r.archive IS NOT NULL
+	ORDER BY r.started_at DESC
+	LIMIT $2
+	`
+
+	rows, err := s.pool.Query(ctx, query, telegramID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runIDs []string
+
+	for rows.Next() {
+		var runID string
+
+		if err = rows.Scan(&runID); err != nil {
+			return nil, err
+		}
+
+		runIDs = append(runIDs, runID)
+	}
+
+	return runIDs, rows.Err()
+}
+
+// GetStudentRuns возвращает результаты студента в завершённых запусках от старых к новым
+func (s *Storage) GetStudentRuns(ctx context.Context, telegramID, ownerID int64) ([]*models.StudentRunModel, error) {
+	query := `
+	SELECT r.id, q.title, r.started_at, rr.score, rr.max_score, rr.rank,
+		(SELECT COUNT(*) FROM run_results other WHERE other.run_id = rr.run_id)
+	FROM run_results rr
+	JOIN quiz_runs r ON r.id = rr.run_id
+	JOIN quizzes q ON q.id = r.quiz_id
+	WHERE rr.telegram_id = $1 AND r.status = 'finished' AND ($2::BIGINT = 0 OR r.owner_id = $2
+		OR EXISTS (SELECT 1 FROM quiz_shares sh WHERE sh.quiz_id = r.quiz_id AND sh.telegram_id = $2))
+	ORDER BY r.started_at
+	`
+
+	rows, err := s.pool.Query(ctx, query, telegramID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*models.StudentRunModel
+
+	for rows.Next() {
+		run := &models.StudentRunModel{}
+
+		err = rows.Scan(
+			&run.RunID,
+			&run.QuizTitle,
+			&run.StartedAt,
+			&run.Score,
+			&run.MaxScore,
+			&run.Rank,
+			&run.Participants,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// GetStudentTopics возвращает ответы студента по темам вопросов завершённых запусков
+func (s *Storage) GetStudentTopics(
+	ctx context.Context,
+	telegramID, ownerID int64,
+) ([]*models.TopicAccuracyModel, error) {
+	query := `
+	SELECT topic, COUNT(*), COUNT(*) FILTER (WHERE a.correct)
+	FROM run_answers a
+	JOIN quiz_runs r ON r.id = a.run_id
+	CROSS JOIN LATERAL unnest(a.topics) AS topic
+	WHERE a.telegram_id = $1 AND r.status = 'finished' AND ($2::BIGINT = 0 OR r.owner_id = $2
+		OR EXISTS (SELECT 1 FROM quiz_shares sh WHERE sh.quiz_id = r.quiz_id AND sh.telegram_id = $2))
+	GROUP BY topic
+	ORDER BY topic
+	`
+
+	rows, err := s.pool.Query(ctx, query, telegramID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var topics []*models.TopicAccuracyModel
+
+	for rows.Next() {
+		topic := &models.TopicAccuracyModel{}
+
+		if err = rows.Scan(&topic.Topic, &topic.Answered, &topic.Correct); err != nil {
+			return nil, err
+		}
+
+		topics = append(topics, topic)
+	}
+
+	return topics, rows.Err()
+}
+
+// FindStudents ищет участников запусков преподавателя по имени в результатах или ФИО из регистрации
+func (s *Storage) FindStudents(
+	ctx context.Context,
+	ownerID int64,
+	query string,
+	limit int,
+) ([]*models.StudentModel, error) {
+	sqlQuery := `
+	SELECT DISTINCT ON (rr.telegram_id) rr.telegram_id, rr.name, COALESCE(u.full_name, ''), COALESCE(u.user_group, '')
+	FROM run_results rr
+	JOIN quiz_runs r ON r.id = rr.run_id
+	LEFT JOIN users u ON u.telegram_id = rr.telegram_id
+	WHERE (r.owner_id = $1 OR EXISTS (SELECT 1 FROM quiz_shares sh WHERE sh.quiz_id = r.quiz_id AND sh.telegram_id = $1))
+		AND ($2 = '' OR rr.name ILIKE '%' || $2 || '%' OR u.full_name ILIKE '%' || $2 || '%')
+	ORDER BY rr.telegram_id, r.started_at DESC
+	LIMIT $3
+	`
+
+	rows, err := s.pool.Query(ctx, sqlQuery, ownerID, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var students []*models.StudentModel
+
+	for rows.Next() {
+		student := &models.StudentModel{}
+
+		if err = rows.Scan(&student.TelegramID, &student.Name, &student.FullName, &student.Group); err != nil {
+			return nil, err
+		}
+
+		students = append(students, student)
+	}
+
+	return students, rows.Err()
+}
+
+// GetStudent возвращает участника запусков преподавателя. Возвращает storage.ErrStudentNotFound,
+// если студент в них не участвовал
+func (s *Storage) GetStudent(ctx context.Context, ownerID, telegramID int64) (*models.StudentModel, error) {
+	query := `
+	SELECT rr.telegram_id, rr.name, COALESCE(u.full_name, ''), COALESCE(u.user_group, '')
+	FROM run_results rr
+	JOIN quiz_runs r ON r.id = rr.run_id
+	LEFT JOIN users u ON u.telegram_id = rr.telegram_id
+	WHERE rr.telegram_id = $2
+		AND (r.owner_id = $1 OR EXISTS (SELECT 1 FROM quiz_shares sh WHERE sh.quiz_id = r.quiz_id AND sh.telegram_id = $1))
+	ORDER BY r.started_at DESC
+	LIMIT 1
+	`
+
+	student := &models.StudentModel{}
+
+	err := s.pool.QueryRow(ctx, query, ownerID, telegramID).Scan(
+		&student.TelegramID,
+		&student.Name,
+		&student.FullName,
+		&student.Group,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrStudentNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return student, nil
+}
+
+// GetUserFields возвращает сохранённые поля регистрации пользователя вместе с ФИО и группой из профиля
+func (s *Storage) GetUserFields(ctx context.Context, telegramID int64) (map[string]string, error) {
+	fields := make(map[string]string)
+
+	var fullName, group string
+
+	err := s.pool.QueryRow(
+		ctx,
+		`SELECT COALESCE(full_name, ''), COALESCE(user_group, '') FROM users WHERE telegram_id = $1`,
+		telegramID,
+	).Scan(&fullName, &group)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	if fullName != "" {
+		fields["name"] = fullName
+	}
+
+	if group != "" {
+		fields["group"] = group
+	}
+
+	rows, err := s.pool.Query(ctx, `SELECT field, value FROM user_fields WHERE telegram_id = $1`, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var field, value string
+
+		if err = rows.Scan(&field, &value); err != nil {
+			return nil, err
+		}
+
+		fields[field] = value
+	}
+
+	return fields, rows.Err()
+}
+
+// SaveUserField сохраняет поле регистрации пользователя. ФИО и группа записываются в профиль
+func (s *Storage) SaveUserField(ctx context.Context, telegramID int64, field, value string) error {
+	var err error
+
+	switch field {
+	case "name":
+		_, err = s.pool.Exec(ctx, `UPDATE users SET full_name = $1 WHERE telegram_id = $2`, value, telegramID)
+	case "group":
+		_, err = s.pool.Exec(ctx, `UPDATE users SET user_group = $1 WHERE telegram_id = $2`, value, telegramID)
+	default:
+		query := `
+		INSERT INTO user_fields (telegram_id, field, value, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (telegram_id, field) DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at
+		`
+
+		_, err = s.pool.Exec(ctx, query, telegramID, field, value)
+	}
+
+	return err
+}
+
+// SaveUsername запоминает username пользователя
+func (s *Storage) SaveUsername(ctx context.Context, telegramID int64, username string) error {
+	_, err := s.pool.Exec(ctx, `UPDATE users SET username = $1 WHERE telegram_id = $2`, username, telegramID)
+
+	return err
+}
+
+// FindUserByUsername возвращает Telegram ID пользователя по username. Возвращает storage.ErrUserNotFound,
+// если такого пользователя нет
+func (s *Storage) FindUserByUsername(ctx context.Context, username string) (int64, error) {
+	var telegramID int64
+
+	err := s.pool.QueryRow(
+		ctx,
+		`SELECT telegram_id FROM users WHERE LOWER(username) = LOWER($1)`,
+		username,
+	).Scan(&telegramID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, storage.ErrUserNotFound
+	} else if err != nil {
+		return 0, err
+	}
+
+	return telegramID, nil
+}
+
+// SaveShare выдаёт доступ к квизу или меняет роль
+func (s *Storage) SaveShare(ctx context.Context, share *models.QuizShareModel) error {
+	query := `
+	INSERT INTO quiz_shares (quiz_id, telegram_id, role, created_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (quiz_id, telegram_id) DO UPDATE SET role = EXCLUDED.role
+	`
+
+	_, err := s.pool.Exec(ctx, query, share.QuizID, share.TelegramID, share.Role, share.CreatedAt)
+
+	return err
+}
+
+// DeleteShare отзывает доступ к квизу
+func (s *Storage) DeleteShare(ctx context.Context, quizID string, telegramID int64) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM quiz_shares WHERE quiz_id = $1 AND telegram_id = $2`, quizID, telegramID)
+
+	return err
+}
+
+// GetShare возвращает доступ пользователя к квизу. Возвращает storage.ErrShareNotFound, если доступа нет
+func (s *Storage) GetShare(ctx context.Context, quizID string, telegramID int64) (*models.QuizShareModel, error) {
+	query := `
+	SELECT quiz_id, telegram_id, role, created_at FROM quiz_shares WHERE quiz_id = $1 AND telegram_id = $2
+	`
+
+	share := &models.QuizShareModel{}
+
+	err := s.pool.QueryRow(ctx, query, quizID, telegramID).Scan(
+		&share.QuizID,
+		&share.TelegramID,
+		&share.Role,
+		&share.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrShareNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return share, nil
+}
+
+// ListShares возвращает всех, кому открыт квиз, в порядке выдачи доступа
+func (s *Storage) ListShares(ctx context.Context, quizID string) ([]*models.QuizShareModel, error) {
+	query := `
+	SELECT sh.quiz_id, sh.telegram_id, COALESCE(u.username, ''), COALESCE(u.full_name, ''), sh.role, sh.created_at
+	FROM quiz_shares sh
+	LEFT JOIN users u ON u.telegram_id = sh.telegram_id
+	WHERE sh.quiz_id = $1
+	ORDER BY sh.created_at
+	`
+
+	rows, err := s.pool.Query(ctx, query, quizID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shares []*models.QuizShareModel
+
+	for rows.Next() {
+		share := &models.QuizShareModel{}
+
+		err = rows.Scan(
+			&share.QuizID,
+			&share.TelegramID,
+			&share.Username,
+			&share.FullName,
+			&share.Role,
+			&share.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		shares = append(shares, share)
+	}
+
+	return shares, rows.Err()
+}
+
+// ListSharedQuizzes возвращает чужие квизы, открытые пользователю, с количеством их запусков
+func (s *Storage) ListSharedQuizzes(ctx context.Context, telegramID int64, limit int) ([]*models.QuizModel, error) {
+	query := `
+	SELECT q.id, q.owner_id, q.title, q.questions_count, q.created_at,
+		(SELECT COUNT(*) FROM quiz_runs r WHERE r.quiz_id = q.id), sh.role
+	FROM quiz_shares sh
+	JOIN quizzes q ON q.id = sh.quiz_id
+	WHERE sh.telegram_id = $1 AND q.deleted_at IS NULL
+	ORDER BY q.created_at DESC
+	LIMIT $2
+	`
+
+	rows, err := s.pool.Query(ctx, query, telegramID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quizzes []*models.QuizModel
+
+	for rows.Next() {
+		quiz := &models.QuizModel{}
+
+		err = rows.Scan(
+			&quiz.ID,
+			&quiz.OwnerID,
+			&quiz.Title,
+			&quiz.QuestionsCount,
+			&quiz.CreatedAt,
+			&quiz.RunsCount,
+			&quiz.SharedRole,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		quizzes = append(quizzes, quiz)
+	}
+
+	return quizzes, rows.Err()
+}
+
+// SaveInvite сохраняет приглашение к квизу
+func (s *Storage) SaveInvite(ctx context.Context, invite *models.QuizInviteModel) error {
+	query := `
+	INSERT INTO quiz_invites (code, quiz_id, role, created_by, expires_at)
+	VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := s.pool.Exec(ctx, query, invite.Code, invite.QuizID, invite.Role, invite.CreatedBy, invite.ExpiresAt)
+
+	return err
+}
+
+// TakeInvite удаляет приглашение и возвращает его. Возвращает storage.ErrInviteNotFound,
+// если приглашения нет или оно истекло
+func (s *Storage) TakeInvite(ctx context.Context, code string, now time.Time) (*models.QuizInviteModel, error) {
+	query := `
+	DELETE FROM quiz_invites WHERE code = $1
+	RETURNING code, quiz_id, role, created_by, expires_at
+	`
+
+	invite := &models.QuizInviteModel{}
+
+	err := s.pool.QueryRow(ctx, query, code).Scan(
+		&invite.Code,
+		&invite.QuizID,
+		&invite.Role,
+		&invite.CreatedBy,
+		&invite.ExpiresAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrInviteNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !invite.ExpiresAt.After(now) {
+		return nil, storage.ErrInviteNotFound
+	}
+
+	return invite, nil
+}
+
+// IsLecturerApproved сообщает, подтверждена ли пользователю роль преподавателя
+func (s *Storage) IsLecturerApproved(ctx context.Context, telegramID int64) (bool, error) {
+	query := `
+	SELECT EXISTS (SELECT 1 FROM approved_lecturers WHERE telegram_id = $1)
+	`
+
+	var approved bool
+
+	err := s.pool.QueryRow(ctx, query, telegramID).Scan(&approved)
+
+	return approved, err
+}
+
+// ApproveLecturer добавляет пользователя в список подтверждённых преподавателей
+func (s *Storage) ApproveLecturer(ctx context.Context, telegramID int64, approvedBy int64) error {
+	query := `
+	INSERT INTO approved_lecturers (telegram_id, approved_by) VALUES ($1, $2)
+	ON CONFLICT (telegram_id) DO NOTHING
+	`
+
+	_, err := s.pool.Exec(ctx, query, telegramID, approvedBy)
+
+	return err
+}
+
+// RevokeLecturer удаляет пользователя из списка подтверждённых преподавателей
+func (s *Storage) RevokeLecturer(ctx context.Context, telegramID int64) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM approved_lecturers WHERE telegram_id = $1`, telegramID)
+
+	return err
+}
+
+// SaveLecturerCode сохраняет код подтверждения роли преподавателя
+func (s *Storage) SaveLecturerCode(ctx context.Context, code *models.LecturerCodeModel) error {
+	query := `
+	INSERT INTO lecturer_codes (code, created_by, expires_at) VALUES ($1, $2, $3)
+	`
+
+	_, err := s.pool.Exec(ctx, query, code.Code, code.CreatedBy, code.ExpiresAt)
+
+	return err
+}
+
+// TakeLecturerCode удаляет код подтверждения и возвращает его. Возвращает storage.ErrLecturerCodeNotFound,
+// если кода нет или он истёк
+func (s *Storage) TakeLecturerCode(ctx context.Context, code string, now time.Time) (*models.LecturerCodeModel, error) {
+	query := `
+	DELETE FROM lecturer_codes WHERE code = $1
+	RETURNING code, created_by, expires_at
+	`
+
+	lecturerCode := &models.LecturerCodeModel{}
+
+	err := s.pool.QueryRow(ctx, query, code).Scan(
+		&lecturerCode.Code,
+		&lecturerCode.CreatedBy,
+		&lecturerCode.ExpiresAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrLecturerCodeNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !lecturerCode.ExpiresAt.After(now) {
+		return nil, storage.ErrLecturerCodeNotFound
+	}
+
+	return lecturerCode, nil
+}
+
+// ListUsersByRole возвращает не более limit пользователей с ролью role, начиная с новых.
+// Пустая role — пользователи без роли
+func (s *Storage) ListUsersByRole(ctx context.Context, role string, limit int) ([]*models.UserModel, error) {
+	query := `
+	SELECT telegram_id, COALESCE(username, ''), COALESCE(full_name, ''), COALESCE(role, ''),
+		COALESCE(user_group, ''), created_at
+	FROM users
+	WHERE COALESCE(role, '') = $1
+	ORDER BY created_at DESC
+	LIMIT $2
+	`
+
+	rows, err := s.pool.Query(ctx, query, role, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*models.UserModel
+
+	for rows.Next() {
+		user := &models.UserModel{}
+
+		err = rows.Scan(&user.TelegramID, &user.Username, &user.FullName, &user.Role, &user.Group, &user.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// BanUser блокирует пользователя или обновляет причину блокировки
+func (s *Storage) BanUser(ctx context.Context, ban *models.BanModel) error {
+	query := `
+	INSERT INTO banned_users (telegram_id, reason, banned_by, created_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (telegram_id) DO UPDATE SET reason = EXCLUDED.reason, banned_by = EXCLUDED.banned_by
+	`
+
+	_, err := s.pool.Exec(ctx, query, ban.TelegramID, ban.Reason, ban.BannedBy, ban.CreatedAt)
+
+	return err
+}
+
+// UnbanUser снимает блокировку. Возвращает storage.ErrUserNotBanned, если пользователь не заблокирован
+func (s *Storage) UnbanUser(ctx context.Context, telegramID int64) error {
+	cmdTag, err := s.pool.Exec(ctx, `DELETE FROM banned_users WHERE telegram_id = $1`, telegramID)
+	if err != nil {
+		return err
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return storage.ErrUserNotBanned
+	}
+
+	return nil
+}
+
+// ListBans возвращает всех заблокированных пользователей
+func (s *Storage) ListBans(ctx context.Context) ([]*models.BanModel, error) {
+	query := `
+	SELECT b.telegram_id, COALESCE(u.username, ''), COALESCE(u.full_name, ''), b.reason, b.banned_by, b.created_at
+	FROM banned_users b
+	LEFT JOIN users u ON u.telegram_id = b.telegram_id
+	ORDER BY b.created_at DESC
+	`
+
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bans []*models.BanModel
+
+	for rows.Next() {
+		ban := &models.BanModel{}
+
+		err = rows.Scan(&ban.TelegramID, &ban.Username, &ban.FullName, &ban.Reason, &ban.BannedBy, &ban.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		bans = append(bans, ban)
+	}
+
+	return bans, rows.Err()
+}
+
+// GetBotStats возвращает общую статистику бота
+func (s *Storage) GetBotStats(ctx context.Context) (*models.BotStatsModel, error) {
+	stats := &models.BotStatsModel{UsersByRole: make(map[string]int)}
+
+	rows, err := s.pool.Query(ctx, `SELECT COALESCE(role, ''), COUNT(*) FROM users GROUP BY 1`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			role  string
+			count int
+		)
+
+		if err = rows.Scan(&role, &count); err != nil {
+			return nil, err
+		}
+
+		stats.UsersByRole[role] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT
+		(SELECT COUNT(*) FROM banned_users),
+		(SELECT COUNT(*) FROM quizzes WHERE deleted_at IS NULL),
+		(SELECT COUNT(*) FROM quiz_runs),
+		(SELECT COUNT(*) FROM quiz_runs WHERE finished_at IS NOT NULL)
+	`
+
+	err = s.pool.QueryRow(ctx, query).Scan(&stats.BannedUsers, &stats.Quizzes, &stats.Runs, &stats.FinishedRuns)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// CreateCourse сохраняет курс и заполняет его ID. Возвращает storage.ErrCourseAlreadyExists,
+// если у преподавателя уже есть курс с таким названием
+func (s *Storage) CreateCourse(ctx context.Context, course *models.CourseModel) error {
+	query := `
+	INSERT INTO courses (owner_id, title, invite_code, created_at) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (owner_id, title) DO NOTHING
+	RETURNING id
+	`
+
+	err := s.pool.QueryRow(ctx, query, course.OwnerID, course.Title, course.InviteCode, course.CreatedAt).
+		Scan(&course.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return storage.ErrCourseAlreadyExists
+	}
+
+	return err
+}
+
+// GetCourse возвращает курс по ID. Возвращает storage.ErrCourseNotFound, если курса нет
+func (s *Storage) GetCourse(ctx context.Context, id int64) (*models.CourseModel, error) {
+	return s.getCourse(ctx, `SELECT id, owner_id, title, invite_code, created_at FROM courses WHERE id = $1`, id)
+}
+
+// GetCourseByInvite возвращает курс по коду ссылки для записи. Возвращает storage.ErrCourseNotFound, если курса нет
+func (s *Storage) GetCourseByInvite(ctx context.Context, code string) (*models.CourseModel, error) {
+	return s.getCourse(ctx, `SELECT id, owner_id, title, invite_code, created_at FROM courses WHERE invite_code = $1`, code)
+}
+
+// getCourse возвращает один курс по запросу query
+func (s *Storage) getCourse(ctx context.Context, query string, arg any) (*models.CourseModel, error) {
+	course := &models.CourseModel{}
+
+	err := s.pool.QueryRow(ctx, query, arg).Scan(
+		&course.ID,
+		&course.OwnerID,
+		&course.Title,
+		&course.InviteCode,
+		&course.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrCourseNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+// ListCourses возвращает курсы преподавателя с количеством студентов и квизов
+func (s *Storage) ListCourses(ctx context.Context, ownerID int64) ([]*models.CourseModel, error) {
+	query := `
+	SELECT c.id, c.owner_id, c.title, c.invite_code, c.created_at,
+		(SELECT COUNT(*) FROM course_students cs WHERE cs.course_id = c.id),
+		(SELECT COUNT(*) FROM course_quizzes cq WHERE cq.course_id = c.id)
+	FROM courses c
+	WHERE c.owner_id = $1
+	ORDER BY c.created_at DESC
+	`
+
+	return s.listCourses(ctx, query, ownerID)
+}
+
+// ListEnrolledCourses возвращает курсы, на которые записан студент, с количеством квизов
+func (s *Storage) ListEnrolledCourses(ctx context.Context, telegramID int64) ([]*models.CourseModel, error) {
+	query := `
+	SELECT c.id, c.owner_id, c.title, c.invite_code, c.created_at,
+		(SELECT COUNT(*) FROM course_students cs WHERE cs.course_id = c.id),
+		(SELECT COUNT(*) FROM course_quizzes cq WHERE cq.course_id = c.id)
+	FROM courses c
+	JOIN course_students e ON e.course_id = c.id
+	WHERE e.telegram_id = $1
+	ORDER BY e.enrolled_at DESC
+	`
+
+	return s.listCourses(ctx, query, telegramID)
+}
+
+// listCourses возвращает курсы со счётчиками по запросу query
+func (s *Storage) listCourses(ctx context.Context, query string, arg any) ([]*models.CourseModel, error) {
+	rows, err := s.pool.Query(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var courses []*models.CourseModel
+
+	for rows.Next() {
+		course := &models.CourseModel{}
+
+		err = rows.Scan(
+			&course.ID,
+			&course.OwnerID,
+			&course.Title,
+			&course.InviteCode,
+			&course.CreatedAt,
+			&course.StudentsCount,
+			&course.QuizzesCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		courses = append(courses, course)
+	}
+
+	return courses, rows.Err()
+}
+
+// Enroll записывает студента на курс. Возвращает false, если он уже записан
+func (s *Storage) Enroll(ctx context.Context, courseID int64, telegramID int64, now time.Time) (bool, error) {
+	query := `
+	INSERT INTO course_students (course_id, telegram_id, enrolled_at) VALUES ($1, $2, $3)
+	ON CONFLICT (course_id, telegram_id) DO NOTHING
+	`
+
+	cmdTag, err := s.pool.Exec(ctx, query, courseID, telegramID, now)
+	if err != nil {
+		return false, err
+	}
+
+	return cmdTag.RowsAffected() > 0, nil
+}
+
+// EnrollGroup записывает на курс всех студентов учебной группы. Возвращает число новых записей
+func (s *Storage) EnrollGroup(ctx context.Context, courseID int64, group string, now time.Time) (int, error) {
+	query := `
+	INSERT INTO course_students (course_id, telegram_id, enrolled_at)
+	SELECT $1, telegram_id, $3 FROM users WHERE user_group = $2 AND role = 'student'
+	ON CONFLICT (course_id, telegram_id) DO NOTHING
+	`
+
+	cmdTag, err := s.pool.Exec(ctx, query, courseID, group, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(cmdTag.RowsAffected()), nil
+}
+
+// ListCourseStudents возвращает студентов курса по группе и ФИО
+func (s *Storage) ListCourseStudents(ctx context.Context, courseID int64) ([]*models.UserModel, error) {
+	query := `
+	SELECT cs.telegram_id, COALESCE(u.username, ''), COALESCE(u.full_name, ''), COALESCE(u.role, ''),
+		COALESCE(u.user_group, ''), cs.enrolled_at
+	FROM course_students cs
+	LEFT JOIN users u ON u.telegram_id = cs.telegram_id
+	WHERE cs.course_id = $1
+	ORDER BY u.user_group, u.full_name, cs.telegram_id
+	`
+
+	rows, err := s.pool.Query(ctx, query, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var students []*models.UserModel
+
+	for rows.Next() {
+		student := &models.UserModel{}
+
+		err = rows.Scan(
+			&student.TelegramID,
+			&student.Username,
+			&student.FullName,
+			&student.Role,
+			&student.Group,
+			&student.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		students = append(students, student)
+	}
+
+	return students, rows.Err()
+}
+
+// PublishQuiz публикует квиз в курсе. Возвращает false, если квиз уже опубликован в нём
+func (s *Storage) PublishQuiz(ctx context.Context, courseID int64, quizID string, now time.Time) (bool, error) {
+	query := `
+	INSERT INTO course_quizzes (course_id, quiz_id, published_at) VALUES ($1, $2, $3)
+	ON CONFLICT (course_id, quiz_id) DO NOTHING
+	`
+
+	cmdTag, err := s.pool.Exec(ctx, query, courseID, quizID, now)
+	if err != nil {
+		return false, err
+	}
+
+	return cmdTag.RowsAffected() > 0, nil
+}
+
+// ListCourseQuizzes возвращает квизы курса в порядке публикации с количеством их запусков.
+// Удалённые квизы остаются в списке, чтобы их результаты не пропали из журнала
+func (s *Storage) ListCourseQuizzes(ctx context.Context, courseID int64) ([]*models.QuizModel, error) {
+	query := `
+	SELECT q.id, q.owner_id, q.title, q.questions_count, q.created_at,
+		(SELECT COUNT(*) FROM quiz_runs r WHERE r.quiz_id = q.id), q.deleted_at IS NOT NULL
+	FROM course_quizzes cq
+	JOIN quizzes q ON q.id = cq.quiz_id
+	WHERE cq.course_id = $1
+	ORDER BY cq.published_at
+	`
+
+	rows, err := s.pool.Query(ctx, query, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quizzes []*models.QuizModel
+
+	for rows.Next() {
+		quiz := &models.QuizModel{}
+
+		err = rows.Scan(
+			&quiz.ID,
+			&quiz.OwnerID,
+			&quiz.Title,
+			&quiz.QuestionsCount,
+			&quiz.CreatedAt,
+			&quiz.RunsCount,
+			&quiz.Deleted,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		quizzes = append(quizzes, quiz)
+	}
+
+	return quizzes, rows.Err()
+}
+
+// QuizCourseAccess сообщает, опубликован ли квиз в курсах и записан ли пользователь на один из них
+func (s *Storage) QuizCourseAccess(ctx context.Context, quizID string, telegramID int64) (bool, bool, error) {
+	query := `
+	SELECT
+		EXISTS (SELECT 1 FROM course_quizzes WHERE quiz_id = $1),
+		EXISTS (
+			SELECT 1 FROM course_quizzes cq
+			JOIN course_students cs ON cs.course_id = cq.course_id
+			WHERE cq.quiz_id = $1 AND cs.telegram_id = $2
+		)
+	`
+
+	var published, enrolled bool
+
+	err := s.pool.QueryRow(ctx, query, quizID, telegramID).Scan(&published, &enrolled)
+
+	return published, enrolled, err
+}
+
+// ListCourseResults возвращает лучший балл и число попыток студентов курса по квизам курса
+func (s *Storage) ListCourseResults(ctx context.Context, courseID int64) ([]*models.CourseResultModel, error) {
+	query := `
+	SELECT rr.telegram_id, r.quiz_id, MAX(rr.score), COUNT(*)
+	FROM course_quizzes cq
+	JOIN quiz_runs r ON r.quiz_id = cq.quiz_id
+	JOIN run_results rr ON rr.run_id = r.id
+	JOIN course_students cs ON cs.course_id = cq.course_id AND cs.telegram_id = rr.telegram_id
+	WHERE cq.course_id = $1
+	GROUP BY rr.telegram_id, r.quiz_id
+	`
+
+	rows, err := s.pool.Query(ctx, query, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*models.CourseResultModel
+
+	for rows.Next() {
+		result := &models.CourseResultModel{}
+
+		err = rows.Scan(&result.TelegramID, &result.QuizID, &result.BestScore, &result.Attempts)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
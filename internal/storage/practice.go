@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+// PracticeStorage хранит результаты тренировок отдельно от официальных запусков.
+type PracticeStorage interface {
+	// SavePracticeResult сохраняет результат тренировки.
+	SavePracticeResult(ctx context.Context, result *models.PracticeModel) error
+}
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+// ErrQuizNotFound возвращается, если сохранённого квиза нет.
+var ErrQuizNotFound = errors.New("quiz not found")
+
+// QuizStorage хранит загруженные преподавателями квизы и историю их запусков.
+type QuizStorage interface {
+	// SaveQuiz сохраняет квиз.
+	SaveQuiz(ctx context.Context, quiz *models.QuizModel) error
+
+	// GetQuiz возвращает квиз по ID. Возвращает ErrQuizNotFound, если квиза нет.
+	GetQuiz(ctx context.Context, id string) (*models.QuizModel, error)
+
+	// ListQuizzes возвращает не более limit квизов преподавателя, начиная с новых.
+	ListQuizzes(ctx context.Context, ownerID int64, limit int) ([]*models.QuizModel, error)
+
+	// DeleteQuiz удаляет квиз из библиотеки. Запуски квиза и их итоги остаются в статистике студентов
+	// и журналах курсов.
+	DeleteQuiz(ctx context.Context, id string) error
+
+	// SaveRun сохраняет запуск квиза или обновляет уже сохранённый.
+	SaveRun(ctx context.Context, run *models.RunModel) error
+
+	// ListRuns возвращает не более limit запусков квиза, начиная с новых.
+	ListRuns(ctx context.Context, quizID string, limit int) ([]*models.RunModel, error)
+}
@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"context"
+)
+
+// RegistrationStorage хранит значения полей регистрации пользователя, чтобы не спрашивать их в каждом квизе.
+// Поля "name" и "group" хранятся в профиле пользователя (ФИО и группа), остальные — отдельно.
+type RegistrationStorage interface {
+	// GetUserFields возвращает все сохранённые поля пользователя. Незаполненные поля в результат не попадают.
+	GetUserFields(ctx context.Context, telegramID int64) (map[string]string, error)
+
+	// SaveUserField сохраняет значение поля, заменяя сохранённое раньше.
+	SaveUserField(ctx context.Context, telegramID int64, field, value string) error
+}
@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+// ReviewStorage хранит карточки интервального повторения студентов.
+type ReviewStorage interface {
+	// AddReviewCards добавляет карточки. Если карточка по вопросу уже есть,
+	// её повторения начинаются заново, а ошибка засчитывается.
+	AddReviewCards(ctx context.Context, cards []*models.ReviewCardModel) error
+
+	// GetDueReviewCards возвращает не более limit карточек студента, повторение которых наступило к now.
+	GetDueReviewCards(ctx context.Context, telegramID int64, now time.Time, limit int) ([]*models.ReviewCardModel, error)
+
+	// UpdateReviewCard сохраняет состояние карточки после повторения.
+	UpdateReviewCard(ctx context.Context, card *models.ReviewCardModel) error
+
+	// CountDueReviewCards возвращает количество карточек к повторению для каждого студента.
+	CountDueReviewCards(ctx context.Context, now time.Time) (map[int64]int, error)
+
+	// GetForgottenQuestions возвращает вопросы квизов преподавателя, в которых студенты ошибаются чаще всего.
+	GetForgottenQuestions(ctx context.Context, ownerID int64, limit int) ([]*models.ForgottenQuestionModel, error)
+}
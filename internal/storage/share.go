@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+var (
+	// ErrUserNotFound возвращается, если пользователь с таким username не писал боту.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrShareNotFound возвращается, если у пользователя нет доступа к квизу.
+	ErrShareNotFound = errors.New("share not found")
+	// ErrInviteNotFound возвращается, если приглашения нет, оно уже использовано или истекло.
+	ErrInviteNotFound = errors.New("invite not found")
+)
+
+// ShareStorage хранит доступ преподавателей и ассистентов к чужим квизам и приглашения к ним.
+type ShareStorage interface {
+	// SaveUsername запоминает username пользователя, чтобы владелец квиза мог пригласить его по нему.
+	SaveUsername(ctx context.Context, telegramID int64, username string) error
+
+	// FindUserByUsername возвращает Telegram ID пользователя по username без учёта регистра.
+	// Возвращает ErrUserNotFound, если такой пользователь не писал боту.
+	FindUserByUsername(ctx context.Context, username string) (int64, error)
+
+	// SaveShare выдаёт доступ к квизу или меняет роль уже выданного.
+	SaveShare(ctx context.Context, share *models.QuizShareModel) error
+
+	// DeleteShare отзывает доступ к квизу.
+	DeleteShare(ctx context.Context, quizID string, telegramID int64) error
+
+	// GetShare возвращает доступ пользователя к квизу. Возвращает ErrShareNotFound, если доступа нет.
+	GetShare(ctx context.Context, quizID string, telegramID int64) (*models.QuizShareModel, error)
+
+	// ListShares возвращает всех, кому открыт квиз, вместе с их username и ФИО.
+	ListShares(ctx context.Context, quizID string) ([]*models.QuizShareModel, error)
+
+	// ListSharedQuizzes возвращает не более limit чужих квизов, открытых пользователю, начиная с новых.
+	// Файлы квизов не загружаются, SharedRole заполняется.
+	ListSharedQuizzes(ctx context.Context, telegramID int64, limit int) ([]*models.QuizModel, error)
+
+	// SaveInvite сохраняет приглашение.
+	SaveInvite(ctx context.Context, invite *models.QuizInviteModel) error
+
+	// TakeInvite возвращает приглашение и удаляет его: приглашение одноразовое.
+	// Возвращает ErrInviteNotFound, если приглашения нет или оно истекло к моменту now.
+	TakeInvite(ctx context.Context, code string, now time.Time) (*models.QuizInviteModel, error)
+}
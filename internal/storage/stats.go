@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/letsssgooo/quizBot/internal/domain/models"
+)
+
+// ErrStudentNotFound возвращается, если студент не участвовал в запусках преподавателя.
+var ErrStudentNotFound = errors.New("student not found")
+
+// StatsStorage отдаёт сохранённые итоги запусков для личной статистики студента.
+// Во всех методах ownerID ограничивает выборку запусками квизов преподавателя и квизов, открытых ему
+// другими преподавателями, 0 — все запуски.
+type StatsStorage interface {
+	// GetStudentRuns возвращает результаты студента в завершённых запусках от старых к новым.
+	GetStudentRuns(ctx context.Context, telegramID, ownerID int64) ([]*models.StudentRunModel, error)
+
+	// GetStudentTopics возвращает ответы студента по темам вопросов.
+	GetStudentTopics(ctx context.Context, telegramID, ownerID int64) ([]*models.TopicAccuracyModel, error)
+
+	// FindStudents ищет участников запусков преподавателя по имени или ФИО. Пустой запрос возвращает всех.
+	FindStudents(ctx context.Context, ownerID int64, query string, limit int) ([]*models.StudentModel, error)
+
+	// GetStudent возвращает участника запусков преподавателя. Возвращает ErrStudentNotFound,
+	// если студент в них не участвовал.
+	GetStudent(ctx context.Context, ownerID, telegramID int64) (*models.StudentModel, error)
+}
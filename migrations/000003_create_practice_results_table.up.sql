@@ -0,0 +1,16 @@
+-- Результаты тренировок хранятся отдельно от статистики запусков
+CREATE TABLE IF NOT EXISTS practice_results (
+    id UUID PRIMARY KEY,
+    telegram_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    quiz_id UUID NOT NULL,
+    quiz_title VARCHAR(250) NOT NULL,
+    timed BOOLEAN NOT NULL DEFAULT FALSE,
+    score INTEGER NOT NULL,
+    max_score INTEGER NOT NULL,
+    correct_count INTEGER NOT NULL,
+    questions_count INTEGER NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS practice_results_telegram_id_idx ON practice_results (telegram_id);
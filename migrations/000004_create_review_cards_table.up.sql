@@ -0,0 +1,18 @@
+-- Карточки интервального повторения (SM-2): вопросы, на которых ошибся студент
+CREATE TABLE IF NOT EXISTS review_cards (
+    telegram_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    owner_id BIGINT NOT NULL, -- преподаватель, которому принадлежит квиз
+    quiz_id UUID NOT NULL,
+    quiz_title VARCHAR(250) NOT NULL,
+    question_idx INTEGER NOT NULL,
+    question JSONB NOT NULL, -- копия вопроса: квиз может измениться или быть удалён
+    ease_factor DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+    interval_days INTEGER NOT NULL DEFAULT 1,
+    repetitions INTEGER NOT NULL DEFAULT 0,
+    lapses INTEGER NOT NULL DEFAULT 1,
+    due_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (telegram_id, quiz_id, question_idx)
+);
+
+CREATE INDEX IF NOT EXISTS review_cards_due_at_idx ON review_cards (due_at);
+CREATE INDEX IF NOT EXISTS review_cards_owner_id_idx ON review_cards (owner_id);
@@ -0,0 +1,10 @@
+-- Состояния многошаговых диалогов: по одному на пользователя
+CREATE TABLE IF NOT EXISTS dialogs (
+    telegram_id BIGINT PRIMARY KEY,
+    state VARCHAR(50) NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}', -- данные шага диалога, например ID запуска
+    expires_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS dialogs_expires_at_idx ON dialogs (expires_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS quiz_runs;
+DROP TABLE IF EXISTS quizzes;
@@ -0,0 +1,24 @@
+-- Квизы, загруженные преподавателями. Файл хранится как есть, чтобы его можно было скачать без изменений
+CREATE TABLE IF NOT EXISTS quizzes (
+    id UUID PRIMARY KEY,
+    owner_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    title VARCHAR(250) NOT NULL,
+    file BYTEA NOT NULL,
+    questions_count INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS quizzes_owner_id_idx ON quizzes (owner_id);
+
+-- Запуски сохранённых квизов
+CREATE TABLE IF NOT EXISTS quiz_runs (
+    id UUID PRIMARY KEY,
+    quiz_id UUID NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+    owner_id BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    participants_count INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP -- NULL, пока запуск не завершён
+);
+
+CREATE INDEX IF NOT EXISTS quiz_runs_quiz_id_idx ON quiz_runs (quiz_id);
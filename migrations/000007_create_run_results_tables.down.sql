@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS run_questions;
+DROP TABLE IF EXISTS run_results;
+ALTER TABLE quiz_runs DROP COLUMN IF EXISTS csv;
@@ -0,0 +1,25 @@
+-- Итоги завершённых запусков: CSV для повторной выгрузки
+ALTER TABLE quiz_runs ADD COLUMN IF NOT EXISTS csv BYTEA;
+
+-- Результаты участников запусков
+CREATE TABLE IF NOT EXISTS run_results (
+    run_id UUID NOT NULL REFERENCES quiz_runs(id) ON DELETE CASCADE,
+    telegram_id BIGINT NOT NULL,
+    name VARCHAR(150) NOT NULL,
+    score INTEGER NOT NULL,
+    rank INTEGER NOT NULL,
+    correct_count INTEGER NOT NULL,
+    answered_count INTEGER NOT NULL,
+    PRIMARY KEY (run_id, telegram_id)
+);
+
+-- Ответы участников запусков по вопросам
+CREATE TABLE IF NOT EXISTS run_questions (
+    run_id UUID NOT NULL REFERENCES quiz_runs(id) ON DELETE CASCADE,
+    question_idx INTEGER NOT NULL,
+    question_text TEXT NOT NULL,
+    answered INTEGER NOT NULL,
+    correct INTEGER NOT NULL,
+    voided BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (run_id, question_idx)
+);
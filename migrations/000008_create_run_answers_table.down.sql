@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS run_results_telegram_id_idx;
+DROP TABLE IF EXISTS run_answers;
+ALTER TABLE run_results DROP COLUMN IF EXISTS max_score;
@@ -0,0 +1,16 @@
+-- Максимум баллов участника, чтобы считать процент
+ALTER TABLE run_results ADD COLUMN IF NOT EXISTS max_score INTEGER NOT NULL DEFAULT 0;
+
+-- Ответы каждого участника на вопросы запуска с темами вопросов для статистики студентов
+CREATE TABLE IF NOT EXISTS run_answers (
+    run_id UUID NOT NULL REFERENCES quiz_runs(id) ON DELETE CASCADE,
+    telegram_id BIGINT NOT NULL,
+    question_idx INTEGER NOT NULL,
+    topics TEXT[] NOT NULL DEFAULT '{}',
+    answered BOOLEAN NOT NULL,
+    correct BOOLEAN NOT NULL,
+    PRIMARY KEY (run_id, telegram_id, question_idx)
+);
+
+CREATE INDEX IF NOT EXISTS run_answers_telegram_id_idx ON run_answers (telegram_id);
+CREATE INDEX IF NOT EXISTS run_results_telegram_id_idx ON run_results (telegram_id);
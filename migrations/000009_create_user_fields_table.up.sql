@@ -0,0 +1,8 @@
+-- Значения полей регистрации пользователя (кроме ФИО и группы, которые хранятся в users)
+CREATE TABLE IF NOT EXISTS user_fields (
+    telegram_id BIGINT NOT NULL,
+    field VARCHAR(64) NOT NULL,
+    value VARCHAR(150) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (telegram_id, field)
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS quiz_invites;
+DROP TABLE IF EXISTS quiz_shares;
+
+DROP INDEX IF EXISTS users_username_idx;
+
+ALTER TABLE users DROP COLUMN IF EXISTS username;
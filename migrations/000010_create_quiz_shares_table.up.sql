@@ -0,0 +1,24 @@
+-- Username нужен, чтобы владелец квиза мог пригласить преподавателя по нему
+ALTER TABLE users ADD COLUMN IF NOT EXISTS username VARCHAR(32);
+
+CREATE INDEX IF NOT EXISTS users_username_idx ON users (LOWER(username));
+
+-- Доступ преподавателей и ассистентов к чужим квизам
+CREATE TABLE IF NOT EXISTS quiz_shares (
+    quiz_id UUID NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+    telegram_id BIGINT NOT NULL,
+    role VARCHAR(20) NOT NULL, -- viewer, runner или editor
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (quiz_id, telegram_id)
+);
+
+CREATE INDEX IF NOT EXISTS quiz_shares_telegram_id_idx ON quiz_shares (telegram_id);
+
+-- Одноразовые ссылки-приглашения к квизам
+CREATE TABLE IF NOT EXISTS quiz_invites (
+    code VARCHAR(64) PRIMARY KEY,
+    quiz_id UUID NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    created_by BIGINT NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS lecturer_codes;
+DROP TABLE IF EXISTS approved_lecturers;
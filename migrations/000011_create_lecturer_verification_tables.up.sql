@@ -0,0 +1,14 @@
+-- Преподаватели, которым роль подтверждена кодом от администратора.
+-- Список из конфигурации (LECTURER_IDS) в таблицу не попадает
+CREATE TABLE IF NOT EXISTS approved_lecturers (
+    telegram_id BIGINT PRIMARY KEY,
+    approved_by BIGINT NOT NULL,
+    approved_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Одноразовые коды подтверждения роли преподавателя
+CREATE TABLE IF NOT EXISTS lecturer_codes (
+    code VARCHAR(16) PRIMARY KEY,
+    created_by BIGINT NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
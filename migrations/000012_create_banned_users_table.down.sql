@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS users_role_idx;
+
+DROP TABLE IF EXISTS banned_users;
@@ -0,0 +1,9 @@
+-- Пользователи, заблокированные администратором. Их обновления бот не обрабатывает
+CREATE TABLE IF NOT EXISTS banned_users (
+    telegram_id BIGINT PRIMARY KEY,
+    reason VARCHAR(250) NOT NULL DEFAULT '',
+    banned_by BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS users_role_idx ON users (role);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS users_user_group_idx;
+
+DROP TABLE IF EXISTS course_quizzes;
+DROP TABLE IF EXISTS course_students;
+DROP TABLE IF EXISTS courses;
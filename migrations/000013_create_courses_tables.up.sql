@@ -0,0 +1,32 @@
+-- Курсы преподавателей. Студенты записываются на курс по ссылке с invite_code
+CREATE TABLE IF NOT EXISTS courses (
+    id SERIAL PRIMARY KEY,
+    owner_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    title VARCHAR(250) NOT NULL,
+    invite_code VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (owner_id, title)
+);
+
+-- Студенты, записанные на курсы
+CREATE TABLE IF NOT EXISTS course_students (
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    telegram_id BIGINT NOT NULL,
+    enrolled_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (course_id, telegram_id)
+);
+
+CREATE INDEX IF NOT EXISTS course_students_telegram_id_idx ON course_students (telegram_id);
+
+-- Квизы, опубликованные в курсах. В запуски такого квиза входят только студенты его курсов
+CREATE TABLE IF NOT EXISTS course_quizzes (
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    quiz_id UUID NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+    published_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (course_id, quiz_id)
+);
+
+CREATE INDEX IF NOT EXISTS course_quizzes_quiz_id_idx ON course_quizzes (quiz_id);
+
+-- Для записи на курс всей учебной группы
+CREATE INDEX IF NOT EXISTS users_user_group_idx ON users (user_group);
@@ -0,0 +1,2 @@
+DELETE FROM quizzes WHERE deleted_at IS NOT NULL;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS deleted_at;
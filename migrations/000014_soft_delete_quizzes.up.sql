@@ -0,0 +1,2 @@
+-- Удалённый квиз пропадает из библиотеки, но его запуски и итоги остаются в статистике студентов и журналах курсов
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP; -- NULL, пока квиз не удалён
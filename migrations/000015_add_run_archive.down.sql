@@ -0,0 +1,2 @@
+ALTER TABLE quiz_runs DROP COLUMN IF EXISTS starter_id;
+ALTER TABLE quiz_runs DROP COLUMN IF EXISTS archive;
@@ -0,0 +1,4 @@
+-- Завершённые запуски, выгруженные из памяти: состояние движка для отчётов, тренировок и перепроверки
+-- и тот, кто провёл запуск
+ALTER TABLE quiz_runs ADD COLUMN IF NOT EXISTS archive BYTEA;
+ALTER TABLE quiz_runs ADD COLUMN IF NOT EXISTS starter_id BIGINT;